@@ -20,6 +20,11 @@ type Command struct {
 var commands = []Command{
 	{Name: "help", Help: "Prints this help", Run: gonovate.HelpCmd},
 	{Name: "run", Help: "Runs the gonovate process", Run: gonovate.RunCmd},
+	{Name: "extract", Help: "Prints the found dependencies without looking up updates", Run: gonovate.ExtractCmd},
+	{Name: "explain", Help: "Explains which rules apply to a dependency", Run: gonovate.ExplainCmd},
+	{Name: "validate", Help: "Validates the configuration", Run: gonovate.ValidateCmd},
+	{Name: "config", Help: "Prints the merged configuration with the origin of each value", Run: gonovate.ConfigCmd},
+	{Name: "schema", Help: "Prints the JSON Schema of the configuration", Run: gonovate.SchemaCmd},
 }
 
 func main() {
@@ -0,0 +1,77 @@
+package gonovate
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/roemer/gonovate/pkg/config"
+)
+
+func ConfigCmd(args []string) error {
+	// The config command only consists of sub-commands
+	if len(args) < 1 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "Usage:")
+		fmt.Fprintln(os.Stderr, "  gonovate config print [flags]")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Commands:")
+		fmt.Fprintln(os.Stderr, "  print    Prints the merged configuration with the origin of each value")
+		if len(args) < 1 {
+			return fmt.Errorf("missing sub-command")
+		}
+		return fmt.Errorf("unknown sub-command '%s'", args[0])
+	}
+	return configPrintCmd(args[1:])
+}
+
+func configPrintCmd(args []string) error {
+	// Flags and help for the command
+	var verbose bool
+	var configFiles stringSliceFlag
+	var workingDirectory string
+	var projectDirectory string
+	flagSet := flag.NewFlagSet("config print", flag.ExitOnError)
+	flagSet.BoolVar(&verbose, "verbose", false, "The flag to set in order to get verbose output.")
+	flagSet.BoolVar(&verbose, "v", verbose, "Alias for -verbose.")
+	flagSet.Var(&configFiles, "config", "The path to the config file to read. Can be passed multiple times.")
+	flagSet.StringVar(&workingDirectory, "workDir", "", "The path to the working directory.")
+	flagSet.StringVar(&projectDirectory, "projectDir", "", "The path to a project which has its own config that should be merged.")
+	flagSet.Usage = func() { printCmdUsage(flagSet, "config print", "") }
+	flagSet.Parse(args)
+
+	// Create a logger (on stderr to keep the output clean)
+	logger := createLogger(os.Stderr, verbose)
+
+	// Change the working directory
+	if err := changeWorkingDirectory(logger, workingDirectory); err != nil {
+		return err
+	}
+
+	// Read the configuration
+	configLoader := config.NewConfigLoader(logger)
+	gonovateConfig, err := loadConfigFiles(configLoader, configFiles)
+	if err != nil {
+		return err
+	}
+
+	// Merge the config of the project (if any)
+	if projectDirectory != "" {
+		if err := changeWorkingDirectory(logger, projectDirectory); err != nil {
+			return err
+		}
+		if foundPath, err := config.HasProjectConfig(); err != nil {
+			return err
+		} else if foundPath != "" {
+			projectConfigFromFile, err := configLoader.Load(foundPath)
+			if err != nil {
+				return err
+			}
+			gonovateConfig.MergeWith(projectConfigFromFile)
+		} else {
+			logger.Warn(fmt.Sprintf("No config found in project directory '%s'", projectDirectory))
+		}
+	}
+
+	// Print the config
+	return gonovateConfig.PrintWithOrigins(os.Stdout)
+}
@@ -0,0 +1,131 @@
+package gonovate
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/roemer/gonovate/pkg/config"
+)
+
+func ExplainCmd(args []string) error {
+	// Flags and help for the command
+	var verbose bool
+	var configFiles stringSliceFlag
+	var workingDirectory string
+	var file string
+	var exclusive string
+	flagSet := flag.NewFlagSet("explain", flag.ExitOnError)
+	flagSet.BoolVar(&verbose, "verbose", false, "The flag to set in order to get verbose output.")
+	flagSet.BoolVar(&verbose, "v", verbose, "Alias for -verbose.")
+	flagSet.Var(&configFiles, "config", "The path to the config file to read. Can be passed multiple times.")
+	flagSet.StringVar(&workingDirectory, "workDir", "", "The path to the working directory.")
+	flagSet.StringVar(&file, "file", "", "Only explain the dependency in files matching this path or pattern.")
+	flagSet.StringVar(&exclusive, "exclusive", "", "The same exclusive criterias as used for the run command to also explain those rules.")
+	flagSet.StringVar(&exclusive, "e", exclusive, "Alias for -exclusive.")
+	flagSet.Usage = func() { printCmdUsage(flagSet, "explain", "<dependencyName>") }
+	flagSet.Parse(args)
+
+	// The dependency name is mandatory
+	if flagSet.NArg() != 1 {
+		flagSet.Usage()
+		return fmt.Errorf("exactly one dependency name is required")
+	}
+	dependencyName := flagSet.Arg(0)
+
+	// Create a logger (on stderr to keep the output clean)
+	logger := createLogger(os.Stderr, verbose)
+
+	// Change the working directory
+	if err := changeWorkingDirectory(logger, workingDirectory); err != nil {
+		return err
+	}
+
+	// Read the configuration
+	gonovateConfig, err := loadConfigFiles(config.NewConfigLoader(logger), configFiles)
+	if err != nil {
+		return err
+	}
+	// Add the rules from the exclusive flag at the end, like the run command does
+	gonovateConfig.Rules = append(gonovateConfig.Rules, parseExclusiveRules(logger, exclusive)...)
+
+	// Collect the dependencies and search for the desired one(s)
+	allDependencies, err := extractDependencies(logger, gonovateConfig)
+	if err != nil {
+		return err
+	}
+	matchingDependencies := []*common.Dependency{}
+	for _, dependency := range allDependencies {
+		if dependency.Name != dependencyName {
+			continue
+		}
+		if file != "" {
+			if ok, err := common.FilePathMatchesPattern(dependency.FilePath, file); err != nil {
+				return err
+			} else if !ok {
+				continue
+			}
+		}
+		matchingDependencies = append(matchingDependencies, dependency)
+	}
+	if len(matchingDependencies) == 0 {
+		return fmt.Errorf("no dependency found with name '%s'", dependencyName)
+	}
+
+	// Explain the dependencies
+	for i, dependency := range matchingDependencies {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := writeDependencyExplanation(os.Stdout, gonovateConfig, dependency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes the explanation of all the rules for the given dependency.
+func writeDependencyExplanation(out io.Writer, gonovateConfig *config.GonovateConfig, dependency *common.Dependency) error {
+	managerId := ""
+	if dependency.ManagerInfo != nil {
+		managerId = dependency.ManagerInfo.ManagerId
+	}
+	fmt.Fprintf(out, "Dependency '%s' in '%s' (manager: %s, version: %s)\n", dependency.Name, dependency.FilePath, managerId, dependency.Version)
+
+	// Explain the rules
+	for _, explanation := range gonovateConfig.ExplainDependency(dependency) {
+		status := "matched"
+		if !explanation.Matched {
+			status = "rejected"
+		}
+		fmt.Fprintf(out, "  rule #%d [%s] %s\n", explanation.Index, status, explanation.Describe())
+		if !explanation.Matched {
+			fmt.Fprintf(out, "      rejected by %s: %s\n", explanation.RejectedBy, strings.Join(explanation.RejectedValues, ", "))
+			if explanation.RejectedBy == "updateTypes" || explanation.RejectedBy == "excludeUpdateTypes" {
+				fmt.Fprintf(out, "      (rules with update types are only evaluated for the found updates)\n")
+			}
+		}
+		for _, contribution := range explanation.Contributions {
+			fmt.Fprintf(out, "      %s\n", contribution)
+		}
+	}
+
+	// Show the final result
+	if err := gonovateConfig.ApplyToDependency(dependency); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "  result:")
+	fmt.Fprintf(out, "      datasource: %s\n", dependency.Datasource)
+	fmt.Fprintf(out, "      versioning: %s\n", dependency.Versioning)
+	fmt.Fprintf(out, "      updateTypes: %v\n", dependency.UpdateTypes)
+	if dependency.Skip != nil && *dependency.Skip {
+		fmt.Fprintf(out, "      skip: true (%s)\n", dependency.SkipReason)
+	}
+	if dependency.GroupName != "" {
+		fmt.Fprintf(out, "      groupName: %s\n", dependency.GroupName)
+	}
+	return nil
+}
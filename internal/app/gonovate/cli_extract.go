@@ -0,0 +1,108 @@
+package gonovate
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/roemer/gonovate/pkg/config"
+)
+
+func ExtractCmd(args []string) error {
+	// Flags and help for the command
+	var verbose bool
+	var configFiles stringSliceFlag
+	var workingDirectory string
+	var outputFormat string
+	var outputFile string
+	flagSet := flag.NewFlagSet("extract", flag.ExitOnError)
+	flagSet.BoolVar(&verbose, "verbose", false, "The flag to set in order to get verbose output.")
+	flagSet.BoolVar(&verbose, "v", verbose, "Alias for -verbose.")
+	flagSet.Var(&configFiles, "config", "The path to the config file to read. Can be passed multiple times.")
+	flagSet.StringVar(&workingDirectory, "workDir", "", "The path to the working directory.")
+	flagSet.StringVar(&outputFormat, "format", "json", "The format of the output. Valid values are: json, table")
+	flagSet.StringVar(&outputFile, "output", "", "The path to a file to write the output to. Defaults to stdout.")
+	flagSet.StringVar(&outputFile, "o", outputFile, "Alias for -output.")
+	flagSet.Usage = func() { printCmdUsage(flagSet, "extract", "") }
+	flagSet.Parse(args)
+
+	// Validate the format early
+	if outputFormat != "json" && outputFormat != "table" {
+		return fmt.Errorf("invalid format '%s'", outputFormat)
+	}
+
+	// Create a logger (on stderr to keep the output clean)
+	logger := createLogger(os.Stderr, verbose)
+
+	// Change the working directory
+	if err := changeWorkingDirectory(logger, workingDirectory); err != nil {
+		return err
+	}
+
+	// Read the configuration
+	gonovateConfig, err := loadConfigFiles(config.NewConfigLoader(logger), configFiles)
+	if err != nil {
+		return err
+	}
+
+	// Collect the dependencies
+	dependencies, err := extractDependencies(logger, gonovateConfig)
+	if err != nil {
+		return err
+	}
+
+	// Apply the config to the dependencies
+	for _, dependency := range dependencies {
+		if err := gonovateConfig.ApplyToDependency(dependency); err != nil {
+			return err
+		}
+	}
+
+	// Prepare the output
+	var out io.Writer = os.Stdout
+	if outputFile != "" {
+		file, err := os.Create(outputFile)
+		if err != nil {
+			return fmt.Errorf("failed creating output file '%s': %w", outputFile, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	// Write the dependencies
+	if outputFormat == "table" {
+		return writeDependencyTable(out, dependencies)
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dependencies)
+}
+
+// Writes the dependencies as a human readable table.
+func writeDependencyTable(out io.Writer, dependencies []*common.Dependency) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tMANAGER\tNAME\tVERSION\tDATASOURCE\tSKIPPED")
+	for _, dependency := range dependencies {
+		managerId := ""
+		if dependency.ManagerInfo != nil {
+			managerId = dependency.ManagerInfo.ManagerId
+		}
+		version := dependency.Version
+		if dependency.HasDigest() {
+			version = fmt.Sprintf("%s@%s", version, dependency.Digest)
+		}
+		skipped := "no"
+		if dependency.Skip != nil && *dependency.Skip {
+			skipped = "yes"
+			if dependency.SkipReason != "" {
+				skipped = fmt.Sprintf("yes (%s)", dependency.SkipReason)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", dependency.FilePath, managerId, dependency.Name, version, dependency.Datasource, skipped)
+	}
+	return tw.Flush()
+}
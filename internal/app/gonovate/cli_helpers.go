@@ -3,8 +3,15 @@ package gonovate
 import (
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"strings"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/roemer/gonovate/pkg/config"
+	"github.com/roemer/gonovate/pkg/logging"
+	"github.com/samber/lo"
 )
 
 type stringSliceFlag []string
@@ -31,3 +38,171 @@ func printCmdUsage(flagSet *flag.FlagSet, commandName, nonFlagArgs string) {
 	fmt.Fprintln(os.Stderr, "Flags:")
 	flagSet.PrintDefaults()
 }
+
+// Creates the logger used by the commands.
+func createLogger(out io.Writer, verbose bool) *slog.Logger {
+	desiredLogLevel := lo.Ternary(verbose, slog.LevelDebug, slog.LevelInfo)
+	logger := slog.New(logging.NewReadableTextHandler(out, &logging.ReadableTextHandlerOptions{Level: desiredLogLevel}))
+	logger.Debug(fmt.Sprintf("Initialized logger with level: %s", desiredLogLevel))
+	return logger
+}
+
+// Changes the working directory if one is given.
+func changeWorkingDirectory(logger *slog.Logger, workingDirectory string) error {
+	if workingDirectory != "" && workingDirectory != "." {
+		logger.Debug(fmt.Sprintf("Changing working directory to: %s", workingDirectory))
+		if err := os.Chdir(workingDirectory); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Loads the main configuration and merges all additional configurations into it.
+func loadConfigFiles(configLoader *config.ConfigLoader, configFiles []string) (*config.GonovateConfig, error) {
+	// Read the main configuration
+	mainConfig := ""
+	if len(configFiles) > 0 {
+		mainConfig = configFiles[0]
+	}
+	gonovateConfig, err := configLoader.Load(mainConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge additional config files
+	if len(configFiles) > 1 {
+		for _, configFile := range configFiles[1:] {
+			additionalConfig, err := configLoader.Load(configFile)
+			if err != nil {
+				return nil, err
+			}
+			gonovateConfig.MergeWith(additionalConfig)
+		}
+	}
+	return gonovateConfig, nil
+}
+
+// Loops thru the managers of the config and collects the dependencies from the files in the current directory.
+func extractDependencies(logger *slog.Logger, gonovateConfig *config.GonovateConfig) ([]*common.Dependency, error) {
+	// Warn when no managers are defined
+	if len(gonovateConfig.Managers) == 0 {
+		logger.Warn("No managers found to process")
+	}
+
+	allDependencies := []*common.Dependency{}
+	logger.Info(fmt.Sprintf("Searching for dependencies in %d manager(s)", len(gonovateConfig.Managers)))
+	for _, managerConfig := range gonovateConfig.Managers {
+		// Get the appropriate manager from the config
+		manager, err := gonovateConfig.GetManager(managerConfig.Id, managerConfig.Type, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		// Skip the manager if it is disabled
+		if manager.Settings().Disabled != nil && *manager.Settings().Disabled {
+			logger.Info(fmt.Sprintf("Manager '%s': Skip as it is disabled", manager.Id()))
+			continue
+		}
+		logger.Info(fmt.Sprintf("Processing Manager '%s' (%s)", manager.Id(), manager.Type()))
+
+		// Search for the files relevant for the manager
+		managerFilePatterns := manager.Settings().FilePatterns
+		logger.Debug(fmt.Sprintf("Searching files with %d pattern(s)", len(managerFilePatterns)))
+		matchingFiles, err := common.SearchFiles(".", managerFilePatterns, gonovateConfig.IgnorePatterns)
+		logger.Debug(fmt.Sprintf("Found %d matching file(s)", len(matchingFiles)))
+		if err != nil {
+			return nil, err
+		}
+
+		// Loop thru the files and collect the dependencies
+		dependenciesInManager := []*common.Dependency{}
+		for _, matchingFile := range matchingFiles {
+			logger.Debug(fmt.Sprintf("Processing file '%s'", matchingFile))
+			// Extract the dependencies for this file
+			currDependencies, err := manager.ExtractDependencies(matchingFile)
+			if err != nil {
+				return nil, err
+			}
+			logger.Debug(fmt.Sprintf("Found %d dependencies in file", len(currDependencies)))
+			dependenciesInManager = append(dependenciesInManager, currDependencies...)
+		}
+		// Add all dependencies
+		logger.Info(fmt.Sprintf("Found %d dependencies in manager", len(dependenciesInManager)))
+		allDependencies = append(allDependencies, dependenciesInManager...)
+	}
+	logger.Info(fmt.Sprintf("Found %d dependencies in total", len(allDependencies)))
+	return allDependencies, nil
+}
+
+// Parses the exclusive flag into rules that need to be added with the top priority.
+func parseExclusiveRules(logger *slog.Logger, exclusive string) []*config.Rule {
+	topPriorityRules := []*config.Rule{}
+	if exclusive == "" {
+		return topPriorityRules
+	}
+	// Rule that disables all managers and skips all dependencies
+	exclusiveRule := &config.Rule{
+		ManagerConfig:    &config.ManagerConfig{},
+		DependencyConfig: &config.DependencyConfig{},
+		GeneratedFrom:    "exclusive (skip all others)",
+	}
+	// Rule that enables the desired manager or dependency
+	inclusiveRule := &config.Rule{
+		Matches:          &config.RuleMatch{},
+		ManagerConfig:    &config.ManagerConfig{Disabled: common.FalsePtr},
+		DependencyConfig: &config.DependencyConfig{Skip: common.FalsePtr},
+		GeneratedFrom:    "exclusive (enable matching)",
+	}
+	// Check the given values and assign them appropriate match
+	hasManagerExclusive := false
+	hasDependencyExclusive := false
+	pairs := strings.Split(exclusive, "|")
+	for _, pair := range pairs {
+		values := strings.SplitN(pair, "=", 2)
+		if len(values) < 2 {
+			continue
+		}
+		key := values[0]
+		value := strings.TrimSpace(values[1])
+		if value == "" {
+			// Skip empty values
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "dependency":
+			hasDependencyExclusive = true
+			inclusiveRule.Matches.DependencyNames = []string{value}
+		case "datasource":
+			hasDependencyExclusive = true
+			inclusiveRule.Matches.Datasources = []common.DatasourceType{common.DatasourceType(value)}
+		case "file":
+			hasDependencyExclusive = true
+			inclusiveRule.Matches.Files = []string{value}
+		case "manager":
+			hasManagerExclusive = true
+			inclusiveRule.Matches.Managers = []string{value}
+		case "managertype":
+			hasManagerExclusive = true
+			inclusiveRule.Matches.ManagerTypes = []common.ManagerType{common.ManagerType(value)}
+		}
+
+	}
+	// Make sure at least one value matched
+	if hasManagerExclusive || hasDependencyExclusive {
+		if hasManagerExclusive {
+			// There is a rule that enables a specific manager, so disable all others
+			exclusiveRule.ManagerConfig.Disabled = common.TruePtr
+		}
+		if hasDependencyExclusive {
+			// There is a rule that enables a specific dependency, so skip all others
+			exclusiveRule.DependencyConfig.Skip = common.TruePtr
+			exclusiveRule.DependencyConfig.SkipReason = "Exclusive"
+		}
+		topPriorityRules = append(topPriorityRules, exclusiveRule)
+		topPriorityRules = append(topPriorityRules, inclusiveRule)
+	} else {
+		logger.Warn(fmt.Sprintf("Exclusive flag passed but with incompatible values: %s", exclusive))
+	}
+	return topPriorityRules
+}
@@ -1,6 +1,7 @@
 package gonovate
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -10,11 +11,12 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/roemer/gonovate/pkg/common"
 	"github.com/roemer/gonovate/pkg/config"
-	"github.com/roemer/gonovate/pkg/logging"
 	"github.com/roemer/gonovate/pkg/platforms"
+	"github.com/roemer/gonovate/pkg/releasenotes"
 	"github.com/samber/lo"
 )
 
@@ -27,6 +29,8 @@ func RunCmd(args []string) error {
 	var platformOverride string
 	var projectsOverride string
 	var exclusive string
+	var dryRun bool
+	var planDir string
 	flagSet := flag.NewFlagSet("run", flag.ExitOnError)
 	flagSet.BoolVar(&verbose, "verbose", false, "The flag to set in order to get verbose output.")
 	flagSet.BoolVar(&verbose, "v", verbose, "Alias for -verbose.")
@@ -37,112 +41,31 @@ func RunCmd(args []string) error {
 	flagSet.StringVar(&projectsOverride, "projects", "", "Allows specifying one or multiple projects to process. Comma-separated values.")
 	flagSet.StringVar(&exclusive, "exclusive", "", "Allows defining criterias for exclusive updating. The format is: key1=value1|key2=value2\nValid Keys are: dependency, datasource, file, manager, managerType")
 	flagSet.StringVar(&exclusive, "e", exclusive, "Alias for -exclusive.")
+	flagSet.BoolVar(&dryRun, "dryRun", false, "Only searches for updates and writes a plan (gonovate-plan.json and gonovate-plan.md) without changing anything.")
+	flagSet.StringVar(&planDir, "planDir", "", "The path to the directory where the plan is written to in dry-run mode. Defaults to the working directory.")
 	flagSet.Usage = func() { printCmdUsage(flagSet, "run", "") }
 	flagSet.Parse(args)
 
 	// Create a logger
-	desiredLogLevel := lo.Ternary(verbose, slog.LevelDebug, slog.LevelInfo)
-	logger := slog.New(logging.NewReadableTextHandler(os.Stdout, &logging.ReadableTextHandlerOptions{Level: desiredLogLevel}))
-	logger.Debug(fmt.Sprintf("Initialized logger with level: %s", desiredLogLevel))
+	logger := createLogger(os.Stdout, verbose)
 	logger.Info("Starting gonovate run")
 
 	// Parse the exclusive flag
-	topPriorityRules := []*config.Rule{}
-	if exclusive != "" {
-		// Rule that disables all managers and skips all dependencies
-		exclusiveRule := &config.Rule{
-			ManagerConfig:    &config.ManagerConfig{},
-			DependencyConfig: &config.DependencyConfig{},
-		}
-		// Rule that enables the desired manager or dependency
-		inclusiveRule := &config.Rule{
-			Matches:          &config.RuleMatch{},
-			ManagerConfig:    &config.ManagerConfig{Disabled: common.FalsePtr},
-			DependencyConfig: &config.DependencyConfig{Skip: common.FalsePtr},
-		}
-		// Check the given values and assign them appropriate match
-		hasManagerExclusive := false
-		hasDependencyExclusive := false
-		pairs := strings.Split(exclusive, "|")
-		for _, pair := range pairs {
-			values := strings.SplitN(pair, "=", 2)
-			if len(values) < 2 {
-				continue
-			}
-			key := values[0]
-			value := strings.TrimSpace(values[1])
-			if value == "" {
-				// Skip empty values
-				continue
-			}
-			switch strings.ToLower(key) {
-			case "dependency":
-				hasDependencyExclusive = true
-				inclusiveRule.Matches.DependencyNames = []string{value}
-			case "datasource":
-				hasDependencyExclusive = true
-				inclusiveRule.Matches.Datasources = []common.DatasourceType{common.DatasourceType(value)}
-			case "file":
-				hasDependencyExclusive = true
-				inclusiveRule.Matches.Files = []string{value}
-			case "manager":
-				hasManagerExclusive = true
-				inclusiveRule.Matches.Managers = []string{value}
-			case "managertype":
-				hasManagerExclusive = true
-				inclusiveRule.Matches.ManagerTypes = []common.ManagerType{common.ManagerType(value)}
-			}
-
-		}
-		// Make sure at least one value matched
-		if hasManagerExclusive || hasDependencyExclusive {
-			if hasManagerExclusive {
-				// There is a rule that enables a specific manager, so disable all others
-				exclusiveRule.ManagerConfig.Disabled = common.TruePtr
-			}
-			if hasDependencyExclusive {
-				// There is a rule that enables a specific dependency, so skip all others
-				exclusiveRule.DependencyConfig.Skip = common.TruePtr
-				exclusiveRule.DependencyConfig.SkipReason = "Exclusive"
-			}
-			topPriorityRules = append(topPriorityRules, exclusiveRule)
-			topPriorityRules = append(topPriorityRules, inclusiveRule)
-		} else {
-			logger.Warn(fmt.Sprintf("Exclusive flag passed but with incompatible values: %s", exclusive))
-		}
-	}
+	topPriorityRules := parseExclusiveRules(logger, exclusive)
 
 	// Change the working directory
-	if workingDirectory != "" && workingDirectory != "." {
-		logger.Debug(fmt.Sprintf("Changing working directory to: %s", workingDirectory))
-		if err := os.Chdir(workingDirectory); err != nil {
-			return err
-		}
+	if err := changeWorkingDirectory(logger, workingDirectory); err != nil {
+		return err
 	}
 
 	// Prepare the config loader
 	configLoader := config.NewConfigLoader(logger)
-	// Read the main configuration
-	mainConfig := ""
-	if len(configFiles) > 0 {
-		mainConfig = configFiles[0]
-	}
-	gonovateConfig, err := configLoader.Load(mainConfig)
+	// Read the configuration
+	gonovateConfig, err := loadConfigFiles(configLoader, configFiles)
 	if err != nil {
 		return err
 	}
 
-	// Merge additional config files
-	if len(configFiles) > 1 {
-		for _, configFile := range configFiles[1:] {
-			additionalConfig, err := configLoader.Load(configFile)
-			if err != nil {
-				return err
-			}
-			gonovateConfig.MergeWith(additionalConfig)
-		}
-	}
-
 	// Process overrides
 	if platformOverride != "" {
 		if gonovateConfig.Platform == nil {
@@ -173,9 +96,25 @@ func RunCmd(args []string) error {
 	logger.Debug(fmt.Sprintf("Using cache directory: %s", cacheDir))
 	gonovateCache := common.NewGonovateCache(cacheDir, logger)
 
+	// Prepare the plan for the dry-run
+	updatePlan := &common.UpdatePlan{}
+	if dryRun {
+		// Make sure it is absolute to the working directory
+		planDir, err = filepath.Abs(planDir)
+		if err != nil {
+			return fmt.Errorf("failed making planDir '%s' absolute: %w", planDir, err)
+		}
+		logger.Info(fmt.Sprintf("Running in dry-run mode, the plan will be written to: %s", planDir))
+	}
+
 	// Prepare the platform
 	platformSettings := gonovateConfig.ToCommonPlatformSettings(logger)
 	platformSettings.GitLabUserIdCache = gonovateCache.GitLabUserIdCache
+	gitHubApp, err := gonovateConfig.ToGitHubApp()
+	if err != nil {
+		return err
+	}
+	platformSettings.GitHubApp = gitHubApp
 	platform, err := platforms.GetPlatform(platformSettings)
 	if err != nil {
 		return err
@@ -189,6 +128,10 @@ func RunCmd(args []string) error {
 	if gonovateConfig.Platform.Inplace != nil {
 		isInplace = *gonovateConfig.Platform.Inplace
 	}
+	// A dry-run does not clone or discover projects and can only plan the project in the working directory
+	if dryRun && !isInplace {
+		return fmt.Errorf("dry-run is only supported for inplace projects, set 'platform.inplace' and run gonovate in the project")
+	}
 	if isInplace {
 		// If no project is passed, use a fake project
 		if len(gonovateConfig.Platform.Projects) == 0 {
@@ -203,6 +146,18 @@ func RunCmd(args []string) error {
 		for _, p := range gonovateConfig.Platform.Projects {
 			projects = append(projects, &common.Project{Path: p})
 		}
+		// Add the discovered projects
+		if gonovateConfig.Platform.IsAutodiscoverEnabled() {
+			discoveredProjects, err := discoverProjects(logger, platform, gonovateConfig.Platform.Autodiscover)
+			if err != nil {
+				return err
+			}
+			for _, discoveredProject := range discoveredProjects {
+				if !slices.ContainsFunc(projects, func(p *common.Project) bool { return p.Path == discoveredProject.Path }) {
+					projects = append(projects, discoveredProject)
+				}
+			}
+		}
 	}
 	if len(projects) == 0 {
 		logger.Warn("No projects found to process")
@@ -261,55 +216,21 @@ func RunCmd(args []string) error {
 		} else {
 			logger.Debug("Using inplace project")
 		}
-
-		// Warn when no managers are defined but continue (to perform the cleanup)
-		if len(projectConfig.Managers) == 0 {
-			logger.Warn("No managers found to process")
+		semanticCommits := useSemanticCommits(logger, projectConfig)
+		if semanticCommits && projectConfig.Platform.CommitMessagePrefix != "" {
+			logger.Warn("The commitMessagePrefix is ignored as semantic commits are used")
 		}
 
-		// Loop thru the managers and collect the dependencies
-		allDependencies := []*common.Dependency{}
-		logger.Info(fmt.Sprintf("Searching for dependencies in %d manager(s)", len(projectConfig.Managers)))
-		for _, managerConfig := range projectConfig.Managers {
-			// Get the appropriate manager from the config
-			manager, err := projectConfig.GetManager(managerConfig.Id, managerConfig.Type, logger)
-			if err != nil {
-				return err
-			}
-
-			// Skip the manager if it is disabled
-			if manager.Settings().Disabled != nil && *manager.Settings().Disabled {
-				logger.Info(fmt.Sprintf("Manager '%s': Skip as it is disabled", manager.Id()))
-				continue
-			}
-			logger.Info(fmt.Sprintf("Processing Manager '%s' (%s)", manager.Id(), manager.Type()))
-
-			// Search for the files relevant for the manager
-			managerFilePatterns := manager.Settings().FilePatterns
-			logger.Debug(fmt.Sprintf("Searching files with %d pattern(s)", len(managerFilePatterns)))
-			matchingFiles, err := common.SearchFiles(".", managerFilePatterns, gonovateConfig.IgnorePatterns)
-			logger.Debug(fmt.Sprintf("Found %d matching file(s)", len(matchingFiles)))
-			if err != nil {
-				return err
-			}
-
-			// Loop thru the files and collect the dependencies
-			dependenciesInManager := []*common.Dependency{}
-			for _, matchingFile := range matchingFiles {
-				logger.Debug(fmt.Sprintf("Processing file '%s'", matchingFile))
-				// Extract the dependencies for this file
-				currDependencies, err := manager.ExtractDependencies(matchingFile)
-				if err != nil {
-					return err
-				}
-				logger.Debug(fmt.Sprintf("Found %d dependencies in file", len(currDependencies)))
-				dependenciesInManager = append(dependenciesInManager, currDependencies...)
-			}
-			// Add all dependencies
-			logger.Info(fmt.Sprintf("Found %d dependencies in manager", len(dependenciesInManager)))
-			allDependencies = append(allDependencies, dependenciesInManager...)
+		// Collect the dependencies (continue even without managers to perform the cleanup)
+		allDependencies, err := extractDependencies(logger, projectConfig)
+		if err != nil {
+			return err
 		}
-		logger.Info(fmt.Sprintf("Found %d dependencies in total", len(allDependencies)))
+
+		// With the dashboard or in a dry-run, lookup errors are collected and shown instead of aborting
+		dashboardEnabled := projectConfig.Platform.DependencyDashboard != nil && *projectConfig.Platform.DependencyDashboard
+		collectLookupErrors := dashboardEnabled || dryRun
+		lookupErrors := []*platforms.DashboardLookupError{}
 
 		// Search for updates for the dependencies
 		logger.Info("Searching for dependency updates")
@@ -332,7 +253,7 @@ func RunCmd(args []string) error {
 			}
 
 			// Lookup the correct datasource
-			ds, err := projectConfig.GetDatasource(dependency.Datasource, logger, gonovateCache.ReleaseCache)
+			ds, err := projectConfig.GetDatasource(dependency.Datasource, logger, gonovateCache.ReleaseCache, gitHubApp)
 			if err != nil {
 				return err
 			}
@@ -340,7 +261,12 @@ func RunCmd(args []string) error {
 			// Search for new releases
 			newReleases, err := ds.SearchDependencyUpdates(dependency)
 			if err != nil {
-				return err
+				if !collectLookupErrors {
+					return err
+				}
+				logger.Warn(fmt.Sprintf("Failed searching updates: %s", err.Error()))
+				lookupErrors = append(lookupErrors, &platforms.DashboardLookupError{Dependency: dependency, Error: err})
+				continue
 			}
 			if len(newReleases) > 0 {
 				logger.Debug(fmt.Sprintf("Found %d new release(s) for dependency", len(newReleases)))
@@ -356,32 +282,48 @@ func RunCmd(args []string) error {
 
 		// Group the dependencies which have updates according to group names
 		updateGroups := []*common.UpdateGroup{}
+		groupedBranches := map[*common.Dependency][]string{}
 		for _, dependencyWithUpdate := range updateDependencies {
-			dependency := dependencyWithUpdate.Dependency
+			sourceDependency := dependencyWithUpdate.Dependency
 			newRelease := dependencyWithUpdate.NewRelease
+			// Apply the rules that depend on the update (like update types)
+			dependency := projectConfig.ApplyToDependencyUpdate(sourceDependency, newRelease)
+			if dependency.Skip != nil && *dependency.Skip {
+				logger.Info(fmt.Sprintf("Skipping %s update of '%s' to '%s'", newRelease.UpdateType, dependency.Name, newRelease.VersionString))
+				continue
+			}
+			dependencyWithUpdate.Dependency = dependency
+			separateUpdateTypes := dependency.SeparateUpdateTypes != nil && *dependency.SeparateUpdateTypes
 			// Build the title
-			title, err := common.BuildTitle(&common.TitleBuilderSettings{
-				TitleTemplate:  dependency.TitleTemplate,
-				DependencyName: dependency.Name,
-				GroupName:      dependency.GroupName,
-				NewRelease:     newRelease,
-			})
+			titleSettings := &common.TitleBuilderSettings{
+				TitleTemplate:       dependency.TitleTemplate,
+				DependencyName:      dependency.Name,
+				GroupName:           dependency.GroupName,
+				NewRelease:          newRelease,
+				SeparateUpdateTypes: separateUpdateTypes,
+			}
+			if semanticCommits {
+				titleSettings.SemanticCommitType = dependency.SemanticCommitType
+				titleSettings.SemanticCommitScope = dependency.SemanticCommitScope
+			}
+			title, err := common.BuildTitle(titleSettings)
 			if err != nil {
 				return err
 			}
 			// Build the branch name
 			branchName, err := common.BuildBranchName(&common.BranchNameBuilderSettings{
-				BranchNameTemplate: dependency.BranchNameTemplate,
-				BaseBranch:         projectConfig.Platform.BaseBranch,
-				DependencyName:     dependency.Name,
-				GroupName:          dependency.GroupName,
-				NewRelease:         newRelease,
+				BranchNameTemplate:  dependency.BranchNameTemplate,
+				BaseBranch:          projectConfig.Platform.BaseBranch,
+				DependencyName:      dependency.Name,
+				GroupName:           dependency.GroupName,
+				NewRelease:          newRelease,
+				SeparateUpdateTypes: separateUpdateTypes,
 			})
 			if err != nil {
 				return err
 			}
-			// Add the prefixes
-			if projectConfig.Platform.CommitMessagePrefix != "" {
+			// Add the prefixes (semantic commits already have their own)
+			if projectConfig.Platform.CommitMessagePrefix != "" && !semanticCommits {
 				title = projectConfig.Platform.CommitMessagePrefix + title
 			}
 			if projectConfig.Platform.BranchPrefix != "" {
@@ -391,13 +333,9 @@ func RunCmd(args []string) error {
 			// Check if such a group already exists
 			idx := slices.IndexFunc(updateGroups, func(g *common.UpdateGroup) bool { return g.BranchName == branchName })
 			if idx >= 0 {
-				// Check if the same dependency with another update type already exists in the group
-				existingDependencyIndex := slices.IndexFunc(updateGroups[idx].Dependencies, func(d *common.DependencyWithUpdate) bool {
-					return d.Dependency == dependency
-				})
-				// If it does, abort
-				if existingDependencyIndex >= 0 {
-					return fmt.Errorf("conflicting updates found for dependency '%s' in group '%s'", dependency.Name, branchName)
+				// Check if the same dependency with another update type already exists in the group and if so, abort
+				if slices.Contains(groupedBranches[sourceDependency], branchName) {
+					return fmt.Errorf("conflicting updates found for dependency '%s' in group '%s', consider setting 'separateUpdateTypes'", dependency.Name, branchName)
 				}
 
 				// Else just add the dependency to the existing group
@@ -406,132 +344,468 @@ func RunCmd(args []string) error {
 				updateGroups[idx].Labels = lo.Uniq(append(updateGroups[idx].Labels, dependency.Labels...))
 				// Merge the reviewers
 				updateGroups[idx].Reviewers = lo.Uniq(append(updateGroups[idx].Reviewers, dependency.Reviewers...))
+				// The group needs an approval if any of the dependencies needs one
+				updateGroups[idx].RequiresApproval = updateGroups[idx].RequiresApproval || (dependency.RequireApproval != nil && *dependency.RequireApproval)
+				// The group is only merged automatically if all the dependencies allow it
+				updateGroups[idx].Automerge = updateGroups[idx].Automerge && (dependency.Automerge != nil && *dependency.Automerge)
+				updateGroups[idx].PlatformAutomerge = updateGroups[idx].PlatformAutomerge && (dependency.PlatformAutomerge == nil || *dependency.PlatformAutomerge)
+				if updateGroups[idx].AutomergeStrategy == "" {
+					updateGroups[idx].AutomergeStrategy = dependency.AutomergeStrategy
+				}
+				if updateGroups[idx].BodyTemplate == "" {
+					updateGroups[idx].BodyTemplate = dependency.PrBodyTemplate
+				}
 			} else {
 				// Create the group
 				newGroup := &common.UpdateGroup{
-					Title:        title,
-					BranchName:   branchName,
-					Dependencies: []*common.DependencyWithUpdate{dependencyWithUpdate},
-					Labels:       dependency.Labels,
-					Reviewers:    dependency.Reviewers,
+					Title:             title,
+					BranchName:        branchName,
+					Dependencies:      []*common.DependencyWithUpdate{dependencyWithUpdate},
+					Labels:            dependency.Labels,
+					Reviewers:         dependency.Reviewers,
+					BodyTemplate:      dependency.PrBodyTemplate,
+					RequiresApproval:  dependency.RequireApproval != nil && *dependency.RequireApproval,
+					Automerge:         dependency.Automerge != nil && *dependency.Automerge,
+					AutomergeStrategy: dependency.AutomergeStrategy,
+					PlatformAutomerge: dependency.PlatformAutomerge == nil || *dependency.PlatformAutomerge,
 				}
 				updateGroups = append(updateGroups, newGroup)
 			}
+			groupedBranches[sourceDependency] = append(groupedBranches[sourceDependency], branchName)
 		}
 		logger.Info(fmt.Sprintf("Created %d group(s) with dependency updates", len(updateGroups)))
 
-		// Loop thru the groups
-		for _, updateGroup := range updateGroups {
-			logger.Info(fmt.Sprintf("Processing group '%s' with %d dependencies", updateGroup.Title, len(updateGroup.Dependencies)))
+		if dryRun {
+			// Only add the updates to the plan
+			logger.Info("Dry-run: adding the updates to the plan without applying them")
+			projectPlan := updatePlan.AddProject(project.Path, updateGroups)
+			for _, lookupError := range lookupErrors {
+				projectPlan.AddLookupError(lookupError.Dependency, lookupError.Error)
+			}
+		} else {
+			// List the PRs/MRs of gonovate once as they are needed for the declined updates and the limits
+			pullRequests, err := listPullRequests(platform, project, hasProject, projectConfig, updateGroups)
+			if err != nil {
+				return err
+			}
+			// Remove the updates which were declined by closing their PR/MR
+			updateGroups, err = filterDeclinedUpdates(logger, platform, project, updateGroups, pullRequests)
+			if err != nil {
+				return err
+			}
+			// Prepare the fetcher for the release notes shown in the PRs/MRs and on the dashboard
+			releaseNotesFetcher := releasenotes.NewFetcher(logger, projectConfig.HostRules)
+			// Hold back the groups which are not approved yet
+			approvedGroups, approvals, err := filterApprovedGroups(logger, platform, project, hasProject, projectConfig, releaseNotesFetcher, updateGroups)
+			if err != nil {
+				return err
+			}
+			// Apply the updates and cleanup the platform
+			if err := applyUpdateGroups(logger, platform, project, hasProject, projectConfig, releaseNotesFetcher, approvedGroups, pullRequests); err != nil {
+				return err
+			}
+			// Update the dashboard (it is also needed to approve updates)
+			if (dashboardEnabled || len(approvals) > 0) && hasProject {
+				if err := updateDependencyDashboard(logger, platform, project, projectConfig, allDependencies, lookupErrors, approvals); err != nil {
+					return err
+				}
+			}
+		}
 
-			// Prepare the platform for a new changeset
-			logger.Debug("Prepaparing for changes")
-			if err := platform.PrepareForChanges(updateGroup); err != nil {
+		// Cleanup the working directory
+		if oldWorkdir != "" {
+			if err := os.Chdir(oldWorkdir); err != nil {
 				return err
 			}
+		}
+		if err := os.RemoveAll(platforms.ClonePath); err != nil {
+			return err
+		}
+	}
 
-			// Apply the changes
-			for _, dependencyWithUpdate := range updateGroup.Dependencies {
-				dependency := dependencyWithUpdate.Dependency
-				newRelease := dependencyWithUpdate.NewRelease
-				logger.Info(fmt.Sprintf("Updating '%s' from '%s' to '%s'", dependency.Name, dependency.Version, newRelease.VersionString))
-				// Get the manager config the for the manager that created the dependency
-				managerConfig := projectConfig.GetManagerConfigById(dependency.ManagerInfo.ManagerId)
+	// Write the plan
+	if dryRun {
+		if err := writeUpdatePlan(logger, planDir, updatePlan); err != nil {
+			return err
+		}
+	}
 
-				// Get the appropriate manager from the config
-				manager, err := projectConfig.GetManager(managerConfig.Id, managerConfig.Type, logger)
-				if err != nil {
-					return err
-				}
+	logger.Info("Gonovate finished successfully")
+
+	return nil
+}
+
+// Discovers the projects on the platform.
+func discoverProjects(logger *slog.Logger, platform platforms.IPlatform, autodiscoverConfig *config.AutodiscoverConfig) ([]*common.Project, error) {
+	autodiscoverPlatform, ok := platform.(platforms.IAutodiscoverPlatform)
+	if !ok {
+		return nil, fmt.Errorf("platform '%s' does not support autodiscover", platform.Type())
+	}
+	logger.Info("Discovering projects on the platform")
+	discoveredProjects, err := platforms.DiscoverProjects(autodiscoverPlatform, autodiscoverConfig.ToAutodiscoverSettings())
+	if err != nil {
+		return nil, err
+	}
+	logger.Info(fmt.Sprintf("Discovered %d project(s)", len(discoveredProjects)))
+	for _, project := range discoveredProjects {
+		logger.Debug(fmt.Sprintf("Discovered project '%s'", project.Path))
+	}
+	return discoveredProjects, nil
+}
+
+// Returns true if the commits and titles should follow the conventional commits.
+// In auto mode, this is detected from the recent commits of the project.
+func useSemanticCommits(logger *slog.Logger, projectConfig *config.GonovateConfig) bool {
+	switch projectConfig.Platform.SemanticCommits {
+	case common.SEMANTIC_COMMITS_ENABLED:
+		return true
+	case common.SEMANTIC_COMMITS_AUTO:
+		detected, err := common.DetectSemanticCommits()
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Could not detect semantic commits, disabling them: %s", err.Error()))
+			return false
+		}
+		logger.Debug(fmt.Sprintf("Detected semantic commits: %t", detected))
+		return detected
+	}
+	return false
+}
+
+// Removes the updates from the groups which were declined by closing a PR/MR without merging it.
+// Groups without any update left are removed. If wanted, the declining PRs/MRs get a comment on how to undo it.
+func filterDeclinedUpdates(logger *slog.Logger, platform platforms.IPlatform, project *common.Project, updateGroups []*common.UpdateGroup, pullRequests []*platforms.PullRequestInfo) ([]*common.UpdateGroup, error) {
+	if len(pullRequests) == 0 {
+		return updateGroups, nil
+	}
+	declinedUpdates := platforms.ParseDeclinedUpdates(pullRequests)
+
+	filteredGroups := []*common.UpdateGroup{}
+	decliningPullRequests := map[int64]*platforms.PullRequestInfo{}
+	for _, updateGroup := range updateGroups {
+		updateGroup.Dependencies = lo.Filter(updateGroup.Dependencies, func(dependencyWithUpdate *common.DependencyWithUpdate, _ int) bool {
+			pullRequest := declinedUpdates.Find(dependencyWithUpdate.Dependency.Name, dependencyWithUpdate.NewRelease)
+			if pullRequest == nil {
+				return true
+			}
+			logger.Info(fmt.Sprintf("Skipping update of '%s' to '%s' as it was declined in %s", dependencyWithUpdate.Dependency.Name, dependencyWithUpdate.NewRelease.VersionString, pullRequest.Url))
+			decliningPullRequests[pullRequest.Number] = pullRequest
+			return false
+		})
+		if len(updateGroup.Dependencies) > 0 {
+			filteredGroups = append(filteredGroups, updateGroup)
+		}
+	}
 
-				// Apply the update to the dependency
-				if err := manager.ApplyDependencyUpdate(dependency, newRelease); err != nil {
+	// Explain on the closed PRs/MRs how to undo it
+	pullRequestPlatform, ok := platform.(platforms.IPullRequestPlatform)
+	if !ok {
+		return filteredGroups, nil
+	}
+	for _, pullRequest := range decliningPullRequests {
+		if err := pullRequestPlatform.AddPullRequestComment(project, pullRequest.Number, platforms.DeclinedMarker, platforms.DeclinedComment); err != nil {
+			return nil, err
+		}
+	}
+	return filteredGroups, nil
+}
+
+// Returns the groups that do not need an approval or were approved on the dependency dashboard
+// and the approval states of all groups that need an approval.
+func filterApprovedGroups(logger *slog.Logger, platform platforms.IPlatform, project *common.Project, hasProject bool, projectConfig *config.GonovateConfig, releaseNotesFetcher *releasenotes.Fetcher, updateGroups []*common.UpdateGroup) ([]*common.UpdateGroup, []*platforms.DashboardApproval, error) {
+	if !slices.ContainsFunc(updateGroups, func(g *common.UpdateGroup) bool { return g.RequiresApproval }) {
+		return updateGroups, nil, nil
+	}
+
+	// Read the approvals from the dashboard
+	approvedBranches := []string{}
+	hasDashboard := false
+	if dashboardPlatform, ok := platform.(platforms.IDashboardPlatform); !ok {
+		logger.Warn(fmt.Sprintf("Platform '%s' does not support a dependency dashboard, updates that need an approval are skipped", platform.Type()))
+	} else if !hasProject {
+		logger.Warn("No project defined, updates that need an approval are skipped")
+	} else {
+		body, err := dashboardPlatform.GetDashboardBody(project)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed reading the dependency dashboard: %w", err)
+		}
+		approvedBranches = platforms.ParseApprovedBranches(body)
+		hasDashboard = true
+	}
+
+	approvedGroups := []*common.UpdateGroup{}
+	approvals := []*platforms.DashboardApproval{}
+	for _, updateGroup := range updateGroups {
+		if !updateGroup.RequiresApproval {
+			approvedGroups = append(approvedGroups, updateGroup)
+			continue
+		}
+		approved := slices.Contains(approvedBranches, updateGroup.BranchName)
+		// Link the release notes on the dashboard
+		if hasDashboard {
+			fetchReleaseNotes(logger, releaseNotesFetcher, updateGroup)
+		}
+		approvals = append(approvals, &platforms.DashboardApproval{
+			Title:        updateGroup.Title,
+			BranchName:   updateGroup.BranchName,
+			Approved:     approved,
+			ReleaseNotes: lo.FlatMap(updateGroup.Dependencies, func(d *common.DependencyWithUpdate, _ int) []*common.ReleaseNotes { return d.ReleaseNotes }),
+		})
+		if approved {
+			approvedGroups = append(approvedGroups, updateGroup)
+		} else {
+			logger.Info(fmt.Sprintf("Group '%s' is waiting for an approval on the dependency dashboard", updateGroup.Title))
+		}
+	}
+	return approvedGroups, approvals, nil
+}
+
+// Applies the updates of the groups with the platform and cleans up the platform afterwards.
+// The existing PRs/MRs of gonovate are used for the limits.
+func applyUpdateGroups(logger *slog.Logger, platform platforms.IPlatform, project *common.Project, hasProject bool, projectConfig *config.GonovateConfig, releaseNotesFetcher *releasenotes.Fetcher, updateGroups []*common.UpdateGroup, pullRequests []*platforms.PullRequestInfo) error {
+	// Prepare the limits for PRs/MRs
+	limiter := createPullRequestLimiter(logger, platform, hasProject, projectConfig, pullRequests)
+
+	// Loop thru the groups
+	for _, updateGroup := range updateGroups {
+		logger.Info(fmt.Sprintf("Processing group '%s' with %d dependencies", updateGroup.Title, len(updateGroup.Dependencies)))
+
+		// Leave branches alone which were modified by someone else
+		if isModified, err := platform.IsModified(updateGroup); err != nil {
+			return err
+		} else if isModified {
+			logger.Warn(fmt.Sprintf("Skipping group: branch '%s' was modified by someone else", updateGroup.BranchName))
+			if modifiedPlatform, ok := platform.(platforms.IModifiedBranchPlatform); ok && hasProject {
+				if err := modifiedPlatform.MarkModified(project, updateGroup); err != nil {
 					return err
 				}
+			}
+			continue
+		}
 
-				// Run Post-Upgrade replacements
-				hasPostUpgradeReplacements := len(dependency.PostUpgradeReplacements) > 0
-				if hasPostUpgradeReplacements {
-					// Prepare the values for replacements
-					replacementValues := map[string]string{
-						"version": newRelease.VersionString,
-					}
-					maps.Copy(replacementValues, newRelease.AdditionalData)
-					// Read the file
-					fileContentBytes, err := os.ReadFile(dependency.FilePath)
-					if err != nil {
-						return err
-					}
-					fileContent := string(fileContentBytes)
-					// Apply the replacements
-					for _, reStr := range dependency.PostUpgradeReplacements {
-						re := regexp.MustCompile(reStr)
-						fileContent, _ = common.ReplaceMatchesInRegex(re, fileContent, replacementValues)
-					}
-					// Write the file with the changes
-					if err := os.WriteFile(dependency.FilePath, []byte(fileContent), os.ModePerm); err != nil {
-						return err
-					}
-				}
+		// Check the limits (the group is kept for the cleanup so nothing existing is removed)
+		if limiter != nil {
+			if canPublish, reason := limiter.CanPublish(updateGroup.BranchName); !canPublish {
+				logger.Info(fmt.Sprintf("Skipping group: %s", reason))
+				continue
 			}
+		}
+
+		// Prepare the platform for a new changeset
+		logger.Debug("Prepaparing for changes")
+		if err := platform.PrepareForChanges(updateGroup); err != nil {
+			return err
+		}
 
-			// Submit
-			logger.Debug("Submitting the changes")
-			if err := platform.SubmitChanges(updateGroup); err != nil {
+		// Apply the changes
+		for _, dependencyWithUpdate := range updateGroup.Dependencies {
+			dependency := dependencyWithUpdate.Dependency
+			newRelease := dependencyWithUpdate.NewRelease
+			logger.Info(fmt.Sprintf("Updating '%s' from '%s' to '%s'", dependency.Name, dependency.Version, newRelease.VersionString))
+			// Get the manager config the for the manager that created the dependency
+			managerConfig := projectConfig.GetManagerConfigById(dependency.ManagerInfo.ManagerId)
+
+			// Get the appropriate manager from the config
+			manager, err := projectConfig.GetManager(managerConfig.Id, managerConfig.Type, logger)
+			if err != nil {
 				return err
 			}
 
-			// Check if there is a differente to a remote branch
-			if isNewOrChanged, err := platform.IsNewOrChanged(updateGroup); err != nil {
+			// Apply the update to the dependency
+			if err := manager.ApplyDependencyUpdate(dependency, newRelease); err != nil {
 				return err
-			} else if !isNewOrChanged {
-				logger.Info("Branch on remote exists and already has the same changes, skipping publish")
-			} else {
-				// Publish
-				logger.Debug("Publishing the changes")
-				if err := platform.PublishChanges(updateGroup); err != nil {
-					return err
-				}
 			}
 
-			// Notify
-			if hasProject {
-				// Only notify if a project was defined, otherwise we do not know where to notify
-				logger.Debug("Notifying the project about the changes")
-				if err := platform.NotifyChanges(project, updateGroup); err != nil {
+			// Run Post-Upgrade replacements
+			hasPostUpgradeReplacements := len(dependency.PostUpgradeReplacements) > 0
+			if hasPostUpgradeReplacements {
+				// Prepare the values for replacements
+				replacementValues := map[string]string{
+					"version": newRelease.VersionString,
+				}
+				maps.Copy(replacementValues, newRelease.AdditionalData)
+				// Read the file
+				fileContentBytes, err := os.ReadFile(dependency.FilePath)
+				if err != nil {
+					return err
+				}
+				fileContent := string(fileContentBytes)
+				// Apply the replacements
+				for _, reStr := range dependency.PostUpgradeReplacements {
+					re := regexp.MustCompile(reStr)
+					fileContent, _ = common.ReplaceMatchesInRegex(re, fileContent, replacementValues)
+				}
+				// Write the file with the changes
+				if err := os.WriteFile(dependency.FilePath, []byte(fileContent), os.ModePerm); err != nil {
 					return err
 				}
 			}
+		}
 
-			// Reset
-			logger.Debug("Resetting to the base branch")
-			if err := platform.ResetToBase(projectConfig.Platform.BaseBranch); err != nil {
-				return err
-			}
+		// Submit
+		logger.Debug("Submitting the changes")
+		if err := platform.SubmitChanges(updateGroup); err != nil {
+			return err
 		}
 
-		// Cleanup the platform (eg. unused PRs/MRs)
-		if err := platform.Cleanup(&platforms.PlatformCleanupSettings{
-			Project:      project,
-			UpdateGroups: updateGroups,
-			BaseBranch:   projectConfig.Platform.BaseBranch,
-			BranchPrefix: projectConfig.Platform.BranchPrefix,
-		}); err != nil {
+		// Check if there is a differente to a remote branch
+		isNewOrChanged, err := platform.IsNewOrChanged(updateGroup)
+		if err != nil {
 			return err
+		} else if !isNewOrChanged {
+			logger.Info("Branch on remote exists and already has the same changes, skipping publish")
+		} else {
+			// Publish
+			logger.Debug("Publishing the changes")
+			if err := platform.PublishChanges(updateGroup); err != nil {
+				return err
+			}
+			if limiter != nil {
+				limiter.Published(updateGroup.BranchName)
+			}
 		}
 
-		// Cleanup the working directory
-		if oldWorkdir != "" {
-			if err := os.Chdir(oldWorkdir); err != nil {
+		// Notify
+		if hasProject {
+			// Only notify if a project was defined, otherwise we do not know where to notify
+			if err := buildPullRequestBody(logger, releaseNotesFetcher, updateGroup); err != nil {
+				return err
+			}
+			logger.Debug("Notifying the project about the changes")
+			if err := platform.NotifyChanges(project, updateGroup); err != nil {
 				return err
 			}
+
+			// Merge the branch if it was not changed in this run. This is also done with the auto-merge of the platform
+			// as it cannot be enabled on some platforms if the PR/MR can already be merged.
+			if updateGroup.Automerge && !isNewOrChanged {
+				if automergePlatform, ok := platform.(platforms.IAutomergePlatform); ok {
+					logger.Debug("Merging the changes if they are ready")
+					if _, err := automergePlatform.MergeIfReady(project, updateGroup); err != nil {
+						return err
+					}
+				} else if !updateGroup.PlatformAutomerge {
+					logger.Warn(fmt.Sprintf("Platform '%s' does not support merging automatically", platform.Type()))
+				}
+			}
 		}
-		if err := os.RemoveAll(platforms.ClonePath); err != nil {
+
+		// Reset
+		logger.Debug("Resetting to the base branch")
+		if err := platform.ResetToBase(projectConfig.Platform.BaseBranch); err != nil {
 			return err
 		}
 	}
 
-	logger.Info("Gonovate finished successfully")
+	// Cleanup the platform (eg. unused PRs/MRs)
+	return platform.Cleanup(&platforms.PlatformCleanupSettings{
+		Project:      project,
+		UpdateGroups: updateGroups,
+		BaseBranch:   projectConfig.Platform.BaseBranch,
+		BranchPrefix: projectConfig.Platform.BranchPrefix,
+	})
+}
+
+// Fetches the release notes of the updates in the group which were not fetched yet.
+// Failing to fetch release notes is not fatal, the update is just shown without them.
+func fetchReleaseNotes(logger *slog.Logger, releaseNotesFetcher *releasenotes.Fetcher, updateGroup *common.UpdateGroup) {
+	for _, dependencyWithUpdate := range updateGroup.Dependencies {
+		if dependencyWithUpdate.ReleaseNotes != nil {
+			continue
+		}
+		releaseNotes, err := releaseNotesFetcher.Fetch(dependencyWithUpdate.Dependency, dependencyWithUpdate.NewRelease)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Failed fetching release notes: %s", err.Error()))
+			continue
+		}
+		dependencyWithUpdate.ReleaseNotes = releaseNotes
+	}
+}
 
+// Fetches the release notes of the updates in the group and renders the body of the PR/MR.
+func buildPullRequestBody(logger *slog.Logger, releaseNotesFetcher *releasenotes.Fetcher, updateGroup *common.UpdateGroup) error {
+	fetchReleaseNotes(logger, releaseNotesFetcher, updateGroup)
+	body, err := common.BuildPullRequestBody(updateGroup)
+	if err != nil {
+		return err
+	}
+	updateGroup.Body = body
+	return nil
+}
+
+// Lists the open and closed PRs/MRs of gonovate. Returns nil if there are no updates or the platform does not support PRs/MRs.
+func listPullRequests(platform platforms.IPlatform, project *common.Project, hasProject bool, projectConfig *config.GonovateConfig, updateGroups []*common.UpdateGroup) ([]*platforms.PullRequestInfo, error) {
+	pullRequestPlatform, ok := platform.(platforms.IPullRequestPlatform)
+	if !ok || !hasProject || len(updateGroups) == 0 {
+		return nil, nil
+	}
+	pullRequests, err := pullRequestPlatform.ListPullRequests(project, projectConfig.Platform.BranchPrefix, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing the PRs/MRs: %w", err)
+	}
+	return pullRequests, nil
+}
+
+// Creates the limiter for PRs/MRs from the existing PRs/MRs if limits are configured. Returns nil if there are no limits or they cannot be checked.
+func createPullRequestLimiter(logger *slog.Logger, platform platforms.IPlatform, hasProject bool, projectConfig *config.GonovateConfig, pullRequests []*platforms.PullRequestInfo) *platforms.PullRequestLimiter {
+	if projectConfig.Platform.PrConcurrentLimit <= 0 && projectConfig.Platform.PrHourlyLimit <= 0 {
+		return nil
+	}
+	if _, ok := platform.(platforms.IPullRequestPlatform); !ok {
+		logger.Warn(fmt.Sprintf("Platform '%s' does not support PR/MR limits", platform.Type()))
+		return nil
+	}
+	if !hasProject {
+		logger.Warn("No project defined, PR/MR limits are ignored")
+		return nil
+	}
+	return platforms.NewPullRequestLimiter(pullRequests, projectConfig.Platform.PrConcurrentLimit, projectConfig.Platform.PrHourlyLimit, time.Now())
+}
+
+// Creates or updates the dependency dashboard issue if the platform supports it.
+func updateDependencyDashboard(logger *slog.Logger, platform platforms.IPlatform, project *common.Project, projectConfig *config.GonovateConfig, dependencies []*common.Dependency, lookupErrors []*platforms.DashboardLookupError, approvals []*platforms.DashboardApproval) error {
+	dashboardPlatform, ok := platform.(platforms.IDashboardPlatform)
+	if !ok {
+		logger.Warn(fmt.Sprintf("Platform '%s' does not support a dependency dashboard", platform.Type()))
+		return nil
+	}
+	logger.Info("Updating the dependency dashboard")
+	dashboardData := &platforms.DashboardData{
+		Dependencies: dependencies,
+		LookupErrors: lookupErrors,
+		Approvals:    approvals,
+	}
+	// Get the open PRs/MRs
+	if pullRequestPlatform, ok := platform.(platforms.IPullRequestPlatform); ok {
+		openPullRequests, err := pullRequestPlatform.ListPullRequests(project, projectConfig.Platform.BranchPrefix, false)
+		if err != nil {
+			return fmt.Errorf("failed listing the open PRs/MRs: %w", err)
+		}
+		dashboardData.OpenPullRequests = openPullRequests
+	}
+	title := lo.CoalesceOrEmpty(projectConfig.Platform.DependencyDashboardTitle, platforms.DefaultDashboardTitle)
+	if err := dashboardPlatform.UpsertDashboard(project, title, platforms.BuildDashboardBody(dashboardData)); err != nil {
+		return fmt.Errorf("failed updating the dependency dashboard: %w", err)
+	}
+	return nil
+}
+
+// Writes the plan as json and markdown into the given directory.
+func writeUpdatePlan(logger *slog.Logger, planDir string, updatePlan *common.UpdatePlan) error {
+	if err := os.MkdirAll(planDir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed creating plan directory '%s': %w", planDir, err)
+	}
+	jsonBytes, err := json.MarshalIndent(updatePlan, "", "  ")
+	if err != nil {
+		return err
+	}
+	jsonPath := filepath.Join(planDir, "gonovate-plan.json")
+	if err := os.WriteFile(jsonPath, jsonBytes, os.ModePerm); err != nil {
+		return fmt.Errorf("failed writing plan file '%s': %w", jsonPath, err)
+	}
+	markdownPath := filepath.Join(planDir, "gonovate-plan.md")
+	if err := os.WriteFile(markdownPath, []byte(updatePlan.ToMarkdown()), os.ModePerm); err != nil {
+		return fmt.Errorf("failed writing plan file '%s': %w", markdownPath, err)
+	}
+	logger.Info(fmt.Sprintf("Wrote plan to '%s' and '%s'", jsonPath, markdownPath))
 	return nil
 }
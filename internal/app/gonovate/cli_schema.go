@@ -0,0 +1,36 @@
+package gonovate
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/roemer/gonovate/pkg/config"
+)
+
+func SchemaCmd(args []string) error {
+	// Flags and help for the command
+	var outputFile string
+	flagSet := flag.NewFlagSet("schema", flag.ExitOnError)
+	flagSet.StringVar(&outputFile, "output", "", "The path to a file to write the schema to. Defaults to stdout.")
+	flagSet.StringVar(&outputFile, "o", outputFile, "Alias for -output.")
+	flagSet.Usage = func() { printCmdUsage(flagSet, "schema", "") }
+	flagSet.Parse(args)
+
+	// Generate the schema
+	schemaJson, err := config.GenerateSchemaJson()
+	if err != nil {
+		return fmt.Errorf("failed generating the schema: %w", err)
+	}
+	schemaJson = append(schemaJson, '\n')
+
+	// Write the schema
+	if outputFile != "" {
+		if err := os.WriteFile(outputFile, schemaJson, os.ModePerm); err != nil {
+			return fmt.Errorf("failed writing schema file '%s': %w", outputFile, err)
+		}
+		return nil
+	}
+	_, err = os.Stdout.Write(schemaJson)
+	return err
+}
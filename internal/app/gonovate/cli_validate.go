@@ -0,0 +1,45 @@
+package gonovate
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/roemer/gonovate/pkg/config"
+)
+
+func ValidateCmd(args []string) error {
+	// Flags and help for the command
+	var verbose bool
+	var configFiles stringSliceFlag
+	var workingDirectory string
+	flagSet := flag.NewFlagSet("validate", flag.ExitOnError)
+	flagSet.BoolVar(&verbose, "verbose", false, "The flag to set in order to get verbose output.")
+	flagSet.BoolVar(&verbose, "v", verbose, "Alias for -verbose.")
+	flagSet.Var(&configFiles, "config", "The path to the config file to validate. Can be passed multiple times.")
+	flagSet.StringVar(&workingDirectory, "workDir", "", "The path to the working directory.")
+	flagSet.Usage = func() { printCmdUsage(flagSet, "validate", "") }
+	flagSet.Parse(args)
+
+	// Create a logger
+	logger := createLogger(os.Stderr, verbose)
+
+	// Change the working directory
+	if err := changeWorkingDirectory(logger, workingDirectory); err != nil {
+		return err
+	}
+
+	// Read the configuration with strict decoding
+	gonovateConfig, err := loadConfigFiles(config.NewConfigLoader(logger).WithStrictDecoding(), configFiles)
+	if err != nil {
+		return err
+	}
+
+	// Validate the merged configuration
+	if err := gonovateConfig.Validate(); err != nil {
+		return fmt.Errorf("the configuration is invalid:\n%w", err)
+	}
+
+	fmt.Println("The configuration is valid")
+	return nil
+}
@@ -6,13 +6,26 @@ var FalsePtr *bool = &[]bool{false}[0]
 type PlatformType string
 
 const (
-	PLATFORM_TYPE_GIT    PlatformType = "git"
-	PLATFORM_TYPE_GITEA  PlatformType = "gitea"
-	PLATFORM_TYPE_GITHUB PlatformType = "github"
-	PLATFORM_TYPE_GITLAB PlatformType = "gitlab"
-	PLATFORM_TYPE_NOOP   PlatformType = "noop"
+	PLATFORM_TYPE_AZURE_DEVOPS     PlatformType = "azure-devops"
+	PLATFORM_TYPE_BITBUCKET_SERVER PlatformType = "bitbucket-server"
+	PLATFORM_TYPE_GIT              PlatformType = "git"
+	PLATFORM_TYPE_GITEA            PlatformType = "gitea"
+	PLATFORM_TYPE_GITHUB           PlatformType = "github"
+	PLATFORM_TYPE_GITLAB           PlatformType = "gitlab"
+	PLATFORM_TYPE_NOOP             PlatformType = "noop"
 )
 
+// All known platform types. New types must be added here to be known by the schema and the validation.
+var AllPlatformTypes = []PlatformType{
+	PLATFORM_TYPE_AZURE_DEVOPS,
+	PLATFORM_TYPE_BITBUCKET_SERVER,
+	PLATFORM_TYPE_GIT,
+	PLATFORM_TYPE_GITEA,
+	PLATFORM_TYPE_GITHUB,
+	PLATFORM_TYPE_GITLAB,
+	PLATFORM_TYPE_NOOP,
+}
+
 type ManagerType string
 
 const (
@@ -27,6 +40,19 @@ const (
 	MANAGER_TYPE_NPM            ManagerType = "npm"
 )
 
+// All known manager types.
+var AllManagerTypes = []ManagerType{
+	MANAGER_TYPE_DEVCONTAINER,
+	MANAGER_TYPE_DOCKER_COMPOSE,
+	MANAGER_TYPE_DOCKERFILE,
+	MANAGER_TYPE_GOMOD,
+	MANAGER_TYPE_HELM,
+	MANAGER_TYPE_INLINE,
+	MANAGER_TYPE_KUBERNETES,
+	MANAGER_TYPE_REGEX,
+	MANAGER_TYPE_NPM,
+}
+
 type DatasourceType string
 
 const (
@@ -49,6 +75,27 @@ const (
 	DATASOURCE_TYPE_NPM             DatasourceType = "npm"
 )
 
+// All known datasource types.
+var AllDatasourceTypes = []DatasourceType{
+	DATASOURCE_TYPE_ANTVERSION,
+	DATASOURCE_TYPE_ARTIFACTORY,
+	DATASOURCE_TYPE_BROWSERVERSION,
+	DATASOURCE_TYPE_DOCKER,
+	DATASOURCE_TYPE_GITEA_RELEASES,
+	DATASOURCE_TYPE_GIT_TAGS,
+	DATASOURCE_TYPE_GITHUB_RELEASES,
+	DATASOURCE_TYPE_GITHUB_TAGS,
+	DATASOURCE_TYPE_GITLAB_PACKAGES,
+	DATASOURCE_TYPE_GOMOD,
+	DATASOURCE_TYPE_GOVERSION,
+	DATASOURCE_TYPE_GRADLEVERSION,
+	DATASOURCE_TYPE_HELM,
+	DATASOURCE_TYPE_JAVAVERSION,
+	DATASOURCE_TYPE_MAVEN,
+	DATASOURCE_TYPE_NODEJS,
+	DATASOURCE_TYPE_NPM,
+}
+
 type UpdateType string
 
 const (
@@ -57,6 +104,9 @@ const (
 	UPDATE_TYPE_PATCH UpdateType = "patch"
 )
 
+// All known update types.
+var AllUpdateTypes = []UpdateType{UPDATE_TYPE_MAJOR, UPDATE_TYPE_MINOR, UPDATE_TYPE_PATCH}
+
 func (a UpdateType) IsLessSignificant(b UpdateType) bool {
 	toPriority := func(t UpdateType) int {
 		switch t {
@@ -72,3 +122,36 @@ func (a UpdateType) IsLessSignificant(b UpdateType) bool {
 	}
 	return toPriority(a) < toPriority(b)
 }
+
+type AutomergeStrategy string
+
+const (
+	AUTOMERGE_STRATEGY_MERGE  AutomergeStrategy = "merge"
+	AUTOMERGE_STRATEGY_SQUASH AutomergeStrategy = "squash"
+	AUTOMERGE_STRATEGY_REBASE AutomergeStrategy = "rebase"
+)
+
+// All known automerge strategies.
+var AllAutomergeStrategies = []AutomergeStrategy{AUTOMERGE_STRATEGY_MERGE, AUTOMERGE_STRATEGY_SQUASH, AUTOMERGE_STRATEGY_REBASE}
+
+type RebaseWhen string
+
+const (
+	REBASE_WHEN_NEVER       RebaseWhen = "never"
+	REBASE_WHEN_CONFLICTED  RebaseWhen = "conflicted"
+	REBASE_WHEN_BEHIND_BASE RebaseWhen = "behind-base"
+)
+
+// All known values of when to rebase.
+var AllRebaseWhen = []RebaseWhen{REBASE_WHEN_NEVER, REBASE_WHEN_CONFLICTED, REBASE_WHEN_BEHIND_BASE}
+
+type SemanticCommits string
+
+const (
+	SEMANTIC_COMMITS_ENABLED  SemanticCommits = "enabled"
+	SEMANTIC_COMMITS_DISABLED SemanticCommits = "disabled"
+	SEMANTIC_COMMITS_AUTO     SemanticCommits = "auto"
+)
+
+// All known values of the semantic commits setting.
+var AllSemanticCommits = []SemanticCommits{SEMANTIC_COMMITS_ENABLED, SEMANTIC_COMMITS_DISABLED, SEMANTIC_COMMITS_AUTO}
@@ -11,6 +11,8 @@ type DatasourceSettings struct {
 	Logger *slog.Logger
 	// Host rules that might apply when using this datasource.
 	HostRules []*HostRule
+	// An optional GitHub App whose token is used for its server if no host rule defines a token.
+	GitHubApp *GitHubApp
 	// An optional cache to use.
 	Cache cache.Cache[[]*ReleaseInfo]
 }
@@ -8,62 +8,84 @@ import (
 // This type represents a concrete dependency.
 type Dependency struct {
 	// The name of the dependency.
-	Name string
+	Name string `json:"name"`
 	// The current version of the dependency (unprocessed).
-	Version string
+	Version string `json:"version"`
 	// An optional digest either in addition to the version or instead a version.
-	Digest string
+	Digest string `json:"digest,omitempty"`
 	// The type of the dependency. Used to allow different handlings per type in the manager. Optional.
-	Type string
+	Type string `json:"type,omitempty"`
 	// The datasource of the dependency.
-	Datasource DatasourceType
+	Datasource DatasourceType `json:"datasource"`
 	// A map that contains additional data about the dependency (for example a digest).
-	AdditionalData map[string]string
+	AdditionalData map[string]string `json:"additionalData,omitempty"`
 	// The filepath from where this dependency was found.
-	FilePath string
+	FilePath string `json:"filePath"`
 
 	// A list of update types that are allowed. Can be "major", "minor", or "patch".
-	UpdateTypes []UpdateType
+	UpdateTypes []UpdateType `json:"updateTypes,omitempty"`
 	// This flag defines if unstable releases are allowed. Unstable usually means a version that also has parts with text.
-	AllowUnstable *bool
+	AllowUnstable *bool `json:"allowUnstable,omitempty"`
 	// A list of registry urls to use. Allows overwriting the default. Depends on the datasource.
-	RegistryUrls []string
+	RegistryUrls []string `json:"registryUrls,omitempty"`
 	// Defines the regexp to use to parse the version into separate parts. See https://github.com/Roemer/gover for more details.
-	Versioning string
+	Versioning string `json:"versioning,omitempty"`
 	// An optional regexp that is used to separate the version part from the rest of the raw version string.
-	ExtractVersion string
+	ExtractVersion string `json:"extractVersion,omitempty"`
 	// A flag to indicate if versions from a remote that do not match the versioning should be ignored or give an exception.
-	IgnoreNonMatching *bool
+	IgnoreNonMatching *bool `json:"ignoreNonMatching,omitempty"`
 	// A flag that allows disabling individual dependencies.
-	Skip *bool
+	Skip *bool `json:"skip,omitempty"`
 	// An optional text to describe, why a dependency was disabled.
-	SkipReason string
+	SkipReason string `json:"skipReason,omitempty"`
+	// The minimum age a release must have to be considered (like "3d" or "12h"). Releases without a release date are always considered.
+	MinimumReleaseAge string `json:"minimumReleaseAge,omitempty"`
+	// Restricts the versions that are allowed, either with a range (like "<18" or ">=15, <16") or a regexp (like "^3\.").
+	AllowedVersions string `json:"allowedVersions,omitempty"`
+	// A list of versions that should never be used. Entries can be a regexp when prefixed with "re:".
+	IgnoredVersions []string `json:"ignoredVersions,omitempty"`
 	// Flag to indicate if the version check should be skipped (eg. for versions like latest or jdk8 where there is still a digest)
-	SkipVersionCheck *bool
+	SkipVersionCheck *bool `json:"skipVersionCheck,omitempty"`
 
 	// Allows defining regexes that replace further information from dependencies (like hash) after updating.
-	PostUpgradeReplacements []string
+	PostUpgradeReplacements []string `json:"postUpgradeReplacements,omitempty"`
 	// An optional name of a group to group dependency updates together.
-	GroupName string
+	GroupName string `json:"groupName,omitempty"`
 	// A list of labels to add to the created PR for this dependency.
-	Labels []string
+	Labels []string `json:"labels,omitempty"`
 	// A list of reviewers to add to the created PR for this dependency.
-	Reviewers []string
+	Reviewers []string `json:"reviewers,omitempty"`
 	// A template for the title when committing and creating an MR/PR.
-	TitleTemplate string
+	TitleTemplate string `json:"titleTemplate,omitempty"`
 	// A template for the branch name when creating an MR/PR.
-	BranchNameTemplate string
+	BranchNameTemplate string `json:"branchNameTemplate,omitempty"`
+	// A template for the body (description) of the MR/PR.
+	PrBodyTemplate string `json:"prBodyTemplate,omitempty"`
+	// A flag to create separate branches and MRs/PRs per update type (major, minor, patch) when using the default templates.
+	SeparateUpdateTypes *bool `json:"separateUpdateTypes,omitempty"`
+	// A flag to only create the branch and MR/PR after the update was approved on the dependency dashboard.
+	RequireApproval *bool `json:"requireApproval,omitempty"`
+	// A flag to automatically merge the MR/PR once all checks succeeded.
+	Automerge *bool `json:"automerge,omitempty"`
+	// The strategy to use when merging automatically.
+	AutomergeStrategy AutomergeStrategy `json:"automergeStrategy,omitempty"`
+	// The type of conventional commits (like "chore" or "fix") when semantic commits are used.
+	SemanticCommitType string `json:"semanticCommitType,omitempty"`
+	// The scope of conventional commits (like "deps") when semantic commits are used.
+	SemanticCommitScope string `json:"semanticCommitScope,omitempty"`
+	// A flag to use the auto-merge feature of the platform instead of merging on a later run.
+	PlatformAutomerge *bool `json:"platformAutomerge,omitempty"`
 
 	// Contains information about the manager from which this dependency was found from. Is "nil" if the dependency is not from a manager.
-	ManagerInfo *ManagerInfo
+	ManagerInfo *ManagerInfo `json:"managerInfo,omitempty"`
 }
 
 // Object with information about a manager.
 type ManagerInfo struct {
 	// The id of the manager from which this dependency was found.
-	ManagerId string
+	ManagerId string `json:"managerId"`
 	// An object that can contain data which is set/read from the manager to process the dependency.
-	ManagerData interface{}
+	ManagerData interface{} `json:"-"`
 }
 
 func (d *Dependency) String() string {
@@ -4,4 +4,6 @@ package common
 type DependencyWithUpdate struct {
 	Dependency *Dependency
 	NewRelease *ReleaseInfo
+	// The notes of the new release, if they could be fetched.
+	ReleaseNotes []*ReleaseNotes
 }
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v84/github"
+)
+
+// Creates a client for the GitHub API. The endpoint is empty for github.com or the url of
+// a GitHub Enterprise Server (like "https://github.example.com" or "https://github.example.com/api/v3").
+func NewGitHubClient(endpoint string, token string) (*github.Client, error) {
+	client := github.NewClient(nil)
+	if !IsPublicGitHub(endpoint) {
+		serverUrl := GetGitHubServerUrl(endpoint)
+		enterpriseClient, err := client.WithEnterpriseURLs(serverUrl, serverUrl)
+		if err != nil {
+			return nil, fmt.Errorf("failed setting the github enterprise urls for '%s': %w", endpoint, err)
+		}
+		client = enterpriseClient
+	}
+	if token != "" {
+		client = client.WithAuthToken(token)
+	}
+	return client, nil
+}
+
+// Checks if the endpoint is empty or points to github.com.
+func IsPublicGitHub(endpoint string) bool {
+	if endpoint == "" {
+		return true
+	}
+	host := GetGitHubApiHost(endpoint)
+	return host == "github.com" || host == "api.github.com"
+}
+
+// Returns the url of the web interface for the given endpoint like "https://github.com" or "https://github.example.com".
+func GetGitHubServerUrl(endpoint string) string {
+	if IsPublicGitHub(endpoint) {
+		return "https://github.com"
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	return strings.TrimSuffix(endpoint, "/api/v3")
+}
+
+// Returns the host of the GitHub API for the given endpoint. This host is used to lookup host rules.
+func GetGitHubApiHost(endpoint string) string {
+	if endpoint == "" {
+		return "api.github.com"
+	}
+	parsedUrl, err := url.Parse(endpoint)
+	if err != nil || parsedUrl.Host == "" {
+		return endpoint
+	}
+	host := strings.ToLower(parsedUrl.Host)
+	if host == "github.com" {
+		return "api.github.com"
+	}
+	return host
+}
@@ -0,0 +1,174 @@
+package common
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v84/github"
+)
+
+// Installation tokens are renewed when they expire within this duration.
+const gitHubAppTokenRefreshMargin = 5 * time.Minute
+
+// A GitHub App which authenticates with installation tokens that are created with a JWT signed by the private key of the app.
+type GitHubApp struct {
+	// The endpoint of the GitHub API, empty for github.com.
+	endpoint       string
+	appId          int64
+	installationId int64
+	privateKey     *rsa.PrivateKey
+	// The current installation token and its expiry as well as the bot user, guarded by the mutex.
+	mutex        sync.Mutex
+	token        string
+	expiresAt    time.Time
+	botUserName  string
+	botUserEmail string
+}
+
+// Creates a GitHub App with the given ids and the PEM encoded private key.
+func NewGitHubApp(endpoint string, appId int64, installationId int64, privateKeyPem []byte) (*GitHubApp, error) {
+	privateKey, err := parseRsaPrivateKey(privateKeyPem)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing the private key of the github app: %w", err)
+	}
+	return &GitHubApp{
+		endpoint:       endpoint,
+		appId:          appId,
+		installationId: installationId,
+		privateKey:     privateKey,
+	}, nil
+}
+
+// Returns the host of the API the app belongs to.
+func (a *GitHubApp) ApiHost() string {
+	return GetGitHubApiHost(a.endpoint)
+}
+
+// Returns a valid installation token. The token is created on the first call and renewed shortly before it expires.
+func (a *GitHubApp) Token() (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.currentToken()
+}
+
+// Returns the name and email of the bot user of the app which are used for commits. They are only looked up once.
+func (a *GitHubApp) LookupBotUser() (string, string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.botUserName != "" {
+		return a.botUserName, a.botUserEmail, nil
+	}
+	jwtClient, err := a.createJwtClient()
+	if err != nil {
+		return "", "", err
+	}
+	app, _, err := jwtClient.Apps.Get(context.Background(), "")
+	if err != nil {
+		return "", "", fmt.Errorf("failed reading the github app: %w", err)
+	}
+	token, err := a.currentToken()
+	if err != nil {
+		return "", "", err
+	}
+	client, err := NewGitHubClient(a.endpoint, token)
+	if err != nil {
+		return "", "", err
+	}
+	botName := app.GetSlug() + "[bot]"
+	botUser, _, err := client.Users.Get(context.Background(), botName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed reading the bot user '%s' of the github app: %w", botName, err)
+	}
+	serverHost := "github.com"
+	if serverUrl, err := url.Parse(GetGitHubServerUrl(a.endpoint)); err == nil {
+		serverHost = serverUrl.Hostname()
+	}
+	a.botUserName = botName
+	a.botUserEmail = fmt.Sprintf("%d+%s@users.noreply.%s", botUser.GetID(), botName, serverHost)
+	return a.botUserName, a.botUserEmail, nil
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+// Returns a valid installation token and creates a new one if needed. The mutex must be held.
+func (a *GitHubApp) currentToken() (string, error) {
+	if a.token != "" && time.Until(a.expiresAt) > gitHubAppTokenRefreshMargin {
+		return a.token, nil
+	}
+	client, err := a.createJwtClient()
+	if err != nil {
+		return "", err
+	}
+	installationToken, _, err := client.Apps.CreateInstallationToken(context.Background(), a.installationId, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed creating an installation token for the github app: %w", err)
+	}
+	a.token = installationToken.GetToken()
+	a.expiresAt = installationToken.GetExpiresAt().Time
+	return a.token, nil
+}
+
+// Creates a client that authenticates as the app itself.
+func (a *GitHubApp) createJwtClient() (*github.Client, error) {
+	jwt, err := a.createJwt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return NewGitHubClient(a.endpoint, jwt)
+}
+
+// Creates a JWT which is valid for a few minutes. The issue time is set back to allow some clock drift.
+func (a *GitHubApp) createJwt(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": a.appId,
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("failed signing the jwt of the github app: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Parses a RSA private key in the PKCS #1 format (as downloaded from GitHub) or the PKCS #8 format.
+func parseRsaPrivateKey(privateKeyPem []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPem)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	if privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return privateKey, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the private key is not a RSA key")
+	}
+	return privateKey, nil
+}
@@ -0,0 +1,117 @@
+package common
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubApp(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(err)
+	privateKeyPem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	// Verifies the JWT of the app
+	verifyJwt := func(r *http.Request) {
+		jwt, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		require.True(found)
+		parts := strings.Split(jwt, ".")
+		require.Len(parts, 3)
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(err)
+		hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, hash[:], signature))
+		claimsJson, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(err)
+		claims := map[string]int64{}
+		require.NoError(json.Unmarshal(claimsJson, &claims))
+		assert.Equal(int64(7), claims["iss"])
+		assert.Less(claims["iat"], time.Now().Unix())
+		assert.Greater(claims["exp"], time.Now().Unix())
+	}
+
+	tokenCount := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v3/app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		verifyJwt(r)
+		tokenCount++
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token":"token-%d","expires_at":"%s"}`, tokenCount, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("GET /api/v3/app", func(w http.ResponseWriter, r *http.Request) {
+		verifyJwt(r)
+		w.Write([]byte(`{"id":7,"slug":"gonovate"}`))
+	})
+	userCount := 0
+	mux.HandleFunc("GET /api/v3/users/{user}", func(w http.ResponseWriter, r *http.Request) {
+		userCount++
+		assert.Equal("Bearer token-1", r.Header.Get("Authorization"))
+		assert.Equal("gonovate[bot]", r.PathValue("user"))
+		w.Write([]byte(`{"id":123,"login":"gonovate[bot]"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	app, err := NewGitHubApp(server.URL, 7, 42, privateKeyPem)
+	require.NoError(err)
+	assert.Equal("127.0.0.1", strings.Split(app.ApiHost(), ":")[0])
+
+	// The token is reused until it expires
+	token, err := app.Token()
+	require.NoError(err)
+	assert.Equal("token-1", token)
+	token, err = app.Token()
+	require.NoError(err)
+	assert.Equal("token-1", token)
+
+	// The bot user
+	name, email, err := app.LookupBotUser()
+	require.NoError(err)
+	assert.Equal("gonovate[bot]", name)
+	assert.Equal("123+gonovate[bot]@users.noreply.127.0.0.1", email)
+
+	// The bot user is only looked up once
+	name, _, err = app.LookupBotUser()
+	require.NoError(err)
+	assert.Equal("gonovate[bot]", name)
+	assert.Equal(1, userCount)
+
+	// The token is renewed shortly before it expires
+	app.expiresAt = time.Now().Add(time.Minute)
+	token, err = app.Token()
+	require.NoError(err)
+	assert.Equal("token-2", token)
+}
+
+func TestParseRsaPrivateKey(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(err)
+	parsedKey, err := parseRsaPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))
+	require.NoError(err)
+	assert.True(privateKey.Equal(parsedKey))
+
+	_, err = parseRsaPrivateKey([]byte("not a key"))
+	assert.Error(err)
+}
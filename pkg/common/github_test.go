@@ -0,0 +1,39 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGitHubClient(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// github.com
+	client, err := NewGitHubClient("", "token")
+	require.NoError(err)
+	assert.Equal("https://api.github.com/", client.BaseURL.String())
+	client, err = NewGitHubClient("https://api.github.com", "")
+	require.NoError(err)
+	assert.Equal("https://api.github.com/", client.BaseURL.String())
+
+	// GitHub Enterprise Server with and without the api path
+	for _, endpoint := range []string{"https://github.example.com", "https://github.example.com/api/v3/"} {
+		client, err = NewGitHubClient(endpoint, "token")
+		require.NoError(err)
+		assert.Equal("https://github.example.com/api/v3/", client.BaseURL.String())
+		assert.Equal("https://github.example.com/api/uploads/", client.UploadURL.String())
+	}
+}
+
+func TestGitHubUrls(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("https://github.com", GetGitHubServerUrl(""))
+	assert.Equal("https://github.com", GetGitHubServerUrl("https://api.github.com/"))
+	assert.Equal("https://github.example.com", GetGitHubServerUrl("https://github.example.com/api/v3/"))
+	assert.Equal("api.github.com", GetGitHubApiHost(""))
+	assert.Equal("api.github.com", GetGitHubApiHost("https://github.com"))
+	assert.Equal("github.example.com", GetGitHubApiHost("https://github.example.com/api/v3"))
+}
@@ -41,3 +41,9 @@ type IDatasource interface {
 	// Handles the dependency update searching.
 	SearchDependencyUpdates(dependency *Dependency) ([]*ReleaseInfo, error)
 }
+
+// Optional interface for datasources which know the source repository of a dependency.
+type ISourceUrlDatasource interface {
+	// Gets the url of the source repository of the dependency for the given release or an empty string if it is not known.
+	GetSourceUrl(dependency *Dependency, release *ReleaseInfo) (string, error)
+}
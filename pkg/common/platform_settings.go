@@ -14,12 +14,20 @@ type PlatformSettings struct {
 	Platform PlatformType
 	// The token which is used to interact with the platform. Is expanded from environment variables.
 	Token string
+	// An optional GitHub App which is used instead of the token.
+	GitHubApp *GitHubApp
 	// The endpoint to use when interacting with the platform.
 	Endpoint string
 	// The author to use when interacting with git.
 	GitAuthor string
 	// The name of the base branch.
 	BaseBranch string
+	// When existing branches should be rebuilt on the base branch.
+	RebaseWhen RebaseWhen
+	// An optional label which is added to PRs/MRs of branches that were modified by someone else.
+	ModifiedBranchLabel string
+	// Flag to add a comment to PRs/MRs of branches that were modified by someone else.
+	ModifiedBranchComment bool
 	// Cache for gitlab user id lookups.
 	GitLabUserIdCache *cache.MemoryCache[int64]
 }
@@ -1,6 +1,10 @@
 package common
 
-import "strings"
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
 
 type Project struct {
 	Path string
@@ -9,5 +13,17 @@ type Project struct {
 // Splits the path into "owner" and "repository"
 func (p *Project) SplitPath() (string, string) {
 	parts := strings.SplitN(p.Path, "/", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
 	return parts[0], parts[1]
 }
+
+// Splits a path with three segments into "organization", "project" and "repository"
+func (p *Project) SplitOrganizationPath() (string, string, string, error) {
+	parts := strings.Split(p.Path, "/")
+	if len(parts) != 3 || slices.Contains(parts, "") {
+		return "", "", "", fmt.Errorf("invalid project path '%s', expected 'organization/project/repository'", p.Path)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
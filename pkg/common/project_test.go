@@ -0,0 +1,40 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectSplitPath(t *testing.T) {
+	assert := assert.New(t)
+
+	owner, repository := (&Project{Path: "owner/repo"}).SplitPath()
+	assert.Equal("owner", owner)
+	assert.Equal("repo", repository)
+
+	owner, repository = (&Project{Path: "group/sub/repo"}).SplitPath()
+	assert.Equal("group", owner)
+	assert.Equal("sub/repo", repository)
+
+	owner, repository = (&Project{Path: "repo"}).SplitPath()
+	assert.Equal("repo", owner)
+	assert.Equal("", repository)
+}
+
+func TestProjectSplitOrganizationPath(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	organization, project, repository, err := (&Project{Path: "org/My Project/repo"}).SplitOrganizationPath()
+	require.NoError(err)
+	assert.Equal("org", organization)
+	assert.Equal("My Project", project)
+	assert.Equal("repo", repository)
+
+	for _, path := range []string{"owner/repo", "org/project/repo/extra", "org//repo"} {
+		_, _, _, err = (&Project{Path: path}).SplitOrganizationPath()
+		assert.ErrorContains(err, "expected 'organization/project/repository'", path)
+	}
+}
@@ -0,0 +1,13 @@
+package common
+
+// Holds the notes of a single release of a dependency.
+type ReleaseNotes struct {
+	// The version the notes belong to.
+	Version string
+	// The title of the release.
+	Title string
+	// The notes in markdown.
+	Body string
+	// The url to the release.
+	Url string
+}
@@ -0,0 +1,52 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Regex for the subject of a conventional commit like "feat: add x", "fix(api)!: change y".
+var conventionalCommitRegex = regexp.MustCompile(`^[a-z]+(\([^()\s]+\))?!?: \S`)
+
+// The number of recent commits that are checked to detect conventional commits.
+const semanticCommitsDetectionDepth = 20
+
+// Builds the prefix of a conventional commit like "chore(deps): ".
+func BuildSemanticCommitPrefix(commitType string, scope string) string {
+	if scope == "" {
+		return commitType + ": "
+	}
+	return fmt.Sprintf("%s(%s): ", commitType, scope)
+}
+
+// Checks if the repository in the current directory uses conventional commits.
+// This is the case if at least half of the recent commits (without merges) are conventional commits.
+func DetectSemanticCommits() (bool, error) {
+	stdout, _, err := Git.Run("log", fmt.Sprintf("--max-count=%d", semanticCommitsDetectionDepth), "--no-merges", "--format=%s")
+	if err != nil {
+		return false, fmt.Errorf("failed reading the commit history: %w", err)
+	}
+	return usesConventionalCommits(strings.Split(stdout, "\n")), nil
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+// Checks if at least half of the given commit subjects are conventional commits.
+func usesConventionalCommits(subjects []string) bool {
+	total := 0
+	conventional := 0
+	for _, subject := range subjects {
+		subject = strings.TrimSpace(subject)
+		if subject == "" {
+			continue
+		}
+		total++
+		if conventionalCommitRegex.MatchString(subject) {
+			conventional++
+		}
+	}
+	return total > 0 && conventional*2 >= total
+}
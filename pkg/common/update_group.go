@@ -6,4 +6,24 @@ type UpdateGroup struct {
 	Dependencies []*DependencyWithUpdate
 	Labels       []string
 	Reviewers    []string
+	// The template for the body of the MR/PR.
+	BodyTemplate string
+	// The rendered body of the MR/PR.
+	Body string
+	// Flag if the group needs to be approved on the dependency dashboard before it is created.
+	RequiresApproval bool
+	// Flag if the MR/PR of the group should be merged automatically. Is only set if all dependencies allow it.
+	Automerge bool
+	// The strategy to use when merging automatically.
+	AutomergeStrategy AutomergeStrategy
+	// Flag if the auto-merge feature of the platform should be used instead of merging on a later run.
+	PlatformAutomerge bool
+}
+
+// Returns the strategy to merge the group with, falling back to a plain merge.
+func (g *UpdateGroup) GetAutomergeStrategy() AutomergeStrategy {
+	if g.AutomergeStrategy == "" {
+		return AUTOMERGE_STRATEGY_MERGE
+	}
+	return g.AutomergeStrategy
 }
@@ -0,0 +1,173 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Contains all the updates that would be done for all projects.
+type UpdatePlan struct {
+	Projects []*ProjectUpdatePlan `json:"projects"`
+}
+
+// Contains the updates that would be done for a single project.
+type ProjectUpdatePlan struct {
+	Project      string             `json:"project"`
+	Groups       []*UpdateGroupPlan `json:"groups"`
+	LookupErrors []*LookupErrorPlan `json:"lookupErrors,omitempty"`
+}
+
+// Contains the information about a branch and PR/MR that would be created.
+type UpdateGroupPlan struct {
+	BranchName       string                  `json:"branchName"`
+	Title            string                  `json:"title"`
+	Labels           []string                `json:"labels"`
+	Reviewers        []string                `json:"reviewers"`
+	RequiresApproval bool                    `json:"requiresApproval,omitempty"`
+	Automerge        AutomergeStrategy       `json:"automerge,omitempty"`
+	Dependencies     []*DependencyUpdatePlan `json:"dependencies"`
+}
+
+// Contains the information about a single dependency update.
+type DependencyUpdatePlan struct {
+	Name       string         `json:"name"`
+	FilePath   string         `json:"filePath"`
+	ManagerId  string         `json:"managerId,omitempty"`
+	Datasource DatasourceType `json:"datasource"`
+	OldVersion string         `json:"oldVersion"`
+	NewVersion string         `json:"newVersion"`
+	OldDigest  string         `json:"oldDigest,omitempty"`
+	NewDigest  string         `json:"newDigest,omitempty"`
+	UpdateType UpdateType     `json:"updateType,omitempty"`
+}
+
+// Contains the information about a dependency where searching for updates failed.
+type LookupErrorPlan struct {
+	Name       string         `json:"name"`
+	FilePath   string         `json:"filePath"`
+	Datasource DatasourceType `json:"datasource"`
+	Error      string         `json:"error"`
+}
+
+// Adds the given update groups of a project to the plan.
+func (p *UpdatePlan) AddProject(projectPath string, updateGroups []*UpdateGroup) *ProjectUpdatePlan {
+	projectPlan := &ProjectUpdatePlan{
+		Project: projectPath,
+		Groups:  []*UpdateGroupPlan{},
+	}
+	for _, updateGroup := range updateGroups {
+		groupPlan := &UpdateGroupPlan{
+			BranchName:       updateGroup.BranchName,
+			Title:            updateGroup.Title,
+			Labels:           nonNilSlice(updateGroup.Labels),
+			Reviewers:        nonNilSlice(updateGroup.Reviewers),
+			RequiresApproval: updateGroup.RequiresApproval,
+			Dependencies:     []*DependencyUpdatePlan{},
+		}
+		if updateGroup.Automerge {
+			groupPlan.Automerge = updateGroup.GetAutomergeStrategy()
+		}
+		for _, dependencyWithUpdate := range updateGroup.Dependencies {
+			dependency := dependencyWithUpdate.Dependency
+			newRelease := dependencyWithUpdate.NewRelease
+			dependencyPlan := &DependencyUpdatePlan{
+				Name:       dependency.Name,
+				FilePath:   dependency.FilePath,
+				Datasource: dependency.Datasource,
+				OldVersion: dependency.Version,
+				NewVersion: newRelease.VersionString,
+				OldDigest:  dependency.Digest,
+				NewDigest:  newRelease.Digest,
+				UpdateType: newRelease.UpdateType,
+			}
+			if dependency.ManagerInfo != nil {
+				dependencyPlan.ManagerId = dependency.ManagerInfo.ManagerId
+			}
+			groupPlan.Dependencies = append(groupPlan.Dependencies, dependencyPlan)
+		}
+		projectPlan.Groups = append(projectPlan.Groups, groupPlan)
+	}
+	p.Projects = append(p.Projects, projectPlan)
+	return projectPlan
+}
+
+// Adds a dependency where searching for updates failed.
+func (pp *ProjectUpdatePlan) AddLookupError(dependency *Dependency, err error) {
+	pp.LookupErrors = append(pp.LookupErrors, &LookupErrorPlan{
+		Name:       dependency.Name,
+		FilePath:   dependency.FilePath,
+		Datasource: dependency.Datasource,
+		Error:      err.Error(),
+	})
+}
+
+// Renders the plan as a markdown document.
+func (p *UpdatePlan) ToMarkdown() string {
+	sb := &strings.Builder{}
+	sb.WriteString("# Gonovate Update Plan\n")
+	for _, projectPlan := range p.Projects {
+		sb.WriteString(fmt.Sprintf("\n## %s\n", projectPlan.Project))
+		if len(projectPlan.Groups) == 0 {
+			sb.WriteString("\nNo updates found.\n")
+		}
+		for _, groupPlan := range projectPlan.Groups {
+			sb.WriteString(fmt.Sprintf("\n### %s\n\n", groupPlan.Title))
+			sb.WriteString(fmt.Sprintf("- Branch: `%s`\n", groupPlan.BranchName))
+			if len(groupPlan.Labels) > 0 {
+				sb.WriteString(fmt.Sprintf("- Labels: %s\n", strings.Join(groupPlan.Labels, ", ")))
+			}
+			if len(groupPlan.Reviewers) > 0 {
+				sb.WriteString(fmt.Sprintf("- Reviewers: %s\n", strings.Join(groupPlan.Reviewers, ", ")))
+			}
+			if groupPlan.RequiresApproval {
+				sb.WriteString("- Requires approval on the dependency dashboard\n")
+			}
+			if groupPlan.Automerge != "" {
+				sb.WriteString(fmt.Sprintf("- Merged automatically (%s)\n", groupPlan.Automerge))
+			}
+			sb.WriteString("\n| Dependency | File | Update | Type |\n")
+			sb.WriteString("| --- | --- | --- | --- |\n")
+			for _, dependencyPlan := range groupPlan.Dependencies {
+				oldVersion := dependencyPlan.OldVersion
+				newVersion := dependencyPlan.NewVersion
+				if dependencyPlan.OldDigest != "" || dependencyPlan.NewDigest != "" {
+					oldVersion = fmt.Sprintf("%s@%s", oldVersion, shortDigest(dependencyPlan.OldDigest))
+					newVersion = fmt.Sprintf("%s@%s", newVersion, shortDigest(dependencyPlan.NewDigest))
+				}
+				sb.WriteString(fmt.Sprintf("| %s | %s | `%s` → `%s` | %s |\n", dependencyPlan.Name, dependencyPlan.FilePath, oldVersion, newVersion, dependencyPlan.UpdateType))
+			}
+		}
+		if len(projectPlan.LookupErrors) > 0 {
+			sb.WriteString("\n### Failed Lookups\n\n")
+			sb.WriteString("| Dependency | File | Datasource | Error |\n")
+			sb.WriteString("| --- | --- | --- | --- |\n")
+			for _, lookupError := range projectPlan.LookupErrors {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", lookupError.Name, lookupError.FilePath, lookupError.Datasource, strings.ReplaceAll(lookupError.Error, "|", "\\|")))
+			}
+		}
+	}
+	return sb.String()
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+func nonNilSlice(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// Shortens digests like "sha256:abcdef..." for better readability.
+func shortDigest(digest string) string {
+	_, hash, found := strings.Cut(digest, ":")
+	if !found {
+		hash = digest
+	}
+	if len(hash) > 12 {
+		hash = hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,103 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdatePlan(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	plan := &UpdatePlan{}
+	plan.AddProject("owner/repo", []*UpdateGroup{
+		{
+			Title:      "Update 'golang' to '1.24.1'",
+			BranchName: "gonovate/main-golang-1.24.1",
+			Labels:     []string{"dependencies"},
+			Dependencies: []*DependencyWithUpdate{
+				{
+					Dependency: &Dependency{Name: "golang", FilePath: "Dockerfile", Version: "1.23.0", Datasource: DATASOURCE_TYPE_DOCKER, ManagerInfo: &ManagerInfo{ManagerId: "dockerfile"}},
+					NewRelease: &ReleaseInfo{VersionString: "1.24.1", UpdateType: UPDATE_TYPE_MINOR},
+				},
+			},
+		},
+	})
+	plan.AddProject("owner/empty", nil).AddLookupError(&Dependency{Name: "left-pad", FilePath: "package.json", Datasource: DATASOURCE_TYPE_NPM}, errors.New("status 404 | not found"))
+	plan.AddProject("owner/approval", []*UpdateGroup{
+		{
+			Title:            "Update 'node' to '24.0.0'",
+			BranchName:       "gonovate/main-node-24.0.0",
+			RequiresApproval: true,
+		},
+	})
+	plan.AddProject("owner/automerge", []*UpdateGroup{
+		{
+			Title:             "Update 'alpine' to '3.22.1'",
+			BranchName:        "gonovate/main-alpine-3.22.1",
+			Automerge:         true,
+			AutomergeStrategy: AUTOMERGE_STRATEGY_SQUASH,
+		},
+	})
+
+	// Json
+	jsonBytes, err := json.Marshal(plan)
+	require.NoError(err)
+	assert.JSONEq(`{"projects":[
+		{"project":"owner/repo","groups":[{
+			"branchName":"gonovate/main-golang-1.24.1",
+			"title":"Update 'golang' to '1.24.1'",
+			"labels":["dependencies"],
+			"reviewers":[],
+			"dependencies":[{"name":"golang","filePath":"Dockerfile","managerId":"dockerfile","datasource":"docker","oldVersion":"1.23.0","newVersion":"1.24.1","updateType":"minor"}]
+		}]},
+		{"project":"owner/empty","groups":[],"lookupErrors":[
+			{"name":"left-pad","filePath":"package.json","datasource":"npm","error":"status 404 | not found"}
+		]},
+		{"project":"owner/approval","groups":[{
+			"branchName":"gonovate/main-node-24.0.0",
+			"title":"Update 'node' to '24.0.0'",
+			"labels":[],
+			"reviewers":[],
+			"requiresApproval":true,
+			"dependencies":[]
+		}]},
+		{"project":"owner/automerge","groups":[{
+			"branchName":"gonovate/main-alpine-3.22.1",
+			"title":"Update 'alpine' to '3.22.1'",
+			"labels":[],
+			"reviewers":[],
+			"automerge":"squash",
+			"dependencies":[]
+		}]}
+	]}`, string(jsonBytes))
+
+	// Markdown
+	markdown := plan.ToMarkdown()
+	assert.Contains(markdown, "## owner/repo\n")
+	assert.Contains(markdown, "- Branch: `gonovate/main-golang-1.24.1`\n")
+	assert.Contains(markdown, "- Labels: dependencies\n")
+	assert.NotContains(markdown, "- Reviewers:")
+	assert.Contains(markdown, "| golang | Dockerfile | `1.23.0` → `1.24.1` | minor |\n")
+	assert.Contains(markdown, "## owner/empty\n\nNo updates found.\n")
+	assert.Contains(markdown, "### Failed Lookups\n\n| Dependency | File | Datasource | Error |\n| --- | --- | --- | --- |\n| left-pad | package.json | npm | status 404 \\| not found |\n")
+	assert.Contains(markdown, "- Branch: `gonovate/main-node-24.0.0`\n- Requires approval on the dependency dashboard\n")
+	assert.Contains(markdown, "- Branch: `gonovate/main-alpine-3.22.1`\n- Merged automatically (squash)\n")
+}
+
+func TestShortDigest(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("0123456789ab", shortDigest("sha256:0123456789abcdef"))
+	assert.Equal("abc", shortDigest("abc"))
+	assert.Equal("", shortDigest(""))
+}
+
+func TestGetAutomergeStrategy(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(AUTOMERGE_STRATEGY_MERGE, (&UpdateGroup{}).GetAutomergeStrategy())
+	assert.Equal(AUTOMERGE_STRATEGY_REBASE, (&UpdateGroup{AutomergeStrategy: AUTOMERGE_STRATEGY_REBASE}).GetAutomergeStrategy())
+}
@@ -4,10 +4,49 @@ import (
 	"bytes"
 	"fmt"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 )
 
+// Regex for a single part of a duration like "3d" or "12h".
+var durationPartRegex = regexp.MustCompile(`(\d+)(w|d|h|m|s)`)
+
+// Parses a duration like "3d", "1w" or "1d12h". Allowed units are w (weeks), d (days), h, m and s.
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	units := map[string]time.Duration{
+		"w": 7 * 24 * time.Hour,
+		"d": 24 * time.Hour,
+		"h": time.Hour,
+		"m": time.Minute,
+		"s": time.Second,
+	}
+	duration := time.Duration(0)
+	parsedLength := 0
+	for _, match := range durationPartRegex.FindAllStringSubmatchIndex(value, -1) {
+		// Make sure the parts follow each other without anything in between
+		if match[0] != parsedLength {
+			break
+		}
+		amount, err := strconv.Atoi(value[match[2]:match[3]])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s': %w", value, err)
+		}
+		duration += time.Duration(amount) * units[value[match[4]:match[5]]]
+		parsedLength = match[1]
+	}
+	if parsedLength != len(value) {
+		return 0, fmt.Errorf("invalid duration '%s', expected something like '3d' or '1d12h'", value)
+	}
+	return duration, nil
+}
+
 func NormalizeString(value string, maxLength int) string {
 	// Assign the initial value
 	normalizedString := value
@@ -46,6 +85,11 @@ type TitleBuilderSettings struct {
 	DependencyName string
 	GroupName      string
 	NewRelease     *ReleaseInfo
+	// If set, the default template contains the update type.
+	SeparateUpdateTypes bool
+	// The type and scope of conventional commits (like "chore" and "deps"). The title only gets the prefix if the type is set.
+	SemanticCommitType  string
+	SemanticCommitScope string
 }
 
 type BranchNameBuilderSettings struct {
@@ -54,6 +98,8 @@ type BranchNameBuilderSettings struct {
 	DependencyName     string
 	GroupName          string
 	NewRelease         *ReleaseInfo
+	// If set, the default template contains the update type so each update type gets its own branch.
+	SeparateUpdateTypes bool
 }
 
 func BuildTitle(settings *TitleBuilderSettings) (string, error) {
@@ -61,8 +107,12 @@ func BuildTitle(settings *TitleBuilderSettings) (string, error) {
 		return "", fmt.Errorf("settings is nil")
 	}
 	templateString := settings.TitleTemplate
-	if templateString == "" {
+	isDefaultTemplate := templateString == ""
+	if isDefaultTemplate {
 		templateString = "Update {{if .GroupName}}group '{{.GroupName}}'{{else}}'{{.DependencyName}}' to '{{.NewVersion}}'{{end}}"
+		if settings.SeparateUpdateTypes {
+			templateString += "{{if .UpdateType}} ({{.UpdateType}}){{end}}"
+		}
 	}
 	tmpl, err := template.New("tmpl").Option("missingkey=error").Parse(templateString)
 	if err != nil {
@@ -100,6 +150,13 @@ func BuildTitle(settings *TitleBuilderSettings) (string, error) {
 	if title == "" {
 		return "", fmt.Errorf("generated title is empty (template=%q, dependency=%q, group=%q)", templateString, settings.DependencyName, settings.GroupName)
 	}
+	// Add the prefix of conventional commits, their descriptions start lowercase
+	if settings.SemanticCommitType != "" {
+		if isDefaultTemplate {
+			title = strings.ToLower(title[:1]) + title[1:]
+		}
+		title = BuildSemanticCommitPrefix(settings.SemanticCommitType, settings.SemanticCommitScope) + title
+	}
 	return title, nil
 }
 
@@ -110,6 +167,9 @@ func BuildBranchName(settings *BranchNameBuilderSettings) (string, error) {
 	templateString := settings.BranchNameTemplate
 	if templateString == "" {
 		templateString = "{{if .GroupName}}{{.GroupName}}{{else}}{{.BaseBranch}}-{{.DependencyName}}-{{.NewVersion}}{{end}}"
+		if settings.SeparateUpdateTypes {
+			templateString = "{{if .GroupName}}{{.GroupName}}{{else}}{{.BaseBranch}}-{{.DependencyName}}{{end}}{{if .UpdateType}}-{{.UpdateType}}{{end}}{{if not .GroupName}}-{{.NewVersion}}{{end}}"
+		}
 	}
 
 	tmpl, err := template.New("tmpl").Option("missingkey=error").Parse(templateString)
@@ -163,3 +223,62 @@ func BuildBranchName(settings *BranchNameBuilderSettings) (string, error) {
 	}
 	return normalizedBranchName, nil
 }
+
+// The default template for the body of MRs/PRs. Lists the updates followed by the release notes if there are any.
+const defaultPrBodyTemplate = `{{range .Dependencies}}- {{.Dependency.Name}} from {{.Dependency.Version}} to {{.NewRelease.VersionString}}
+{{end}}{{if .HasReleaseNotes}}
+## Release Notes
+{{range .Dependencies}}{{$name := .Dependency.Name}}{{range .ReleaseNotes}}
+<details>
+<summary>{{$name}} {{.Version}}</summary>
+
+{{if .Url}}[{{.Title}}]({{.Url}})
+
+{{end}}{{.Body}}
+
+</details>
+{{end}}{{end}}{{end}}`
+
+// The maximum length of a rendered body. The platforms limit the final body including the update markers on their own.
+const maxPullRequestBodyLength = 60000
+
+// Builds the body of the MR/PR of the group with its body template or the default one.
+func BuildPullRequestBody(updateGroup *UpdateGroup) (string, error) {
+	if updateGroup == nil {
+		return "", fmt.Errorf("update group is nil")
+	}
+	templateString := updateGroup.BodyTemplate
+	if templateString == "" {
+		templateString = defaultPrBodyTemplate
+	}
+	tmpl, err := template.New("tmpl").Option("missingkey=error").Parse(templateString)
+	if err != nil {
+		return "", fmt.Errorf("body template parse error (template=%q, branch=%q): %w", templateString, updateGroup.BranchName, err)
+	}
+
+	// Prepare the template data
+	data := struct {
+		Title           string
+		BranchName      string
+		Dependencies    []*DependencyWithUpdate
+		HasReleaseNotes bool
+	}{
+		Title:        updateGroup.Title,
+		BranchName:   updateGroup.BranchName,
+		Dependencies: updateGroup.Dependencies,
+		HasReleaseNotes: slices.ContainsFunc(updateGroup.Dependencies, func(d *DependencyWithUpdate) bool {
+			return len(d.ReleaseNotes) > 0
+		}),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("body template execution error (branch=%q): %w", updateGroup.BranchName, err)
+	}
+
+	body := strings.TrimSpace(buf.String())
+	if len(body) > maxPullRequestBodyLength {
+		body = strings.ToValidUTF8(body[:maxPullRequestBodyLength], "") + "\n\n*The body was truncated.*"
+	}
+	return body, nil
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -54,6 +55,41 @@ func TestBranchName_WithGroupName(t *testing.T) {
 	assert.Equal("core-deps", branchName)
 }
 
+func TestTitleAndBranchName_SeparateUpdateTypes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	for _, testCase := range []struct {
+		groupName      string
+		updateType     UpdateType
+		expectedTitle  string
+		expectedBranch string
+	}{
+		{"", UPDATE_TYPE_MAJOR, "Update 'roemer/foo' to '2.0.0' (major)", "main-roemer-foo-major-2.0.0"},
+		{"core-deps", UPDATE_TYPE_MINOR, "Update group 'core-deps' (minor)", "core-deps-minor"},
+		{"core-deps", "", "Update group 'core-deps'", "core-deps"},
+	} {
+		newRelease := &ReleaseInfo{VersionString: "2.0.0", UpdateType: testCase.updateType}
+		title, err := BuildTitle(&TitleBuilderSettings{
+			DependencyName:      "roemer/foo",
+			GroupName:           testCase.groupName,
+			NewRelease:          newRelease,
+			SeparateUpdateTypes: true,
+		})
+		require.NoError(err)
+		assert.Equal(testCase.expectedTitle, title)
+		branchName, err := BuildBranchName(&BranchNameBuilderSettings{
+			BaseBranch:          "main",
+			DependencyName:      "roemer/foo",
+			GroupName:           testCase.groupName,
+			NewRelease:          newRelease,
+			SeparateUpdateTypes: true,
+		})
+		require.NoError(err)
+		assert.Equal(testCase.expectedBranch, branchName)
+	}
+}
+
 func TestTitle_CustomTemplate_Valid(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -67,6 +103,42 @@ func TestTitle_CustomTemplate_Valid(t *testing.T) {
 	assert.Equal("Upgrade acme/pkg -> 2.0.0", title)
 }
 
+func TestTitle_SemanticCommits(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	settings := &TitleBuilderSettings{
+		DependencyName:      "roemer/foo",
+		NewRelease:          &ReleaseInfo{VersionString: "1.2.3"},
+		SemanticCommitType:  "chore",
+		SemanticCommitScope: "deps",
+	}
+	title, err := BuildTitle(settings)
+	require.NoError(err)
+	assert.Equal("chore(deps): update 'roemer/foo' to '1.2.3'", title)
+
+	// Without scope
+	settings.SemanticCommitScope = ""
+	title, err = BuildTitle(settings)
+	require.NoError(err)
+	assert.Equal("chore: update 'roemer/foo' to '1.2.3'", title)
+
+	// Custom templates are kept as they are
+	settings.SemanticCommitType = "fix"
+	settings.TitleTemplate = "Bump {{.DependencyName}}"
+	title, err = BuildTitle(settings)
+	require.NoError(err)
+	assert.Equal("fix: Bump roemer/foo", title)
+}
+
+func TestUsesConventionalCommits(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(usesConventionalCommits([]string{"feat: add x", "fix(api)!: change y", "Update readme", ""}))
+	assert.True(usesConventionalCommits([]string{"chore(deps): update 'foo' to '1.2.3'"}))
+	assert.False(usesConventionalCommits([]string{"feat: add x", "Update readme", "Fix: typo", "Merge branch 'main'"}))
+	assert.False(usesConventionalCommits([]string{"feat:missing space"}))
+	assert.False(usesConventionalCommits([]string{}))
+}
+
 func TestBranchName_CustomTemplate_Valid(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -164,3 +236,76 @@ func TestBranchName_UsesRawFields(t *testing.T) {
 	assert.Equal("thisisaver-ntychars-x", normBranch)
 	assert.Equal(NormalizeString(longBase, 0)+"-x", rawBranch)
 }
+
+func TestParseDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	for value, expected := range map[string]time.Duration{
+		"3d":    72 * time.Hour,
+		"1w":    168 * time.Hour,
+		"12h":   12 * time.Hour,
+		"1d12h": 36 * time.Hour,
+		"30m":   30 * time.Minute,
+		" 2d ":  48 * time.Hour,
+	} {
+		duration, err := ParseDuration(value)
+		assert.NoError(err, value)
+		assert.Equal(expected, duration, value)
+	}
+	for _, value := range []string{"", "3", "d", "3 days", "1.5d", "3d-"} {
+		_, err := ParseDuration(value)
+		assert.Error(err, value)
+	}
+}
+
+func TestPullRequestBody_Default(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	updateGroup := &UpdateGroup{Dependencies: []*DependencyWithUpdate{
+		{
+			Dependency: &Dependency{Name: "golang", Version: "1.23.0"},
+			NewRelease: &ReleaseInfo{VersionString: "1.24.1"},
+		},
+		{
+			Dependency:   &Dependency{Name: "owner/tool", Version: "1.0.0"},
+			NewRelease:   &ReleaseInfo{VersionString: "1.1.0"},
+			ReleaseNotes: []*ReleaseNotes{{Version: "1.1.0", Title: "v1.1.0", Body: "- Feature A", Url: "https://example.com/v1.1.0"}},
+		},
+	}}
+	body, err := BuildPullRequestBody(updateGroup)
+	require.NoError(err)
+	assert.Equal("- golang from 1.23.0 to 1.24.1\n- owner/tool from 1.0.0 to 1.1.0\n\n## Release Notes\n\n"+
+		"<details>\n<summary>owner/tool 1.1.0</summary>\n\n[v1.1.0](https://example.com/v1.1.0)\n\n- Feature A\n\n</details>", body)
+
+	// Without release notes
+	updateGroup.Dependencies[1].ReleaseNotes = nil
+	body, err = BuildPullRequestBody(updateGroup)
+	require.NoError(err)
+	assert.Equal("- golang from 1.23.0 to 1.24.1\n- owner/tool from 1.0.0 to 1.1.0", body)
+}
+
+func TestPullRequestBody_Template(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	updateGroup := &UpdateGroup{
+		Title:        "Update 'golang' to '1.24.1'",
+		BodyTemplate: "{{.Title}}\n{{range .Dependencies}}{{.Dependency.FilePath}} {{.Dependency.ManagerInfo.ManagerId}} {{.Dependency.Datasource}} {{.NewRelease.UpdateType}} {{.NewRelease.ReleaseDate.Format \"2006-01-02\"}} {{.NewRelease.Digest}} {{index .NewRelease.AdditionalData \"hash\"}}{{end}}",
+		Dependencies: []*DependencyWithUpdate{
+			{
+				Dependency: &Dependency{Name: "golang", Version: "1.23.0", FilePath: "Dockerfile", Datasource: DATASOURCE_TYPE_DOCKER, ManagerInfo: &ManagerInfo{ManagerId: "dockerfile"}},
+				NewRelease: &ReleaseInfo{VersionString: "1.24.1", UpdateType: UPDATE_TYPE_MINOR, ReleaseDate: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), Digest: "sha256:abc", AdditionalData: map[string]string{"hash": "123"}},
+			},
+		},
+	}
+	body, err := BuildPullRequestBody(updateGroup)
+	require.NoError(err)
+	assert.Equal("Update 'golang' to '1.24.1'\nDockerfile dockerfile docker minor 2025-03-04 sha256:abc 123", body)
+
+	// Invalid templates
+	updateGroup.BodyTemplate = "{{.Title"
+	_, err = BuildPullRequestBody(updateGroup)
+	assert.ErrorContains(err, "body template parse error")
+	updateGroup.BodyTemplate = "{{.NonExistent}}"
+	_, err = BuildPullRequestBody(updateGroup)
+	assert.ErrorContains(err, "body template execution error")
+}
@@ -0,0 +1,153 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/roemer/gover"
+)
+
+// A constraint that restricts the versions which are allowed for a dependency.
+// It is either a range like "<18" or ">=15, <16" or a regexp like "^3\." which must match the version string.
+type VersionConstraint struct {
+	raw          string
+	regex        *regexp.Regexp
+	ranges       []*versionRange
+	versionRegex *regexp.Regexp
+}
+
+// A single part of a range constraint like ">=15".
+type versionRange struct {
+	operator string
+	version  *gover.Version
+}
+
+// The supported operators, longer ones first so they are matched before their prefixes.
+var versionRangeOperators = []string{"<=", ">=", "!=", "<", ">", "="}
+
+// Parses the given constraint. Versions in a range are parsed with the given versioning regexp if possible.
+func ParseVersionConstraint(constraint string, versionRegex *regexp.Regexp) (*VersionConstraint, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+	versionConstraint := &VersionConstraint{raw: constraint, versionRegex: versionRegex}
+
+	// Anything that does not start with an operator is a regexp
+	if !strings.ContainsAny(constraint[:1], "<>=!") {
+		regex, err := regexp.Compile(constraint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint regexp '%s': %w", constraint, err)
+		}
+		versionConstraint.regex = regex
+		return versionConstraint, nil
+	}
+
+	// Parse the parts of the range
+	for part := range strings.FieldsFuncSeq(constraint, func(r rune) bool { return r == ',' }) {
+		part = strings.TrimSpace(part)
+		operator := ""
+		for _, candidate := range versionRangeOperators {
+			if strings.HasPrefix(part, candidate) {
+				operator = candidate
+				break
+			}
+		}
+		if operator == "" {
+			return nil, fmt.Errorf("invalid version constraint '%s': missing operator in '%s'", constraint, part)
+		}
+		versionString := strings.TrimSpace(part[len(operator):])
+		if versionString == "" {
+			return nil, fmt.Errorf("invalid version constraint '%s': missing version in '%s'", constraint, part)
+		}
+		versionConstraint.ranges = append(versionConstraint.ranges, &versionRange{
+			operator: operator,
+			version:  parseConstraintVersion(versionString, versionRegex),
+		})
+	}
+	return versionConstraint, nil
+}
+
+// Checks if the release is allowed by the constraint.
+func (c *VersionConstraint) Allows(release *ReleaseInfo) bool {
+	if c.regex != nil {
+		return c.regex.MatchString(release.VersionString)
+	}
+	if release.Version == nil {
+		return false
+	}
+	for _, versionRange := range c.ranges {
+		comparison := release.Version.CompareTo(versionRange.version)
+		allowed := false
+		switch versionRange.operator {
+		case "<":
+			allowed = comparison < 0
+		case "<=":
+			allowed = comparison <= 0
+		case ">":
+			allowed = comparison > 0
+		case ">=":
+			allowed = comparison >= 0
+		case "=":
+			allowed = comparison == 0
+		case "!=":
+			allowed = comparison != 0
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// Checks if the given version string is allowed by the constraint. The version is parsed like the versions of a range.
+func (c *VersionConstraint) AllowsVersion(versionString string) bool {
+	return c.Allows(&ReleaseInfo{
+		VersionString: versionString,
+		Version:       parseConstraintVersion(versionString, c.versionRegex),
+	})
+}
+
+func (c *VersionConstraint) String() string {
+	return c.raw
+}
+
+// Checks if the release matches any of the ignored versions. Those can either be plain or a regexp when prefixed with "re:".
+func IsIgnoredVersion(release *ReleaseInfo, ignoredVersions []string) (bool, error) {
+	for _, ignoredVersion := range ignoredVersions {
+		if strings.HasPrefix(ignoredVersion, "re:") {
+			regex, err := regexp.Compile(ignoredVersion[3:])
+			if err != nil {
+				return false, fmt.Errorf("invalid ignored version regexp '%s': %w", ignoredVersion[3:], err)
+			}
+			if regex.MatchString(release.VersionString) {
+				return true, nil
+			}
+		} else if release.VersionString == ignoredVersion {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+// Parses a version of a range. Falls back to a simple dot separated version (with an optional "v" prefix)
+// if the versioning does not match (like "18" for semver).
+func parseConstraintVersion(versionString string, versionRegex *regexp.Regexp) *gover.Version {
+	if versionRegex != nil {
+		if version, err := gover.ParseVersionFromRegex(versionString, versionRegex); err == nil {
+			return version
+		}
+	}
+	versionString = strings.TrimPrefix(versionString, "v")
+	if version, err := gover.ParseVersionFromRegex(versionString, gover.RegexpSimple); err == nil {
+		return version
+	}
+	version := gover.ParseSimple(strings.Split(versionString, "."))
+	version.Raw = versionString
+	return version
+}
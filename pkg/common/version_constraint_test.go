@@ -0,0 +1,75 @@
+package common
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/roemer/gover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionConstraint_Range(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	versionRegex := regexp.MustCompile(`^(?P<d1>\d+)(?:\.(?P<d2>\d+))?(?:\.(?P<d3>\d+))?$`)
+	release := func(version string) *ReleaseInfo {
+		return &ReleaseInfo{VersionString: version, Version: gover.MustParseVersionFromRegex(version, versionRegex)}
+	}
+
+	constraint, err := ParseVersionConstraint("<18", versionRegex)
+	require.NoError(err)
+	assert.True(constraint.Allows(release("17.9.1")))
+	assert.False(constraint.Allows(release("18.0.0")))
+	assert.False(constraint.Allows(release("20.1.0")))
+
+	constraint, err = ParseVersionConstraint(">=15, <16", versionRegex)
+	require.NoError(err)
+	assert.True(constraint.Allows(release("15.0")))
+	assert.True(constraint.Allows(release("15.8.2")))
+	assert.False(constraint.Allows(release("14.12")))
+	assert.False(constraint.Allows(release("16.1")))
+
+	constraint, err = ParseVersionConstraint("!=2.4.1", versionRegex)
+	require.NoError(err)
+	assert.True(constraint.Allows(release("2.4.2")))
+	assert.False(constraint.Allows(release("2.4.1")))
+}
+
+func TestVersionConstraint_Regex(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	constraint, err := ParseVersionConstraint(`^3\.`, nil)
+	require.NoError(err)
+	assert.True(constraint.Allows(&ReleaseInfo{VersionString: "3.20.1"}))
+	assert.False(constraint.Allows(&ReleaseInfo{VersionString: "4.0.0"}))
+}
+
+func TestVersionConstraint_Invalid(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, constraint := range []string{"", "^(3", "<", ">=15, 16"} {
+		_, err := ParseVersionConstraint(constraint, nil)
+		assert.Error(err, constraint)
+	}
+}
+
+func TestIsIgnoredVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	ignoredVersions := []string{"2.4.1", `re:^3\.0\.`}
+	for version, expected := range map[string]bool{
+		"2.4.1": true,
+		"2.4.2": false,
+		"3.0.5": true,
+		"3.1.0": false,
+	} {
+		isIgnored, err := IsIgnoredVersion(&ReleaseInfo{VersionString: version}, ignoredVersions)
+		assert.NoError(err)
+		assert.Equal(expected, isIgnored, version)
+	}
+	_, err := IsIgnoredVersion(&ReleaseInfo{VersionString: "1.0.0"}, []string{"re:[0-9"})
+	assert.Error(err)
+}
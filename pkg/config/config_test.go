@@ -34,7 +34,7 @@ func TestPostLoadProcess(t *testing.T) {
 	assert.Len(gonovateConfig.Rules, 0)
 
 	// Pre-process
-	gonovateConfig.PostLoadProcess()
+	assert.NoError(gonovateConfig.PostLoadProcess())
 
 	// Test after pre-process
 	assert.Nil(gonovateConfig.Managers[0].ManagerConfig)
@@ -51,6 +51,22 @@ func TestPostLoadProcess(t *testing.T) {
 	assert.Equal(checkRule.DependencyConfig.Versioning, "1.0.0")
 }
 
+func TestPostLoadProcessInvalidRegexp(t *testing.T) {
+	assert := assert.New(t)
+
+	gonovateConfig := &GonovateConfig{
+		Rules: []*Rule{
+			{Matches: &RuleMatch{DependencyNames: []string{"re:^golang"}}},
+			{Matches: &RuleMatch{ExcludeManagers: []string{"re:(docker"}}},
+		},
+	}
+	assert.ErrorContains(gonovateConfig.PostLoadProcess(), "invalid regexp '(docker'")
+
+	// Matching does not panic but never matches
+	assert.False(matchStringMatches("docker", "re:(docker"))
+	assert.True(matchStringMatches("golang", "re:^golang"))
+}
+
 func TestFileSearch(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"log/slog"
+	"os"
 
 	"github.com/roemer/gonovate/pkg/common"
+	"github.com/roemer/gonovate/pkg/platforms"
 	"github.com/roemer/gonovate/pkg/presets"
 	"github.com/samber/lo"
 )
@@ -35,11 +38,40 @@ func (managerConfig *ManagerConfig) ToCommonDevcontainerManagerSettings() *commo
 
 func (cfg *GonovateConfig) ToCommonPlatformSettings(logger *slog.Logger) *common.PlatformSettings {
 	return &common.PlatformSettings{
-		Logger:     logger,
-		Platform:   cfg.Platform.Type,
-		Token:      cfg.Platform.Token,
-		Endpoint:   cfg.Platform.Endpoint,
-		GitAuthor:  cfg.Platform.GitAuthor,
-		BaseBranch: cfg.Platform.BaseBranch,
+		Logger:                logger,
+		Platform:              cfg.Platform.Type,
+		Token:                 cfg.Platform.Token,
+		Endpoint:              cfg.Platform.Endpoint,
+		GitAuthor:             cfg.Platform.GitAuthor,
+		BaseBranch:            cfg.Platform.BaseBranch,
+		RebaseWhen:            cfg.Platform.RebaseWhen,
+		ModifiedBranchLabel:   cfg.Platform.ModifiedBranchLabel,
+		ModifiedBranchComment: cfg.Platform.ModifiedBranchComment != nil && *cfg.Platform.ModifiedBranchComment,
 	}
 }
+
+// Converts the autodiscover config to the settings of the platforms.
+func (autodiscoverConfig *AutodiscoverConfig) ToAutodiscoverSettings() *platforms.AutodiscoverSettings {
+	return &platforms.AutodiscoverSettings{
+		Namespaces:      autodiscoverConfig.Namespaces,
+		Includes:        autodiscoverConfig.Includes,
+		Excludes:        autodiscoverConfig.Excludes,
+		Topics:          autodiscoverConfig.Topics,
+		ExcludeTopics:   autodiscoverConfig.ExcludeTopics,
+		IncludeArchived: autodiscoverConfig.IncludeArchived != nil && *autodiscoverConfig.IncludeArchived,
+		IncludeForks:    autodiscoverConfig.IncludeForks != nil && *autodiscoverConfig.IncludeForks,
+	}
+}
+
+// Creates the GitHub App of the platform. Returns nil if no GitHub App is configured.
+func (cfg *GonovateConfig) ToGitHubApp() (*common.GitHubApp, error) {
+	if cfg.Platform == nil || !cfg.Platform.HasGitHubApp() {
+		return nil, nil
+	}
+	privateKeyFile := os.ExpandEnv(cfg.Platform.GitHubAppPrivateKeyFile)
+	privateKey, err := os.ReadFile(privateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading the private key of the github app: %w", err)
+	}
+	return common.NewGitHubApp(os.ExpandEnv(cfg.Platform.Endpoint), cfg.Platform.GitHubAppId, cfg.Platform.GitHubAppInstallationId, privateKey)
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/roemer/gonovate/pkg/common"
+)
+
+// Holds information about how a rule was applied to a dependency.
+type RuleExplanation struct {
+	// The position of the rule in the list of rules.
+	Index int
+	// The rule itself.
+	Rule *Rule
+	// The origin of the rule (like "preset:docker" or "local:gonovate.json").
+	Origin string
+	// Flag if the rule matched the dependency.
+	Matched bool
+	// The criterion of the rule that rejected the dependency (like "dependencyNames").
+	RejectedBy string
+	// The values of the criterion that rejected the dependency.
+	RejectedValues []string
+	// The dependency config values that the rule contributed, in the form "name: value".
+	Contributions []string
+}
+
+// Explains for each rule in order if and how it applies to the given dependency.
+func (config *GonovateConfig) ExplainDependency(dependency *common.Dependency) []*RuleExplanation {
+	explanations := []*RuleExplanation{}
+	config.mergeRulesForDependency(dependency, nil, func(index int, rule *Rule, rejectedBy string) {
+		explanation := &RuleExplanation{
+			Index:      index,
+			Rule:       rule,
+			Origin:     config.ruleOrigin(rule),
+			Matched:    rejectedBy == "",
+			RejectedBy: rejectedBy,
+		}
+		if rejectedBy != "" {
+			explanation.RejectedValues = configFieldValues(reflect.ValueOf(rule.Matches), rejectedBy)
+		} else if rule.DependencyConfig != nil {
+			forEachConfigField(reflect.ValueOf(rule.DependencyConfig).Elem(), func(name string, field reflect.Value) {
+				if !isEmptyConfigValue(field) {
+					explanation.Contributions = append(explanation.Contributions, fmt.Sprintf("%s: %v", name, reflect.Indirect(field).Interface()))
+				}
+			})
+		}
+		explanations = append(explanations, explanation)
+	})
+	return explanations
+}
+
+// Describes the rule for humans, either with its origin or where it was generated from.
+func (explanation *RuleExplanation) Describe() string {
+	parts := []string{}
+	if explanation.Rule.GeneratedFrom != "" {
+		parts = append(parts, fmt.Sprintf("generated from %s", explanation.Rule.GeneratedFrom))
+	}
+	if explanation.Origin != "" {
+		parts = append(parts, explanation.Origin)
+	}
+	if len(parts) == 0 {
+		return "unknown origin"
+	}
+	return strings.Join(parts, ", ")
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+// Returns the origin of the rule, taken from the first value of the rule that has an origin.
+func (config *GonovateConfig) ruleOrigin(rule *Rule) string {
+	origin := ""
+	walkConfigValues(reflect.ValueOf(rule), configItemPath("rules", reflect.ValueOf(rule)), func(path string) {
+		if origin == "" {
+			origin = config.origins[path]
+		}
+	})
+	return origin
+}
+
+// Returns the values of the field with the given json name as strings.
+func configFieldValues(value reflect.Value, fieldName string) []string {
+	values := []string{}
+	if isEmptyConfigValue(value) {
+		return values
+	}
+	forEachConfigField(reflect.Indirect(value), func(name string, field reflect.Value) {
+		if name != fieldName {
+			return
+		}
+		if field.Kind() != reflect.Slice {
+			values = append(values, fmt.Sprint(reflect.Indirect(field).Interface()))
+			return
+		}
+		for i := range field.Len() {
+			values = append(values, fmt.Sprint(field.Index(i).Interface()))
+		}
+	})
+	return values
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainDependency(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &GonovateConfig{
+		Managers: []*Manager{
+			{
+				Id:               "manager",
+				Type:             common.MANAGER_TYPE_REGEX,
+				DependencyConfig: &DependencyConfig{Datasource: common.DATASOURCE_TYPE_GOMOD},
+			},
+		},
+		Rules: []*Rule{
+			{
+				Matches:          &RuleMatch{DependencyNames: []string{"other", "re:^another"}},
+				DependencyConfig: &DependencyConfig{GroupName: "other"},
+			},
+			{
+				Matches:          &RuleMatch{Datasources: []common.DatasourceType{common.DATASOURCE_TYPE_GOMOD}},
+				DependencyConfig: &DependencyConfig{GroupName: "gomod", Labels: []string{"go"}},
+			},
+		},
+	}
+	assert.NoError(cfg.PostLoadProcess())
+	cfg.Rules = append(cfg.Rules, &Rule{GeneratedFrom: "exclusive", DependencyConfig: &DependencyConfig{Skip: common.TruePtr}})
+
+	explanations := cfg.ExplainDependency(&common.Dependency{
+		Name:        "dependency",
+		FilePath:    "file.txt",
+		ManagerInfo: &common.ManagerInfo{ManagerId: "manager"},
+	})
+	assert.Len(explanations, 4)
+
+	// Rule generated from the manager
+	assert.True(explanations[0].Matched)
+	assert.Equal("manager 'manager'", explanations[0].Rule.GeneratedFrom)
+	assert.Equal("generated from manager 'manager'", explanations[0].Describe())
+	assert.Equal([]string{"datasource: go-mod"}, explanations[0].Contributions)
+
+	// Rule rejected by the name
+	assert.False(explanations[1].Matched)
+	assert.Equal("dependencyNames", explanations[1].RejectedBy)
+	assert.Equal([]string{"other", "re:^another"}, explanations[1].RejectedValues)
+	assert.Empty(explanations[1].Contributions)
+
+	// Rule matching the datasource from the previous rule
+	assert.True(explanations[2].Matched)
+	assert.Equal([]string{"groupName: gomod", "labels: [go]"}, explanations[2].Contributions)
+
+	// Exclusive rule
+	assert.True(explanations[3].Matched)
+	assert.Equal("generated from exclusive", explanations[3].Describe())
+	assert.Equal([]string{"skip: true"}, explanations[3].Contributions)
+}
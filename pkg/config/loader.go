@@ -1,8 +1,10 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/url"
 	"os"
@@ -20,6 +22,8 @@ import (
 
 type ConfigLoader struct {
 	logger *slog.Logger
+	// Flag to fail on unknown keys in the configurations.
+	strict bool
 }
 
 func NewConfigLoader(logger *slog.Logger) *ConfigLoader {
@@ -32,6 +36,12 @@ func NewConfigLoader(logger *slog.Logger) *ConfigLoader {
 // Public Methods
 ////////////////////////////////////////////////////////////
 
+// Enables strict decoding which fails when a configuration contains unknown keys.
+func (cl *ConfigLoader) WithStrictDecoding() *ConfigLoader {
+	cl.strict = true
+	return cl
+}
+
 // Loads the given configuration
 func (cl *ConfigLoader) Load(configPath string) (*GonovateConfig, error) {
 	if configPath == "" {
@@ -55,14 +65,19 @@ func (cl *ConfigLoader) Load(configPath string) (*GonovateConfig, error) {
 func (cl *ConfigLoader) loadConfig(parentInfo, newInfo *configInfo) (*GonovateConfig, error) {
 	var newConfig *GonovateConfig
 	var err error
+	// The origin of the config, used to trace where values come from
+	origin := fmt.Sprintf("%s:%s", newInfo.Type, newInfo.Location)
 	// Try load the config according to the type
 	switch newInfo.Type {
 	case infoTypePreset:
 		newConfig, err = cl.loadConfigFromEmbeddedFile(newInfo.Location)
 	case infoTypeLocal:
-		newConfig, err = cl.loadConfigFromFile(parentInfo, newInfo)
+		var configPath string
+		newConfig, configPath, err = cl.loadConfigFromFile(parentInfo, newInfo)
+		origin = fmt.Sprintf("%s:%s", newInfo.Type, configPath)
 	case infoTypeWeb:
 		newConfig, err = cl.loadConfigFromWeb(newInfo.Location)
+		origin = newInfo.Location
 	default:
 		return nil, fmt.Errorf("unknown config type '%s'", newInfo.Type)
 	}
@@ -71,7 +86,10 @@ func (cl *ConfigLoader) loadConfig(parentInfo, newInfo *configInfo) (*GonovateCo
 	}
 
 	// PreProcess the config
-	newConfig.PostLoadProcess()
+	if err := newConfig.PostLoadProcess(); err != nil {
+		return nil, fmt.Errorf("failed processing config '%s:%s': %w", newInfo.Type, newInfo.Location, err)
+	}
+	newConfig.stampOrigin(origin)
 
 	// Create a new object for the merged config with the presets
 	mergedConfig := &GonovateConfig{}
@@ -96,7 +114,8 @@ func (cl *ConfigLoader) loadConfig(parentInfo, newInfo *configInfo) (*GonovateCo
 	return mergedConfig, nil
 }
 
-func (cl *ConfigLoader) loadConfigFromFile(parentInfo, newInfo *configInfo) (*GonovateConfig, error) {
+// Loads the config from a file and returns it together with the path of the file that was found.
+func (cl *ConfigLoader) loadConfigFromFile(parentInfo, newInfo *configInfo) (*GonovateConfig, string, error) {
 	// Build a list of paths that should be searched
 	searchPaths := []string{}
 	if filepath.IsAbs(newInfo.Location) {
@@ -133,7 +152,7 @@ func (cl *ConfigLoader) loadConfigFromFile(parentInfo, newInfo *configInfo) (*Go
 		if hasExt {
 			// We have an extension, directly search in the given path
 			if exists, err := common.FileExists(searchPath); err != nil {
-				return nil, err
+				return nil, "", err
 			} else if exists {
 				finalValidConfigPath = searchPath
 				break
@@ -141,7 +160,7 @@ func (cl *ConfigLoader) loadConfigFromFile(parentInfo, newInfo *configInfo) (*Go
 		} else {
 			// No extension, probe with the valid extensions
 			if foundPath, err := SearchConfigFileFromPath(searchPath); err != nil {
-				return nil, err
+				return nil, "", err
 			} else if foundPath != "" {
 				finalValidConfigPath = foundPath
 				break
@@ -155,26 +174,20 @@ func (cl *ConfigLoader) loadConfigFromFile(parentInfo, newInfo *configInfo) (*Go
 		// Open the file
 		configFile, err := os.Open(finalValidConfigPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed opening file '%s': %w", finalValidConfigPath, err)
+			return nil, "", fmt.Errorf("failed opening file '%s': %w", finalValidConfigPath, err)
 		}
 		defer configFile.Close()
 
 		// Decode the file
-		config := &GonovateConfig{}
-		if filepath.Ext(finalValidConfigPath) == ".json" {
-			if err = json.NewDecoder(configFile).Decode(config); err != nil {
-				return nil, fmt.Errorf("failed parsing file '%s': %w", finalValidConfigPath, err)
-			}
-		} else {
-			if err = yaml.NewDecoder(configFile).Decode(config); err != nil {
-				return nil, fmt.Errorf("failed parsing file '%s': %w", finalValidConfigPath, err)
-			}
+		config, err := cl.decodeConfig(configFile, filepath.Ext(finalValidConfigPath) == ".json")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed parsing file '%s': %w", finalValidConfigPath, err)
 		}
-		return config, nil
+		return config, finalValidConfigPath, nil
 	}
 
 	// Nothing found at all
-	return nil, fmt.Errorf("file not found for '%s'", newInfo.Location)
+	return nil, "", fmt.Errorf("file not found for '%s'", newInfo.Location)
 }
 
 func (cl *ConfigLoader) loadConfigFromEmbeddedFile(configPath string) (*GonovateConfig, error) {
@@ -205,15 +218,9 @@ func (cl *ConfigLoader) loadConfigFromEmbeddedFile(configPath string) (*Gonovate
 	}
 	defer configFile.Close()
 
-	config := &GonovateConfig{}
-	if path.Ext(configPath) == ".json" {
-		if err = json.NewDecoder(configFile).Decode(config); err != nil {
-			return nil, fmt.Errorf("failed parsing embedded file '%s': %w", configPath, err)
-		}
-	} else {
-		if err = yaml.NewDecoder(configFile).Decode(config); err != nil {
-			return nil, fmt.Errorf("failed parsing embedded file '%s': %w", configPath, err)
-		}
+	config, err := cl.decodeConfig(configFile, path.Ext(configPath) == ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing embedded file '%s': %w", configPath, err)
 	}
 	return config, nil
 }
@@ -233,14 +240,31 @@ func (cl *ConfigLoader) loadConfigFromWeb(urlString string) (*GonovateConfig, er
 	}
 
 	// Unmarshal it
+	config, err := cl.decodeConfig(bytes.NewReader(content), path.Ext(parsedUrl.Path) == ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing config from '%s': %w", urlString, err)
+	}
+	return config, nil
+}
+
+// Decodes a config from the given reader either as json or as yaml.
+func (cl *ConfigLoader) decodeConfig(reader io.Reader, isJson bool) (*GonovateConfig, error) {
 	config := &GonovateConfig{}
-	if path.Ext(parsedUrl.Path) == ".json" {
-		if err = json.Unmarshal(content, config); err != nil {
-			return nil, fmt.Errorf("failed parsing config from '%s': %w", urlString, err)
+	if isJson {
+		decoder := json.NewDecoder(reader)
+		if cl.strict {
+			decoder.DisallowUnknownFields()
+		}
+		if err := decoder.Decode(config); err != nil {
+			return nil, err
 		}
 	} else {
-		if err = yaml.Unmarshal(content, config); err != nil {
-			return nil, fmt.Errorf("failed parsing config from '%s': %w", urlString, err)
+		options := []yaml.DecodeOption{}
+		if cl.strict {
+			options = append(options, yaml.DisallowUnknownField())
+		}
+		if err := yaml.NewDecoder(reader, options...).Decode(config); err != nil {
+			return nil, err
 		}
 	}
 	return config, nil
@@ -65,6 +65,8 @@ func (configA *GonovateConfig) MergeWith(configB *GonovateConfig) {
 	configA.Rules = append(configA.Rules, configB.Rules...)
 	// Host Rules
 	configA.HostRules = append(configA.HostRules, configB.HostRules...)
+	// Origins
+	configA.mergeOriginsWith(configB)
 }
 
 func (platformConfigA *PlatformConfig) MergeWith(platformConfigB *PlatformConfig) {
@@ -79,6 +81,18 @@ func (platformConfigA *PlatformConfig) MergeWith(platformConfigB *PlatformConfig
 	if platformConfigB.Token != "" {
 		platformConfigA.Token = platformConfigB.Token
 	}
+	// GitHubAppId
+	if platformConfigB.GitHubAppId != 0 {
+		platformConfigA.GitHubAppId = platformConfigB.GitHubAppId
+	}
+	// GitHubAppInstallationId
+	if platformConfigB.GitHubAppInstallationId != 0 {
+		platformConfigA.GitHubAppInstallationId = platformConfigB.GitHubAppInstallationId
+	}
+	// GitHubAppPrivateKeyFile
+	if platformConfigB.GitHubAppPrivateKeyFile != "" {
+		platformConfigA.GitHubAppPrivateKeyFile = platformConfigB.GitHubAppPrivateKeyFile
+	}
 	// GitAuthor
 	if platformConfigB.GitAuthor != "" {
 		platformConfigA.GitAuthor = platformConfigB.GitAuthor
@@ -93,6 +107,13 @@ func (platformConfigA *PlatformConfig) MergeWith(platformConfigB *PlatformConfig
 	}
 	// Projects
 	platformConfigA.Projects = lo.Union(platformConfigA.Projects, platformConfigB.Projects)
+	// Autodiscover
+	if platformConfigB.Autodiscover != nil {
+		if platformConfigA.Autodiscover == nil {
+			platformConfigA.Autodiscover = &AutodiscoverConfig{}
+		}
+		platformConfigA.Autodiscover.MergeWith(platformConfigB.Autodiscover)
+	}
 	// BaseBranch
 	if platformConfigB.BaseBranch != "" {
 		platformConfigA.BaseBranch = platformConfigB.BaseBranch
@@ -105,6 +126,66 @@ func (platformConfigA *PlatformConfig) MergeWith(platformConfigB *PlatformConfig
 	if platformConfigB.CommitMessagePrefix != "" {
 		platformConfigA.CommitMessagePrefix = platformConfigB.CommitMessagePrefix
 	}
+	// SemanticCommits
+	if platformConfigB.SemanticCommits != "" {
+		platformConfigA.SemanticCommits = platformConfigB.SemanticCommits
+	}
+	// DependencyDashboard
+	if platformConfigB.DependencyDashboard != nil {
+		platformConfigA.DependencyDashboard = platformConfigB.DependencyDashboard
+	}
+	// DependencyDashboardTitle
+	if platformConfigB.DependencyDashboardTitle != "" {
+		platformConfigA.DependencyDashboardTitle = platformConfigB.DependencyDashboardTitle
+	}
+	// PrConcurrentLimit
+	if platformConfigB.PrConcurrentLimit != 0 {
+		platformConfigA.PrConcurrentLimit = platformConfigB.PrConcurrentLimit
+	}
+	// PrHourlyLimit
+	if platformConfigB.PrHourlyLimit != 0 {
+		platformConfigA.PrHourlyLimit = platformConfigB.PrHourlyLimit
+	}
+	// RebaseWhen
+	if platformConfigB.RebaseWhen != "" {
+		platformConfigA.RebaseWhen = platformConfigB.RebaseWhen
+	}
+	// ModifiedBranchLabel
+	if platformConfigB.ModifiedBranchLabel != "" {
+		platformConfigA.ModifiedBranchLabel = platformConfigB.ModifiedBranchLabel
+	}
+	// ModifiedBranchComment
+	if platformConfigB.ModifiedBranchComment != nil {
+		platformConfigA.ModifiedBranchComment = platformConfigB.ModifiedBranchComment
+	}
+}
+
+func (autodiscoverConfigA *AutodiscoverConfig) MergeWith(autodiscoverConfigB *AutodiscoverConfig) {
+	if autodiscoverConfigB == nil {
+		return
+	}
+	// Enabled
+	if autodiscoverConfigB.Enabled != nil {
+		autodiscoverConfigA.Enabled = autodiscoverConfigB.Enabled
+	}
+	// Namespaces
+	autodiscoverConfigA.Namespaces = lo.Union(autodiscoverConfigA.Namespaces, autodiscoverConfigB.Namespaces)
+	// Includes
+	autodiscoverConfigA.Includes = lo.Union(autodiscoverConfigA.Includes, autodiscoverConfigB.Includes)
+	// Excludes
+	autodiscoverConfigA.Excludes = lo.Union(autodiscoverConfigA.Excludes, autodiscoverConfigB.Excludes)
+	// Topics
+	autodiscoverConfigA.Topics = lo.Union(autodiscoverConfigA.Topics, autodiscoverConfigB.Topics)
+	// ExcludeTopics
+	autodiscoverConfigA.ExcludeTopics = lo.Union(autodiscoverConfigA.ExcludeTopics, autodiscoverConfigB.ExcludeTopics)
+	// IncludeArchived
+	if autodiscoverConfigB.IncludeArchived != nil {
+		autodiscoverConfigA.IncludeArchived = autodiscoverConfigB.IncludeArchived
+	}
+	// IncludeForks
+	if autodiscoverConfigB.IncludeForks != nil {
+		autodiscoverConfigA.IncludeForks = autodiscoverConfigB.IncludeForks
+	}
 }
 
 func (managerA *Manager) MergeWith(managerB *Manager) {
@@ -260,6 +341,48 @@ func (DependencyConfigA *DependencyConfig) MergeWith(DependencyConfigB *Dependen
 	if DependencyConfigB.BranchNameTemplate != "" {
 		DependencyConfigA.BranchNameTemplate = DependencyConfigB.BranchNameTemplate
 	}
+	// PrBodyTemplate
+	if DependencyConfigB.PrBodyTemplate != "" {
+		DependencyConfigA.PrBodyTemplate = DependencyConfigB.PrBodyTemplate
+	}
+	// SeparateUpdateTypes
+	if DependencyConfigB.SeparateUpdateTypes != nil {
+		DependencyConfigA.SeparateUpdateTypes = DependencyConfigB.SeparateUpdateTypes
+	}
+	// RequireApproval
+	if DependencyConfigB.RequireApproval != nil {
+		DependencyConfigA.RequireApproval = DependencyConfigB.RequireApproval
+	}
+	// MinimumReleaseAge
+	if DependencyConfigB.MinimumReleaseAge != "" {
+		DependencyConfigA.MinimumReleaseAge = DependencyConfigB.MinimumReleaseAge
+	}
+	// AllowedVersions
+	if DependencyConfigB.AllowedVersions != "" {
+		DependencyConfigA.AllowedVersions = DependencyConfigB.AllowedVersions
+	}
+	// IgnoredVersions (merge)
+	DependencyConfigA.IgnoredVersions = lo.Union(DependencyConfigA.IgnoredVersions, DependencyConfigB.IgnoredVersions)
+	// Automerge
+	if DependencyConfigB.Automerge != nil {
+		DependencyConfigA.Automerge = DependencyConfigB.Automerge
+	}
+	// AutomergeStrategy
+	if DependencyConfigB.AutomergeStrategy != "" {
+		DependencyConfigA.AutomergeStrategy = DependencyConfigB.AutomergeStrategy
+	}
+	// SemanticCommitType
+	if DependencyConfigB.SemanticCommitType != "" {
+		DependencyConfigA.SemanticCommitType = DependencyConfigB.SemanticCommitType
+	}
+	// SemanticCommitScope
+	if DependencyConfigB.SemanticCommitScope != "" {
+		DependencyConfigA.SemanticCommitScope = DependencyConfigB.SemanticCommitScope
+	}
+	// PlatformAutomerge
+	if DependencyConfigB.PlatformAutomerge != nil {
+		DependencyConfigA.PlatformAutomerge = DependencyConfigB.PlatformAutomerge
+	}
 }
 
 func (objA *DevcontainerFeatureDependency) MergeWith(objB *DevcontainerFeatureDependency) {
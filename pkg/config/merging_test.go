@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/roemer/gonovate/pkg/common"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -186,6 +187,35 @@ func TestMergePlatformConfig(t *testing.T) {
 	assert.Equal("token_b", merged.Platform.Token)
 }
 
+func TestMergeAutodiscoverConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	configA := &GonovateConfig{
+		Platform: &PlatformConfig{
+			Autodiscover: &AutodiscoverConfig{
+				Enabled:    lo.ToPtr(true),
+				Namespaces: []string{"org-a"},
+				Excludes:   []string{"org-a/legacy"},
+			},
+		},
+	}
+	configB := &GonovateConfig{
+		Platform: &PlatformConfig{
+			Autodiscover: &AutodiscoverConfig{
+				Namespaces:   []string{"org-b"},
+				IncludeForks: lo.ToPtr(true),
+			},
+		},
+	}
+	merged := configA.MergeWithAsCopy(configB)
+
+	assert.True(merged.Platform.IsAutodiscoverEnabled())
+	assert.Equal([]string{"org-a", "org-b"}, merged.Platform.Autodiscover.Namespaces)
+	assert.Equal([]string{"org-a/legacy"}, merged.Platform.Autodiscover.Excludes)
+	assert.True(*merged.Platform.Autodiscover.IncludeForks)
+	assert.Nil(merged.Platform.Autodiscover.IncludeArchived)
+}
+
 func TestMergeDevcontainerConfig(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"fmt"
 	"log/slog"
+	"reflect"
 	"regexp"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/roemer/goext"
 	"github.com/roemer/gonovate/pkg/cache"
@@ -16,7 +19,7 @@ import (
 )
 
 // This method processes the gonovate config object. This should be called on any config object just after loading.
-func (c *GonovateConfig) PostLoadProcess() {
+func (c *GonovateConfig) PostLoadProcess() error {
 	// Convert managerConfigs/dependencyConfigs to rules and add them to keep the priority order
 	for _, managerConfig := range c.Managers {
 		if managerConfig.ManagerConfig != nil || managerConfig.DependencyConfig != nil {
@@ -24,6 +27,7 @@ func (c *GonovateConfig) PostLoadProcess() {
 				Matches: &RuleMatch{
 					Managers: []string{managerConfig.Id},
 				},
+				GeneratedFrom: fmt.Sprintf("manager '%s'", managerConfig.Id),
 			}
 			if managerConfig.ManagerConfig != nil {
 				newRule.ManagerConfig = &ManagerConfig{}
@@ -38,6 +42,27 @@ func (c *GonovateConfig) PostLoadProcess() {
 			c.Rules = goext.SlicePrepend(c.Rules, newRule)
 		}
 	}
+	// Compile the regexps of the rules so invalid ones are reported before matching
+	for i, rule := range c.Rules {
+		if rule.Matches == nil {
+			continue
+		}
+		for _, matchStrings := range [][]string{
+			rule.Matches.Managers,
+			rule.Matches.ExcludeManagers,
+			rule.Matches.DependencyNames,
+			rule.Matches.ExcludeDependencyNames,
+			rule.Matches.DependencyTypes,
+			rule.Matches.ExcludeDependencyTypes,
+		} {
+			for _, matchString := range matchStrings {
+				if _, err := compileMatchString(matchString); err != nil {
+					return fmt.Errorf("invalid match in rule %d: %w", i, err)
+				}
+			}
+		}
+	}
+	return nil
 }
 
 func (config *GonovateConfig) GetMergedManagerConfig(managerId string, managerType common.ManagerType) *ManagerConfig {
@@ -45,13 +70,19 @@ func (config *GonovateConfig) GetMergedManagerConfig(managerId string, managerTy
 	for _, rule := range config.Rules {
 		if rule.Matches != nil {
 			// ManagerId
-			if len(rule.Matches.Managers) > 0 && !slices.Contains(rule.Matches.Managers, managerId) {
+			if len(rule.Matches.Managers) > 0 && !slices.ContainsFunc(rule.Matches.Managers, func(matchId string) bool { return matchStringMatches(managerId, matchId) }) {
+				continue
+			}
+			if slices.ContainsFunc(rule.Matches.ExcludeManagers, func(matchId string) bool { return matchStringMatches(managerId, matchId) }) {
 				continue
 			}
 			// ManagerTypes
 			if len(rule.Matches.ManagerTypes) > 0 && !slices.Contains(rule.Matches.ManagerTypes, managerType) {
 				continue
 			}
+			if slices.Contains(rule.Matches.ExcludeManagerTypes, managerType) {
+				continue
+			}
 		}
 		mergedManagerConfig.MergeWith(rule.ManagerConfig)
 	}
@@ -82,10 +113,11 @@ func (config *GonovateConfig) GetManager(managerId string, managerType common.Ma
 }
 
 // Creates a datasource out of the config of the given datasource type.
-func (config *GonovateConfig) GetDatasource(datasourceType common.DatasourceType, logger *slog.Logger, cache cache.Cache[[]*common.ReleaseInfo]) (common.IDatasource, error) {
+func (config *GonovateConfig) GetDatasource(datasourceType common.DatasourceType, logger *slog.Logger, cache cache.Cache[[]*common.ReleaseInfo], gitHubApp *common.GitHubApp) (common.IDatasource, error) {
 	datasourceSettings := &common.DatasourceSettings{
 		Logger:    logger,
 		HostRules: config.HostRules,
+		GitHubApp: gitHubApp,
 		Cache:     cache,
 	}
 	return datasources.GetDatasource(datasourceType, datasourceSettings)
@@ -107,53 +139,8 @@ func (config *GonovateConfig) ApplyToDependency(dependency *common.Dependency) e
 }
 
 func (config *GonovateConfig) applyRulesToDependency(dependency *common.Dependency) {
-	// Get the config of the manager for this dependency
-	var managerConfig *Manager
-	if dependency.ManagerInfo != nil && dependency.ManagerInfo.ManagerId != "" {
-		managerConfig = config.GetManagerConfigById(dependency.ManagerInfo.ManagerId)
-	}
-
-	// Prepare the merged settings
-	mergedDependencyConfig := &DependencyConfig{}
-
 	// Search for matching rules and merge them
-	for _, rule := range config.Rules {
-		if rule.Matches != nil {
-			// Manager related matches
-			if managerConfig != nil {
-				// ManagerIds
-				if len(rule.Matches.Managers) > 0 && slices.IndexFunc(rule.Matches.Managers, func(matchId string) bool {
-					return matchStringMatches(managerConfig.Id, matchId)
-				}) < 0 {
-					continue
-				}
-				// ManagerTypes
-				if len(rule.Matches.ManagerTypes) > 0 && !slices.Contains(rule.Matches.ManagerTypes, managerConfig.Type) {
-					continue
-				}
-			}
-			// Files
-			ok, _ := common.FilePathMatchesPattern(dependency.FilePath, rule.Matches.Files...)
-			if len(rule.Matches.Files) > 0 && !ok {
-				continue
-			}
-			// DependencyNames
-			if len(rule.Matches.DependencyNames) > 0 && slices.IndexFunc(rule.Matches.DependencyNames, func(matchName string) bool {
-				return matchStringMatches(dependency.Name, matchName)
-			}) < 0 {
-				continue
-			}
-			// Datasources
-			datasource := dependency.Datasource
-			if datasource == "" {
-				datasource = mergedDependencyConfig.Datasource
-			}
-			if len(rule.Matches.Datasources) > 0 && slices.IndexFunc(rule.Matches.Datasources, func(ds common.DatasourceType) bool { return ds == datasource }) < 0 {
-				continue
-			}
-		}
-		mergedDependencyConfig.MergeWith(rule.DependencyConfig)
-	}
+	mergedDependencyConfig := config.mergeRulesForDependency(dependency, nil, nil)
 
 	// Apply the rule settings where the dependency has no value yet (the plain dependency settings have priority)
 	if dependency.Name == "" {
@@ -184,6 +171,13 @@ func (config *GonovateConfig) applyRulesToDependency(dependency *common.Dependen
 	if dependency.IgnoreNonMatching == nil {
 		dependency.IgnoreNonMatching = mergedDependencyConfig.IgnoreNonMatching
 	}
+	if dependency.MinimumReleaseAge == "" {
+		dependency.MinimumReleaseAge = mergedDependencyConfig.MinimumReleaseAge
+	}
+	if dependency.AllowedVersions == "" {
+		dependency.AllowedVersions = mergedDependencyConfig.AllowedVersions
+	}
+	dependency.IgnoredVersions = lo.Union(dependency.IgnoredVersions, mergedDependencyConfig.IgnoredVersions)
 	dependency.PostUpgradeReplacements = lo.Union(dependency.PostUpgradeReplacements, mergedDependencyConfig.PostUpgradeReplacements)
 	if dependency.GroupName == "" {
 		dependency.GroupName = mergedDependencyConfig.GroupName
@@ -200,12 +194,224 @@ func (config *GonovateConfig) applyRulesToDependency(dependency *common.Dependen
 	if dependency.BranchNameTemplate == "" {
 		dependency.BranchNameTemplate = mergedDependencyConfig.BranchNameTemplate
 	}
+	if dependency.PrBodyTemplate == "" {
+		dependency.PrBodyTemplate = mergedDependencyConfig.PrBodyTemplate
+	}
+	if dependency.SeparateUpdateTypes == nil {
+		dependency.SeparateUpdateTypes = mergedDependencyConfig.SeparateUpdateTypes
+	}
+	if dependency.RequireApproval == nil {
+		dependency.RequireApproval = mergedDependencyConfig.RequireApproval
+	}
+	if dependency.Automerge == nil {
+		dependency.Automerge = mergedDependencyConfig.Automerge
+	}
+	if dependency.AutomergeStrategy == "" {
+		dependency.AutomergeStrategy = mergedDependencyConfig.AutomergeStrategy
+	}
+	if dependency.PlatformAutomerge == nil {
+		dependency.PlatformAutomerge = mergedDependencyConfig.PlatformAutomerge
+	}
+	if dependency.SemanticCommitType == "" {
+		dependency.SemanticCommitType = mergedDependencyConfig.SemanticCommitType
+	}
+	if dependency.SemanticCommitScope == "" {
+		dependency.SemanticCommitScope = mergedDependencyConfig.SemanticCommitScope
+	}
+}
+
+// The settings (json names) which can be changed by rules that are specific for an update.
+var updateSpecificSettings = []string{"skip", "skipReason", "groupName", "labels", "reviewers", "titleTemplate", "branchNameTemplate", "prBodyTemplate", "separateUpdateTypes", "requireApproval", "automerge", "automergeStrategy", "platformAutomerge", "semanticCommitType", "semanticCommitScope", "postUpgradeReplacements"}
+
+// Applies the rules which are specific for an update (eg. matching update types) to a copy of the dependency.
+// Only the settings which are relevant for the branch and MR/PR can be changed per update.
+// Returns the dependency itself if no such rule changes anything.
+func (config *GonovateConfig) ApplyToDependencyUpdate(dependency *common.Dependency, release *common.ReleaseInfo) *common.Dependency {
+	if !slices.ContainsFunc(config.Rules, func(rule *Rule) bool { return rule.Matches.IsUpdateSpecific() }) {
+		return dependency
+	}
+	// Compare the settings with and without the update to find the ones that changed because of the update
+	baseConfig := config.mergeRulesForDependency(dependency, nil, nil)
+	updateConfig := config.mergeRulesForDependency(dependency, release, nil)
+
+	updateDependency := *dependency
+	changed := false
+	overrideIfChanged(&updateDependency.Skip, baseConfig.Skip, updateConfig.Skip, &changed)
+	overrideIfChanged(&updateDependency.SkipReason, baseConfig.SkipReason, updateConfig.SkipReason, &changed)
+	overrideIfChanged(&updateDependency.GroupName, baseConfig.GroupName, updateConfig.GroupName, &changed)
+	overrideIfChanged(&updateDependency.Labels, baseConfig.Labels, updateConfig.Labels, &changed)
+	overrideIfChanged(&updateDependency.Reviewers, baseConfig.Reviewers, updateConfig.Reviewers, &changed)
+	overrideIfChanged(&updateDependency.TitleTemplate, baseConfig.TitleTemplate, updateConfig.TitleTemplate, &changed)
+	overrideIfChanged(&updateDependency.BranchNameTemplate, baseConfig.BranchNameTemplate, updateConfig.BranchNameTemplate, &changed)
+	overrideIfChanged(&updateDependency.PrBodyTemplate, baseConfig.PrBodyTemplate, updateConfig.PrBodyTemplate, &changed)
+	overrideIfChanged(&updateDependency.SeparateUpdateTypes, baseConfig.SeparateUpdateTypes, updateConfig.SeparateUpdateTypes, &changed)
+	overrideIfChanged(&updateDependency.RequireApproval, baseConfig.RequireApproval, updateConfig.RequireApproval, &changed)
+	overrideIfChanged(&updateDependency.Automerge, baseConfig.Automerge, updateConfig.Automerge, &changed)
+	overrideIfChanged(&updateDependency.AutomergeStrategy, baseConfig.AutomergeStrategy, updateConfig.AutomergeStrategy, &changed)
+	overrideIfChanged(&updateDependency.PlatformAutomerge, baseConfig.PlatformAutomerge, updateConfig.PlatformAutomerge, &changed)
+	overrideIfChanged(&updateDependency.SemanticCommitType, baseConfig.SemanticCommitType, updateConfig.SemanticCommitType, &changed)
+	overrideIfChanged(&updateDependency.SemanticCommitScope, baseConfig.SemanticCommitScope, updateConfig.SemanticCommitScope, &changed)
+	overrideIfChanged(&updateDependency.PostUpgradeReplacements, baseConfig.PostUpgradeReplacements, updateConfig.PostUpgradeReplacements, &changed)
+	if !changed {
+		return dependency
+	}
+	return &updateDependency
+}
+
+// Merges the dependency configs of all rules that match the dependency.
+// If a release is given, the rules which are specific for an update are evaluated as well, otherwise they never match.
+// The optional callback is called for each rule with the name of the criterion that rejected the rule (or empty if it matched).
+func (config *GonovateConfig) mergeRulesForDependency(dependency *common.Dependency, release *common.ReleaseInfo, onRule func(index int, rule *Rule, rejectedBy string)) *DependencyConfig {
+	// Get the config of the manager for this dependency
+	var managerConfig *Manager
+	if dependency.ManagerInfo != nil && dependency.ManagerInfo.ManagerId != "" {
+		managerConfig = config.GetManagerConfigById(dependency.ManagerInfo.ManagerId)
+	}
+
+	// Prepare the merged settings
+	mergedDependencyConfig := &DependencyConfig{}
+
+	// Search for matching rules and merge them
+	for i, rule := range config.Rules {
+		// Use the datasource from the rules if the dependency has none
+		datasource := dependency.Datasource
+		if datasource == "" {
+			datasource = mergedDependencyConfig.Datasource
+		}
+		rejectedBy := ruleRejectsDependency(rule, managerConfig, dependency, datasource, release)
+		if onRule != nil {
+			onRule(i, rule, rejectedBy)
+		}
+		if rejectedBy != "" {
+			continue
+		}
+		mergedDependencyConfig.MergeWith(rule.DependencyConfig)
+	}
+	return mergedDependencyConfig
 }
 
+// Checks if the rule applies to the dependency. Returns the name of the criterion that rejected the rule or an empty string if the rule matches.
+func ruleRejectsDependency(rule *Rule, managerConfig *Manager, dependency *common.Dependency, datasource common.DatasourceType, release *common.ReleaseInfo) string {
+	if rule.Matches == nil {
+		return ""
+	}
+	// Manager related matches
+	if managerConfig != nil {
+		// ManagerIds
+		if len(rule.Matches.Managers) > 0 && slices.IndexFunc(rule.Matches.Managers, func(matchId string) bool {
+			return matchStringMatches(managerConfig.Id, matchId)
+		}) < 0 {
+			return "managers"
+		}
+		if slices.ContainsFunc(rule.Matches.ExcludeManagers, func(matchId string) bool { return matchStringMatches(managerConfig.Id, matchId) }) {
+			return "excludeManagers"
+		}
+		// ManagerTypes
+		if len(rule.Matches.ManagerTypes) > 0 && !slices.Contains(rule.Matches.ManagerTypes, managerConfig.Type) {
+			return "managerTypes"
+		}
+		if slices.Contains(rule.Matches.ExcludeManagerTypes, managerConfig.Type) {
+			return "excludeManagerTypes"
+		}
+	}
+	// Files
+	ok, _ := common.FilePathMatchesPattern(dependency.FilePath, rule.Matches.Files...)
+	if len(rule.Matches.Files) > 0 && !ok {
+		return "files"
+	}
+	if excluded, _ := common.FilePathMatchesPattern(dependency.FilePath, rule.Matches.ExcludeFiles...); len(rule.Matches.ExcludeFiles) > 0 && excluded {
+		return "excludeFiles"
+	}
+	// DependencyNames
+	if len(rule.Matches.DependencyNames) > 0 && slices.IndexFunc(rule.Matches.DependencyNames, func(matchName string) bool {
+		return matchStringMatches(dependency.Name, matchName)
+	}) < 0 {
+		return "dependencyNames"
+	}
+	if slices.ContainsFunc(rule.Matches.ExcludeDependencyNames, func(matchName string) bool { return matchStringMatches(dependency.Name, matchName) }) {
+		return "excludeDependencyNames"
+	}
+	// Datasources
+	if len(rule.Matches.Datasources) > 0 && slices.IndexFunc(rule.Matches.Datasources, func(ds common.DatasourceType) bool { return ds == datasource }) < 0 {
+		return "datasources"
+	}
+	if slices.Contains(rule.Matches.ExcludeDatasources, datasource) {
+		return "excludeDatasources"
+	}
+	// DependencyTypes
+	if len(rule.Matches.DependencyTypes) > 0 && !slices.ContainsFunc(rule.Matches.DependencyTypes, func(matchType string) bool {
+		return matchStringMatches(dependency.Type, matchType)
+	}) {
+		return "dependencyTypes"
+	}
+	if slices.ContainsFunc(rule.Matches.ExcludeDependencyTypes, func(matchType string) bool { return matchStringMatches(dependency.Type, matchType) }) {
+		return "excludeDependencyTypes"
+	}
+	// CurrentVersions
+	if len(rule.Matches.CurrentVersions) > 0 && !slices.ContainsFunc(rule.Matches.CurrentVersions, func(constraint string) bool {
+		return versionConstraintMatches(dependency.Version, constraint)
+	}) {
+		return "currentVersions"
+	}
+	if slices.ContainsFunc(rule.Matches.ExcludeCurrentVersions, func(constraint string) bool { return versionConstraintMatches(dependency.Version, constraint) }) {
+		return "excludeCurrentVersions"
+	}
+	// UpdateTypes (only match if there is an update to check)
+	if len(rule.Matches.UpdateTypes) > 0 && (release == nil || !slices.Contains(rule.Matches.UpdateTypes, release.UpdateType)) {
+		return "updateTypes"
+	}
+	if len(rule.Matches.ExcludeUpdateTypes) > 0 && (release == nil || slices.Contains(rule.Matches.ExcludeUpdateTypes, release.UpdateType)) {
+		return "excludeUpdateTypes"
+	}
+	return ""
+}
+
+// Checks if the version matches the constraint. Invalid constraints never match.
+func versionConstraintMatches(version string, constraint string) bool {
+	versionConstraint, err := common.ParseVersionConstraint(constraint, nil)
+	if err != nil {
+		return false
+	}
+	return versionConstraint.AllowsVersion(version)
+}
+
+// Sets the target to the update value if it differs from the base value.
+func overrideIfChanged[T any](target *T, baseValue T, updateValue T, changed *bool) {
+	if reflect.DeepEqual(baseValue, updateValue) {
+		return
+	}
+	*target = updateValue
+	*changed = true
+}
+
+// Checks if the input matches the string which can either be plain or a regexp when prefixed with "re:".
+// Invalid regexps never match, they are reported when the config is loaded.
 func matchStringMatches(input string, matchString string) bool {
 	if strings.HasPrefix(matchString, "re:") {
-		re := regexp.MustCompile(matchString[3:])
+		re, err := compileMatchString(matchString)
+		if err != nil {
+			return false
+		}
 		return re.MatchString(input)
 	}
 	return input == matchString
 }
+
+// Cache for the compiled regexps of the match strings as they are used for each dependency.
+var matchStringRegexps sync.Map
+
+// Compiles the regexp of a match string prefixed with "re:". Returns nil for plain match strings.
+func compileMatchString(matchString string) (*regexp.Regexp, error) {
+	if !strings.HasPrefix(matchString, "re:") {
+		return nil, nil
+	}
+	if re, ok := matchStringRegexps.Load(matchString); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(matchString[3:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid regexp '%s': %w", matchString[3:], err)
+	}
+	matchStringRegexps.Store(matchString, re)
+	return re, nil
+}
@@ -88,3 +88,133 @@ func TestApplyToDependencyWithExistingDatasource(t *testing.T) {
 	assert.Equal(common.DATASOURCE_TYPE_ARTIFACTORY, dependency.Datasource)
 	assert.Equal("", dependency.GroupName)
 }
+
+func TestApplyToDependencyWithNewMatchers(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &GonovateConfig{
+		Managers: []*Manager{
+			{Id: "npm", Type: common.MANAGER_TYPE_REGEX},
+		},
+		Rules: []*Rule{
+			{
+				Matches:          &RuleMatch{DependencyTypes: []string{"dev"}},
+				DependencyConfig: &DependencyConfig{GroupName: "dev-dependencies"},
+			},
+			{
+				Matches:          &RuleMatch{ExcludeDependencyNames: []string{"re:^@types/"}},
+				DependencyConfig: &DependencyConfig{Labels: []string{"no-types"}},
+			},
+			{
+				Matches:          &RuleMatch{CurrentVersions: []string{"<1"}},
+				DependencyConfig: &DependencyConfig{Reviewers: []string{"zero-ver"}},
+			},
+			{
+				Matches:          &RuleMatch{ExcludeFiles: []string{"legacy/**"}},
+				DependencyConfig: &DependencyConfig{TitleTemplate: "not-legacy"},
+			},
+		},
+	}
+
+	dependency := &common.Dependency{Name: "eslint", Version: "0.9.0", Type: "dev", FilePath: "package.json", ManagerInfo: &common.ManagerInfo{ManagerId: "npm"}}
+	assert.NoError(cfg.ApplyToDependency(dependency))
+	assert.Equal("dev-dependencies", dependency.GroupName)
+	assert.Equal([]string{"no-types"}, dependency.Labels)
+	assert.Equal([]string{"zero-ver"}, dependency.Reviewers)
+	assert.Equal("not-legacy", dependency.TitleTemplate)
+
+	dependency = &common.Dependency{Name: "@types/node", Version: "v20.1.0", Type: "direct", FilePath: "legacy/package.json", ManagerInfo: &common.ManagerInfo{ManagerId: "npm"}}
+	assert.NoError(cfg.ApplyToDependency(dependency))
+	assert.Equal("", dependency.GroupName)
+	assert.Empty(dependency.Labels)
+	assert.Empty(dependency.Reviewers)
+	assert.Equal("", dependency.TitleTemplate)
+}
+
+func TestApplyToDependencyUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &GonovateConfig{
+		Rules: []*Rule{
+			{
+				DependencyConfig: &DependencyConfig{Labels: []string{"dependencies"}},
+			},
+			{
+				Matches:          &RuleMatch{UpdateTypes: []common.UpdateType{common.UPDATE_TYPE_MAJOR}},
+				DependencyConfig: &DependencyConfig{Labels: []string{"major"}},
+			},
+			{
+				Matches:          &RuleMatch{DependencyTypes: []string{"dev"}, ExcludeUpdateTypes: []common.UpdateType{common.UPDATE_TYPE_MAJOR}},
+				DependencyConfig: &DependencyConfig{GroupName: "dev-non-major"},
+			},
+		},
+	}
+
+	dependency := &common.Dependency{Name: "eslint", Version: "8.1.0", Type: "dev", FilePath: "package.json"}
+	assert.NoError(cfg.ApplyToDependency(dependency))
+	// Rules for update types are not applied to the dependency itself
+	assert.Equal([]string{"dependencies"}, dependency.Labels)
+	assert.Equal("", dependency.GroupName)
+
+	majorDependency := cfg.ApplyToDependencyUpdate(dependency, &common.ReleaseInfo{VersionString: "9.0.0", UpdateType: common.UPDATE_TYPE_MAJOR})
+	assert.NotSame(dependency, majorDependency)
+	assert.Equal([]string{"major"}, majorDependency.Labels)
+	assert.Equal("", majorDependency.GroupName)
+
+	minorDependency := cfg.ApplyToDependencyUpdate(dependency, &common.ReleaseInfo{VersionString: "8.2.0", UpdateType: common.UPDATE_TYPE_MINOR})
+	assert.Equal([]string{"dependencies"}, minorDependency.Labels)
+	assert.Equal("dev-non-major", minorDependency.GroupName)
+
+	// The dependency itself is unchanged
+	assert.Equal([]string{"dependencies"}, dependency.Labels)
+	assert.Equal("", dependency.GroupName)
+
+	// Without update specific rules, the same dependency is returned
+	cfg.Rules = cfg.Rules[:1]
+	assert.Same(dependency, cfg.ApplyToDependencyUpdate(dependency, &common.ReleaseInfo{VersionString: "9.0.0", UpdateType: common.UPDATE_TYPE_MAJOR}))
+}
+
+func TestApplyToDependencyUpdate_Automerge(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &GonovateConfig{
+		Rules: []*Rule{
+			{
+				DependencyConfig: &DependencyConfig{AutomergeStrategy: common.AUTOMERGE_STRATEGY_SQUASH},
+			},
+			{
+				Matches:          &RuleMatch{UpdateTypes: []common.UpdateType{common.UPDATE_TYPE_PATCH}},
+				DependencyConfig: &DependencyConfig{Automerge: common.TruePtr},
+			},
+		},
+	}
+
+	dependency := &common.Dependency{Name: "internal/base", Version: "1.2.3", FilePath: "Dockerfile"}
+	assert.NoError(cfg.ApplyToDependency(dependency))
+	assert.Nil(dependency.Automerge)
+	assert.Equal(common.AUTOMERGE_STRATEGY_SQUASH, dependency.AutomergeStrategy)
+
+	patchDependency := cfg.ApplyToDependencyUpdate(dependency, &common.ReleaseInfo{VersionString: "1.2.4", UpdateType: common.UPDATE_TYPE_PATCH})
+	assert.True(*patchDependency.Automerge)
+	assert.Equal(common.AUTOMERGE_STRATEGY_SQUASH, patchDependency.AutomergeStrategy)
+
+	minorDependency := cfg.ApplyToDependencyUpdate(dependency, &common.ReleaseInfo{VersionString: "1.3.0", UpdateType: common.UPDATE_TYPE_MINOR})
+	assert.Nil(minorDependency.Automerge)
+}
+
+func TestGetMergedManagerConfig_Regex(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &GonovateConfig{
+		Rules: []*Rule{
+			{
+				Matches:       &RuleMatch{Managers: []string{"re:^docker-"}, ExcludeManagers: []string{"re:-legacy$"}},
+				ManagerConfig: &ManagerConfig{Disabled: common.TruePtr},
+			},
+		},
+	}
+
+	assert.True(*cfg.GetMergedManagerConfig("docker-main", common.MANAGER_TYPE_DOCKERFILE).Disabled)
+	assert.Nil(cfg.GetMergedManagerConfig("docker-legacy", common.MANAGER_TYPE_DOCKERFILE).Disabled)
+	assert.Nil(cfg.GetMergedManagerConfig("helm", common.MANAGER_TYPE_HELM).Disabled)
+}
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Returns the origin (like "preset:docker" or "local:gonovate.json") of the value with the given path.
+// Paths use the json names of the fields, list items are identified by their value, id or property (e.g. "managers[docker].type").
+func (c *GonovateConfig) OriginOf(path string) string {
+	return c.origins[path]
+}
+
+// Writes the config as yaml and annotates each value with the origin it came from.
+func (c *GonovateConfig) PrintWithOrigins(writer io.Writer) error {
+	printer := &originPrinter{config: c, builder: &strings.Builder{}}
+	printer.printStruct(reflect.ValueOf(c).Elem(), "", 0, false)
+	_, err := io.WriteString(writer, printer.builder.String())
+	return err
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+// Records the given origin for all values which are set in the config.
+func (c *GonovateConfig) stampOrigin(origin string) {
+	c.origins = map[string]string{}
+	walkConfigValues(reflect.ValueOf(c).Elem(), "", func(path string) {
+		c.origins[path] = origin
+	})
+}
+
+// Merges the origins of another config into this config, following the same rules as the values:
+// values are overwritten, except for items of lists which keep the origin where they were first added.
+// Lists which are replaced as a whole (like labels) get the origin of the other config for all their items.
+func (c *GonovateConfig) mergeOriginsWith(other *GonovateConfig) {
+	if len(other.origins) == 0 {
+		return
+	}
+	if c.origins == nil {
+		c.origins = map[string]string{}
+	}
+	listPaths := []string{}
+	collectReplacingLists(reflect.ValueOf(other).Elem(), "", &listPaths)
+	for _, listPath := range listPaths {
+		for path := range c.origins {
+			if strings.HasPrefix(path, listPath+"[") {
+				delete(c.origins, path)
+			}
+		}
+	}
+	for path, origin := range other.origins {
+		if _, exists := c.origins[path]; exists && strings.HasSuffix(path, "]") {
+			continue
+		}
+		c.origins[path] = origin
+	}
+}
+
+// Calls the callback for each leaf value that is set.
+func walkConfigValues(value reflect.Value, path string, callback func(path string)) {
+	if isEmptyConfigValue(value) {
+		return
+	}
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Struct:
+		forEachConfigField(value, func(name string, field reflect.Value) {
+			walkConfigValues(field, joinConfigPath(path, name), callback)
+		})
+	case reflect.Map:
+		for _, key := range sortedMapKeys(value) {
+			walkConfigValues(value.MapIndex(key), joinConfigPath(path, key.String()), callback)
+		}
+	case reflect.Slice:
+		for i := range value.Len() {
+			walkConfigValues(value.Index(i), configItemPath(path, value.Index(i)), callback)
+		}
+	default:
+		callback(path)
+	}
+}
+
+// Collects the paths of the lists which replace the lists of the config they are merged into.
+func collectReplacingLists(value reflect.Value, path string, listPaths *[]string) {
+	if isEmptyConfigValue(value) {
+		return
+	}
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Struct:
+		if managerConfig, ok := value.Interface().(ManagerConfig); ok && managerConfig.ClearFilePatterns != nil && *managerConfig.ClearFilePatterns {
+			*listPaths = append(*listPaths, joinConfigPath(path, "filePatterns"))
+		}
+		forEachConfigField(value, func(name string, field reflect.Value) {
+			if field.Kind() == reflect.Slice && !field.IsNil() && slices.Contains(replacingConfigLists, name) {
+				*listPaths = append(*listPaths, joinConfigPath(path, name))
+				return
+			}
+			collectReplacingLists(field, joinConfigPath(path, name), listPaths)
+		})
+	case reflect.Map:
+		for _, key := range sortedMapKeys(value) {
+			collectReplacingLists(value.MapIndex(key), joinConfigPath(path, key.String()), listPaths)
+		}
+	case reflect.Slice:
+		for i := range value.Len() {
+			collectReplacingLists(value.Index(i), configItemPath(path, value.Index(i)), listPaths)
+		}
+	}
+}
+
+// The names of the lists which are replaced instead of merged when merging configs.
+var replacingConfigLists = []string{"labels", "reviewers", "updateTypes"}
+
+// Returns true if the value is not set or is a container without any set values.
+func isEmptyConfigValue(value reflect.Value) bool {
+	if !value.IsValid() {
+		return true
+	}
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil() || (value.Elem().Kind() == reflect.Struct && isEmptyConfigValue(value.Elem()))
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	case reflect.Struct:
+		empty := true
+		forEachConfigField(value, func(_ string, field reflect.Value) {
+			empty = empty && isEmptyConfigValue(field)
+		})
+		return empty
+	}
+	return value.IsZero()
+}
+
+// Loops thru the exported fields of a struct with their json names.
+func forEachConfigField(value reflect.Value, callback func(name string, field reflect.Value)) {
+	for i := range value.NumField() {
+		fieldType := value.Type().Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(fieldType.Tag.Get("json"), ",")
+		if name == "-" || name == "$schema" {
+			continue
+		}
+		if name == "" {
+			name = fieldType.Name
+		}
+		callback(name, value.Field(i))
+	}
+}
+
+func joinConfigPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// Builds the path of a list item. Items are identified by a stable key where possible,
+// otherwise (like for rules which are only ever appended) by the identity of the object.
+func configItemPath(path string, item reflect.Value) string {
+	key := ""
+	switch typedItem := item.Interface().(type) {
+	case *Manager:
+		key = typedItem.Id
+	case *DevcontainerFeatureDependency:
+		key = typedItem.Property
+	default:
+		if item.Kind() == reflect.Pointer {
+			key = fmt.Sprintf("%p", typedItem)
+		} else {
+			key = fmt.Sprint(typedItem)
+		}
+	}
+	return fmt.Sprintf("%s[%s]", path, key)
+}
+
+func sortedMapKeys(value reflect.Value) []reflect.Value {
+	return slices.SortedFunc(slices.Values(value.MapKeys()), func(a, b reflect.Value) int {
+		return strings.Compare(a.String(), b.String())
+	})
+}
+
+// Helper to print the config as annotated yaml.
+type originPrinter struct {
+	config  *GonovateConfig
+	builder *strings.Builder
+}
+
+// Prints the fields of a struct. If the struct is a list item, the first field is prefixed with the list marker.
+func (p *originPrinter) printStruct(value reflect.Value, path string, indent int, isListItem bool) {
+	first := true
+	forEachConfigField(value, func(name string, field reflect.Value) {
+		if isEmptyConfigValue(field) {
+			return
+		}
+		prefix := strings.Repeat("  ", indent)
+		if isListItem {
+			prefix = strings.Repeat("  ", indent-1) + "  "
+			if first {
+				prefix = strings.Repeat("  ", indent-1) + "- "
+			}
+		}
+		first = false
+		p.printEntry(prefix, name, field, joinConfigPath(path, name), indent)
+	})
+}
+
+// Prints a named entry (struct field or map entry).
+func (p *originPrinter) printEntry(prefix string, name string, value reflect.Value, path string, indent int) {
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Struct:
+		fmt.Fprintf(p.builder, "%s%s:\n", prefix, name)
+		p.printStruct(value, path, indent+1, false)
+	case reflect.Map:
+		fmt.Fprintf(p.builder, "%s%s:\n", prefix, name)
+		for _, key := range sortedMapKeys(value) {
+			entry := value.MapIndex(key)
+			if isEmptyConfigValue(entry) {
+				continue
+			}
+			p.printEntry(strings.Repeat("  ", indent+1), key.String(), entry, joinConfigPath(path, key.String()), indent+1)
+		}
+	case reflect.Slice:
+		fmt.Fprintf(p.builder, "%s%s:\n", prefix, name)
+		for i := range value.Len() {
+			item := value.Index(i)
+			if isEmptyConfigValue(item) {
+				continue
+			}
+			itemPath := configItemPath(path, item)
+			if reflect.Indirect(item).Kind() == reflect.Struct {
+				p.printStruct(reflect.Indirect(item), itemPath, indent+2, true)
+			} else {
+				p.printScalar(strings.Repeat("  ", indent+1)+"- ", reflect.Indirect(item), itemPath)
+			}
+		}
+	default:
+		p.printScalar(fmt.Sprintf("%s%s: ", prefix, name), value, path)
+	}
+}
+
+func (p *originPrinter) printScalar(prefix string, value reflect.Value, path string) {
+	formatted, err := yaml.Marshal(value.Interface())
+	text := strings.TrimSpace(string(formatted))
+	if err != nil || strings.Contains(text, "\n") {
+		// Keep the value on a single line
+		text = strconv.Quote(fmt.Sprint(value.Interface()))
+	}
+	p.builder.WriteString(prefix + text)
+	if origin := p.config.origins[path]; origin != "" {
+		p.builder.WriteString("  # " + origin)
+	}
+	p.builder.WriteString("\n")
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrigins(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tempDir := t.TempDir()
+	basePath := filepath.Join(tempDir, "base.json")
+	extraPath := filepath.Join(tempDir, "extra.yaml")
+	require.NoError(os.WriteFile(basePath, []byte(`{
+  "extends": ["defaults"],
+  "platform": { "type": "noop", "baseBranch": "develop" },
+  "ignorePatterns": ["a"],
+  "managers": [{ "id": "docker", "type": "dockerfile", "dependencyConfig": { "groupName": "docker" } }]
+}`), os.ModePerm))
+	require.NoError(os.WriteFile(extraPath, []byte(`
+platform:
+  baseBranch: release
+ignorePatterns:
+  - a
+  - b
+`), os.ModePerm))
+
+	configLoader := NewConfigLoader(slog.Default())
+	cfg, err := configLoader.Load(basePath)
+	require.NoError(err)
+	extraCfg, err := configLoader.Load(extraPath)
+	require.NoError(err)
+	cfg.MergeWith(extraCfg)
+
+	baseOrigin := "local:" + basePath
+	extraOrigin := "local:" + extraPath
+	assert.Equal(baseOrigin, cfg.OriginOf("platform.type"))
+	assert.Equal(extraOrigin, cfg.OriginOf("platform.baseBranch"))
+	assert.Equal("preset:defaults", cfg.OriginOf("platform.branchPrefix"))
+	assert.Equal(baseOrigin, cfg.OriginOf("ignorePatterns[a]"))
+	assert.Equal(extraOrigin, cfg.OriginOf("ignorePatterns[b]"))
+	assert.Equal("preset:defaults", cfg.OriginOf("ignorePatterns[**/.git]"))
+	assert.Equal(baseOrigin, cfg.OriginOf("managers[docker].type"))
+
+	// Check the printed output
+	output := &strings.Builder{}
+	require.NoError(cfg.PrintWithOrigins(output))
+	assert.Contains(output.String(), "  baseBranch: release  # "+extraOrigin+"\n")
+	assert.Contains(output.String(), "  - id: docker  # "+baseOrigin+"\n")
+	assert.Contains(output.String(), "      groupName: docker  # "+baseOrigin+"\n")
+}
+
+func TestOriginsWithoutLoader(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &GonovateConfig{}
+	cfg.MergeWith(&GonovateConfig{IgnorePatterns: []string{"a"}})
+	assert.Equal("", cfg.OriginOf("ignorePatterns[a]"))
+
+	output := &strings.Builder{}
+	assert.NoError(cfg.PrintWithOrigins(output))
+	assert.Equal("ignorePatterns:\n  - a\n", output.String())
+}
+
+func TestOriginsOfReplacedLists(t *testing.T) {
+	assert := assert.New(t)
+
+	presetCfg := &GonovateConfig{Managers: []*Manager{{
+		Id:               "docker",
+		ManagerConfig:    &ManagerConfig{FilePatterns: []string{"Dockerfile", "*.dockerfile"}},
+		DependencyConfig: &DependencyConfig{Labels: []string{"docker", "deps"}, UpdateTypes: []common.UpdateType{common.UPDATE_TYPE_MINOR}},
+	}}}
+	presetCfg.stampOrigin("preset:docker")
+	localCfg := &GonovateConfig{Managers: []*Manager{{
+		Id:               "docker",
+		ManagerConfig:    &ManagerConfig{ClearFilePatterns: common.TruePtr, FilePatterns: []string{"Dockerfile"}},
+		DependencyConfig: &DependencyConfig{Labels: []string{"docker"}, Reviewers: []string{"me"}},
+	}}}
+	localCfg.stampOrigin("local:gonovate.json")
+
+	cfg := &GonovateConfig{}
+	cfg.MergeWith(presetCfg)
+	cfg.MergeWith(localCfg)
+
+	// The replaced lists only contain items of the overriding config
+	assert.Equal("local:gonovate.json", cfg.OriginOf("managers[docker].dependencyConfig.labels[docker]"))
+	assert.Equal("", cfg.OriginOf("managers[docker].dependencyConfig.labels[deps]"))
+	assert.Equal("local:gonovate.json", cfg.OriginOf("managers[docker].managerConfig.filePatterns[Dockerfile]"))
+	assert.Equal("", cfg.OriginOf("managers[docker].managerConfig.filePatterns[*.dockerfile]"))
+	// Lists which are not replaced keep their origins
+	assert.Equal("preset:docker", cfg.OriginOf("managers[docker].dependencyConfig.updateTypes[minor]"))
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/roemer/gonovate/pkg/common"
+)
+
+// The id of the JSON Schema draft that is generated.
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// The known values of the string based types which are added to the schema as enums.
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeFor[common.PlatformType]():      enumValues(common.AllPlatformTypes),
+	reflect.TypeFor[common.ManagerType]():       enumValues(common.AllManagerTypes),
+	reflect.TypeFor[common.DatasourceType]():    enumValues(common.AllDatasourceTypes),
+	reflect.TypeFor[common.UpdateType]():        enumValues(common.AllUpdateTypes),
+	reflect.TypeFor[common.AutomergeStrategy](): enumValues(common.AllAutomergeStrategies),
+	reflect.TypeFor[common.RebaseWhen]():        enumValues(common.AllRebaseWhen),
+	reflect.TypeFor[common.SemanticCommits]():   enumValues(common.AllSemanticCommits),
+}
+
+// Converts the values of a string based type to strings.
+func enumValues[T ~string](values []T) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = string(value)
+	}
+	return result
+}
+
+// Represents a (simplified) JSON Schema node.
+type JsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Id                   string                 `json:"$id,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Properties           map[string]*JsonSchema `json:"properties,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Items                *JsonSchema            `json:"items,omitempty"`
+	Defs                 map[string]*JsonSchema `json:"$defs,omitempty"`
+}
+
+// Generates the JSON Schema of the gonovate config out of the config types.
+func GenerateSchema() *JsonSchema {
+	generator := &schemaGenerator{defs: map[string]*JsonSchema{}}
+	root := generator.schemaForStruct(reflect.TypeFor[GonovateConfig]())
+	root.Schema = schemaDraft
+	root.Id = "https://github.com/roemer/gonovate/gonovate.schema.json"
+	root.Title = "gonovate configuration"
+	root.Defs = generator.defs
+	return root
+}
+
+// Generates the JSON Schema of the gonovate config as indented json.
+func GenerateSchemaJson() ([]byte, error) {
+	return json.MarshalIndent(GenerateSchema(), "", "  ")
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+type schemaGenerator struct {
+	defs map[string]*JsonSchema
+}
+
+func (g *schemaGenerator) schemaForType(t reflect.Type) *JsonSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if enumValues, ok := schemaEnums[t]; ok {
+		return &JsonSchema{Type: "string", Enum: enumValues}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		// Structs are added as definitions and referenced
+		if _, ok := g.defs[t.Name()]; !ok {
+			// Reserve the name first to support recursive types
+			g.defs[t.Name()] = nil
+			g.defs[t.Name()] = g.schemaForStruct(t)
+		}
+		return &JsonSchema{Ref: "#/$defs/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		return &JsonSchema{Type: "array", Items: g.schemaForType(t.Elem())}
+	case reflect.Map:
+		return &JsonSchema{Type: "object", AdditionalProperties: g.schemaForType(t.Elem())}
+	case reflect.Bool:
+		return &JsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JsonSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JsonSchema{Type: "number"}
+	case reflect.String:
+		return &JsonSchema{Type: "string"}
+	}
+	// Anything else is not restricted
+	return &JsonSchema{}
+}
+
+func (g *schemaGenerator) schemaForStruct(t reflect.Type) *JsonSchema {
+	schema := &JsonSchema{
+		Type:                 "object",
+		Properties:           map[string]*JsonSchema{},
+		AdditionalProperties: false,
+	}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = g.schemaForType(field.Type)
+	}
+	return schema
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/roemer/gonovate/pkg/datasources"
+	"github.com/roemer/gonovate/pkg/managers"
+	"github.com/roemer/gonovate/pkg/platforms"
+	"github.com/roemer/gonovate/pkg/presets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSchema(t *testing.T) {
+	assert := assert.New(t)
+
+	schema := GenerateSchema()
+	assert.Equal("object", schema.Type)
+	assert.Equal(false, schema.AdditionalProperties)
+	assert.Equal("#/$defs/PlatformConfig", schema.Properties["platform"].Ref)
+	assert.Equal("array", schema.Properties["rules"].Type)
+	assert.Equal("#/$defs/Rule", schema.Properties["rules"].Items.Ref)
+	assert.Contains(schema.Defs, "HostRule")
+	assert.Contains(schema.Defs, "RuleMatch")
+	assert.Contains(schema.Defs["DependencyConfig"].Properties["updateTypes"].Items.Enum, "minor")
+	assert.Contains(schema.Defs["Manager"].Properties["type"].Enum, "regex")
+}
+
+func TestSchemaEnumsAreKnown(t *testing.T) {
+	assert := assert.New(t)
+
+	// Every known type in the schema and the validation must be supported by the factories
+	for _, platformType := range common.AllPlatformTypes {
+		_, err := platforms.GetPlatform(&common.PlatformSettings{Logger: slog.Default(), Platform: platformType})
+		assert.NoError(err, platformType)
+	}
+	for _, managerType := range common.AllManagerTypes {
+		_, err := managers.GetManager("test", managerType, &common.ManagerSettings{Logger: slog.Default()})
+		assert.NoError(err, managerType)
+	}
+	for _, datasourceType := range common.AllDatasourceTypes {
+		_, err := datasources.GetDatasource(datasourceType, &common.DatasourceSettings{Logger: slog.Default()})
+		assert.NoError(err, datasourceType)
+	}
+	assert.Len(schemaEnums[reflect.TypeFor[common.PlatformType]()], len(common.AllPlatformTypes))
+
+	// Every type supported by the factories must be known in the schema and the validation
+	assert.ElementsMatch(factoryCaseNames(t, "../platforms/base.go", "GetPlatform"), typeListNames(t, "AllPlatformTypes"))
+	assert.ElementsMatch(factoryCaseNames(t, "../managers/base.go", "GetManager"), typeListNames(t, "AllManagerTypes"))
+	assert.ElementsMatch(factoryCaseNames(t, "../datasources/base.go", "GetDatasource"), typeListNames(t, "AllDatasourceTypes"))
+}
+
+// Returns the names of the constants used in the cases of the switch in the given factory function.
+func factoryCaseNames(t *testing.T, filePath string, funcName string) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, nil, 0)
+	require.NoError(t, err)
+	names := []string{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if funcDecl, ok := node.(*ast.FuncDecl); ok && funcDecl.Name.Name != funcName {
+			return false
+		}
+		if caseClause, ok := node.(*ast.CaseClause); ok {
+			for _, expr := range caseClause.List {
+				if selector, ok := expr.(*ast.SelectorExpr); ok {
+					names = append(names, selector.Sel.Name)
+				}
+			}
+		}
+		return true
+	})
+	require.NotEmpty(t, names, funcName)
+	return names
+}
+
+// Returns the names of the constants in the given list of known types.
+func typeListNames(t *testing.T, listName string) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "../common/constants.go", nil, 0)
+	require.NoError(t, err)
+	names := []string{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		valueSpec, ok := node.(*ast.ValueSpec)
+		if !ok || len(valueSpec.Names) != 1 || valueSpec.Names[0].Name != listName {
+			return true
+		}
+		for _, element := range valueSpec.Values[0].(*ast.CompositeLit).Elts {
+			names = append(names, element.(*ast.Ident).Name)
+		}
+		return false
+	})
+	require.NotEmpty(t, names, listName)
+	return names
+}
+
+func TestPresetsMatchSchema(t *testing.T) {
+	require := require.New(t)
+
+	schema := GenerateSchema()
+	// Convert the schema into a generic structure to check the presets against
+	schemaJson, err := json.Marshal(schema)
+	require.NoError(err)
+	var genericSchema map[string]any
+	require.NoError(json.Unmarshal(schemaJson, &genericSchema))
+
+	files, err := fs.Glob(presets.Presets, "configs/*")
+	require.NoError(err)
+	require.NotEmpty(files)
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			content, err := presets.Presets.ReadFile(file)
+			require.NoError(err)
+			var preset any
+			if strings.HasSuffix(file, ".json") {
+				require.NoError(json.Unmarshal(content, &preset))
+			} else {
+				require.NoError(yaml.Unmarshal(content, &preset))
+			}
+			errors := validateAgainstSchema(genericSchema, genericSchema, preset, "")
+			assert.Empty(t, errors)
+		})
+	}
+}
+
+// A minimal validator which supports the parts of JSON Schema that the generator produces.
+func validateAgainstSchema(root map[string]any, schema map[string]any, value any, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		defName := strings.TrimPrefix(ref, "#/$defs/")
+		return validateAgainstSchema(root, root["$defs"].(map[string]any)[defName].(map[string]any), value, path)
+	}
+	if value == nil {
+		return nil
+	}
+	errors := []string{}
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected object", path)}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for key, propertyValue := range object {
+			if propertySchema, ok := properties[key]; ok {
+				errors = append(errors, validateAgainstSchema(root, propertySchema.(map[string]any), propertyValue, path+"."+key)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+				errors = append(errors, validateAgainstSchema(root, additional, propertyValue, path+"."+key)...)
+			} else if schema["additionalProperties"] == false {
+				errors = append(errors, fmt.Sprintf("%s: unknown property '%s'", path, key))
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected array", path)}
+		}
+		for i, item := range array {
+			errors = append(errors, validateAgainstSchema(root, schema["items"].(map[string]any), item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected string", path)}
+		}
+		if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, any(str)) {
+			errors = append(errors, fmt.Sprintf("%s: invalid value '%s'", path, str))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s: expected boolean", path)}
+		}
+	}
+	return errors
+}
+
+func TestSchemaReferenceIsAllowed(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir := t.TempDir()
+	jsonPath := filepath.Join(tempDir, "schema.json")
+	assert.NoError(os.WriteFile(jsonPath, []byte(`{"$schema":"./gonovate.schema.json","managers":[]}`), os.ModePerm))
+
+	_, err := NewConfigLoader(slog.Default()).WithStrictDecoding().Load(jsonPath)
+	assert.NoError(err)
+	assert.Contains(GenerateSchema().Properties, "$schema")
+}
@@ -6,6 +6,8 @@ import (
 
 // This type represents the gonovate config object.
 type GonovateConfig struct {
+	// An optional reference to the JSON Schema, only used by editors.
+	Schema string `json:"$schema,omitempty" yaml:"$schema,omitempty"`
 	// Settings that are relevant for the platform.
 	Platform *PlatformConfig `json:"platform" yaml:"platform"`
 	// A map of presets for matchstrings that can be used and referenced.
@@ -22,6 +24,8 @@ type GonovateConfig struct {
 	Rules []*Rule `json:"rules" yaml:"rules"`
 	// A list of rules that can apply to hosts.
 	HostRules []*common.HostRule `json:"hostRules" yaml:"hostRules"`
+	// The origins of the values in this config, keyed by the path of the value.
+	origins map[string]string
 }
 
 type MatchStringPreset struct {
@@ -34,17 +38,71 @@ type PlatformConfig struct {
 	// The type of the platform to use.
 	Type  common.PlatformType `json:"type" yaml:"type"`
 	Token string              `json:"token" yaml:"token"`
+	// The id of a GitHub App which authenticates instead of the token. Needs the installation id and the private key file as well.
+	GitHubAppId int64 `json:"githubAppId" yaml:"githubAppId"`
+	// The id of the installation of the GitHub App in the organization or user account.
+	GitHubAppInstallationId int64 `json:"githubAppInstallationId" yaml:"githubAppInstallationId"`
+	// The path to the private key (PEM) of the GitHub App. Is expanded from environment variables.
+	GitHubAppPrivateKeyFile string `json:"githubAppPrivateKeyFile" yaml:"githubAppPrivateKeyFile"`
 	// The author to use when committing changes. Defaults to null which will use the platform credentials to get the author.
 	GitAuthor string   `json:"gitAuthor" yaml:"gitAuthor"`
 	Endpoint  string   `json:"endpoint" yaml:"endpoint"`
 	Inplace   *bool    `json:"inplace" yaml:"inplace"`
 	Projects  []string `json:"projects" yaml:"projects"`
+	// Settings to discover projects on the platform in addition to the configured projects.
+	Autodiscover *AutodiscoverConfig `json:"autodiscover" yaml:"autodiscover"`
 	// The name of the base branch, defaults to "main".
 	BaseBranch string `json:"baseBranch" yaml:"baseBranch"`
 	// The prefix for branches created by gonovate. Defaults to "gonovate/".
 	BranchPrefix string `json:"branchPrefix" yaml:"branchPrefix"`
 	// The prefix for commit messages created by gonovate. Defaults to null.
 	CommitMessagePrefix string `json:"commitMessagePrefix" yaml:"commitMessagePrefix"`
+	// If commit messages and titles use conventional commits (enabled, disabled or auto to detect it from the history). Defaults to disabled.
+	SemanticCommits common.SemanticCommits `json:"semanticCommits" yaml:"semanticCommits"`
+	// Flag to maintain a dependency dashboard issue in the project. Defaults to false.
+	DependencyDashboard *bool `json:"dependencyDashboard" yaml:"dependencyDashboard"`
+	// The title of the dependency dashboard issue. Defaults to "Dependency Dashboard".
+	DependencyDashboardTitle string `json:"dependencyDashboardTitle" yaml:"dependencyDashboardTitle"`
+	// The maximum number of open PRs/MRs created by gonovate. Defaults to 0 which means no limit.
+	PrConcurrentLimit int `json:"prConcurrentLimit" yaml:"prConcurrentLimit"`
+	// The maximum number of PRs/MRs gonovate creates per hour. Defaults to 0 which means no limit.
+	PrHourlyLimit int `json:"prHourlyLimit" yaml:"prHourlyLimit"`
+	// When existing branches should be rebuilt on the base branch (never, conflicted or behind-base). Defaults to behind-base.
+	RebaseWhen common.RebaseWhen `json:"rebaseWhen" yaml:"rebaseWhen"`
+	// An optional label which is added to PRs/MRs of branches that were modified by someone else.
+	ModifiedBranchLabel string `json:"modifiedBranchLabel" yaml:"modifiedBranchLabel"`
+	// Flag to add a comment to PRs/MRs of branches that were modified by someone else. Defaults to false.
+	ModifiedBranchComment *bool `json:"modifiedBranchComment" yaml:"modifiedBranchComment"`
+}
+
+// This type defines how projects are discovered on the platform.
+type AutodiscoverConfig struct {
+	// Flag to enable the discovery of projects. Defaults to false.
+	Enabled *bool `json:"enabled" yaml:"enabled"`
+	// The namespaces to search (GitHub organizations or users, GitLab groups with their subgroups, Gitea organizations). Defaults to all repositories that are visible to the credentials.
+	Namespaces []string `json:"namespaces" yaml:"namespaces"`
+	// The paths of the projects to include. Can be a glob (like "org/service-*") or a regexp when prefixed with "re:". Defaults to all.
+	Includes []string `json:"includes" yaml:"includes"`
+	// The paths of the projects to exclude. Can be a glob or a regexp when prefixed with "re:".
+	Excludes []string `json:"excludes" yaml:"excludes"`
+	// Topics of which a project needs to have at least one.
+	Topics []string `json:"topics" yaml:"topics"`
+	// Topics that exclude a project.
+	ExcludeTopics []string `json:"excludeTopics" yaml:"excludeTopics"`
+	// Flag to also discover archived projects. Defaults to false.
+	IncludeArchived *bool `json:"includeArchived" yaml:"includeArchived"`
+	// Flag to also discover forks. Defaults to false.
+	IncludeForks *bool `json:"includeForks" yaml:"includeForks"`
+}
+
+// Checks if the discovery of projects is enabled.
+func (pc *PlatformConfig) IsAutodiscoverEnabled() bool {
+	return pc.Autodiscover != nil && pc.Autodiscover.Enabled != nil && *pc.Autodiscover.Enabled
+}
+
+// Checks if any of the settings of a GitHub App is set.
+func (pc *PlatformConfig) HasGitHubApp() bool {
+	return pc.GitHubAppId != 0 || pc.GitHubAppInstallationId != 0 || pc.GitHubAppPrivateKeyFile != ""
 }
 
 // This type represents an instance of manager with its configs and configs that apply for all dependencies within this manager.
@@ -108,12 +166,36 @@ type DependencyConfig struct {
 	TitleTemplate string `json:"titleTemplate" yaml:"titleTemplate"`
 	// A template for the branch name when creating an MR/PR.
 	BranchNameTemplate string `json:"branchNameTemplate" yaml:"branchNameTemplate"`
+	// A template for the body (description) of the MR/PR.
+	PrBodyTemplate string `json:"prBodyTemplate" yaml:"prBodyTemplate"`
+	// A flag to only create the branch and MR/PR after the update was approved on the dependency dashboard.
+	RequireApproval *bool `json:"requireApproval" yaml:"requireApproval"`
+	// The minimum age a release must have to be considered (like "3d" or "12h"). Releases without a release date are always considered.
+	MinimumReleaseAge string `json:"minimumReleaseAge" yaml:"minimumReleaseAge"`
+	// Restricts the versions that are allowed, either with a range (like "<18" or ">=15, <16") or a regexp (like "^3\.").
+	AllowedVersions string `json:"allowedVersions" yaml:"allowedVersions"`
+	// A list of versions that should never be used. Entries can be a regexp when prefixed with "re:".
+	IgnoredVersions []string `json:"ignoredVersions" yaml:"ignoredVersions"`
+	// A flag to create separate branches and MRs/PRs per update type (major, minor, patch) when using the default templates.
+	SeparateUpdateTypes *bool `json:"separateUpdateTypes" yaml:"separateUpdateTypes"`
+	// A flag to automatically merge the MR/PR once all checks succeeded.
+	Automerge *bool `json:"automerge" yaml:"automerge"`
+	// The strategy to use when merging automatically (merge, squash or rebase). Defaults to merge.
+	AutomergeStrategy common.AutomergeStrategy `json:"automergeStrategy" yaml:"automergeStrategy"`
+	// The type of conventional commits (like "chore" or "fix") when semantic commits are used.
+	SemanticCommitType string `json:"semanticCommitType" yaml:"semanticCommitType"`
+	// The scope of conventional commits (like "deps") when semantic commits are used.
+	SemanticCommitScope string `json:"semanticCommitScope" yaml:"semanticCommitScope"`
+	// A flag to use the auto-merge feature of the platform. If disabled, gonovate merges the MR/PR itself on a later run. Defaults to true.
+	PlatformAutomerge *bool `json:"platformAutomerge" yaml:"platformAutomerge"`
 }
 
 type Rule struct {
 	Matches          *RuleMatch        `json:"matches" yaml:"matches"`
 	ManagerConfig    *ManagerConfig    `json:"managerConfig" yaml:"managerConfig"`
 	DependencyConfig *DependencyConfig `json:"dependencyConfig" yaml:"dependencyConfig"`
+	// Describes where the rule was generated from if it was not defined in a config directly.
+	GeneratedFrom string `json:"-" yaml:"-"`
 }
 
 type RuleMatch struct {
@@ -122,6 +204,22 @@ type RuleMatch struct {
 	Files           []string                `json:"files" yaml:"files"`
 	DependencyNames []string                `json:"dependencyNames" yaml:"dependencyNames"`
 	Datasources     []common.DatasourceType `json:"datasources" yaml:"datasources"`
+	// Matches the type of the dependency (like "direct", "dev" or "image").
+	DependencyTypes []string `json:"dependencyTypes" yaml:"dependencyTypes"`
+	// Matches the current version of the dependency with a range (like "<2") or a regexp (like "^1\.").
+	CurrentVersions []string `json:"currentVersions" yaml:"currentVersions"`
+	// Matches the type of the update. Rules with this criterion are evaluated per update.
+	UpdateTypes []common.UpdateType `json:"updateTypes" yaml:"updateTypes"`
+
+	// The exclude variants reject the rule if any of the values matches.
+	ExcludeManagers        []string                `json:"excludeManagers" yaml:"excludeManagers"`
+	ExcludeManagerTypes    []common.ManagerType    `json:"excludeManagerTypes" yaml:"excludeManagerTypes"`
+	ExcludeFiles           []string                `json:"excludeFiles" yaml:"excludeFiles"`
+	ExcludeDependencyNames []string                `json:"excludeDependencyNames" yaml:"excludeDependencyNames"`
+	ExcludeDatasources     []common.DatasourceType `json:"excludeDatasources" yaml:"excludeDatasources"`
+	ExcludeDependencyTypes []string                `json:"excludeDependencyTypes" yaml:"excludeDependencyTypes"`
+	ExcludeCurrentVersions []string                `json:"excludeCurrentVersions" yaml:"excludeCurrentVersions"`
+	ExcludeUpdateTypes     []common.UpdateType     `json:"excludeUpdateTypes" yaml:"excludeUpdateTypes"`
 }
 
 // A MatchAll rule is a rule that has no matches defined at all, so it will match everything.
@@ -130,5 +228,21 @@ func (rm *RuleMatch) IsMatchAll() bool {
 		len(rm.DependencyNames) == 0 &&
 		len(rm.Files) == 0 &&
 		len(rm.ManagerTypes) == 0 &&
-		len(rm.Managers) == 0)
+		len(rm.Managers) == 0 &&
+		len(rm.DependencyTypes) == 0 &&
+		len(rm.CurrentVersions) == 0 &&
+		len(rm.UpdateTypes) == 0 &&
+		len(rm.ExcludeManagers) == 0 &&
+		len(rm.ExcludeManagerTypes) == 0 &&
+		len(rm.ExcludeFiles) == 0 &&
+		len(rm.ExcludeDependencyNames) == 0 &&
+		len(rm.ExcludeDatasources) == 0 &&
+		len(rm.ExcludeDependencyTypes) == 0 &&
+		len(rm.ExcludeCurrentVersions) == 0 &&
+		len(rm.ExcludeUpdateTypes) == 0)
+}
+
+// Checks if the rule can only be evaluated for a specific update (eg. because it matches update types).
+func (rm *RuleMatch) IsUpdateSpecific() bool {
+	return rm != nil && (len(rm.UpdateTypes) > 0 || len(rm.ExcludeUpdateTypes) > 0)
 }
@@ -0,0 +1,308 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/roemer/gonovate/pkg/platforms"
+	"github.com/roemer/gonovate/pkg/presets"
+)
+
+// Validates the config and returns an error containing all the problems that were found.
+func (c *GonovateConfig) Validate() error {
+	validator := &configValidator{
+		config: c,
+	}
+	validator.validate()
+	return errors.Join(validator.errors...)
+}
+
+// Helper object that collects the validation errors of a config.
+type configValidator struct {
+	config *GonovateConfig
+	errors []error
+}
+
+func (v *configValidator) addError(path string, format string, args ...any) {
+	v.errors = append(v.errors, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+func (v *configValidator) validate() {
+	// Platform
+	if v.config.Platform != nil && v.config.Platform.Type != "" {
+		if !slices.Contains(common.AllPlatformTypes, v.config.Platform.Type) {
+			v.addError("platform.type", "invalid platform type '%s'", v.config.Platform.Type)
+		}
+	}
+	if v.config.Platform != nil && v.config.Platform.RebaseWhen != "" {
+		if !slices.Contains(common.AllRebaseWhen, v.config.Platform.RebaseWhen) {
+			v.addError("platform.rebaseWhen", "invalid value '%s'", v.config.Platform.RebaseWhen)
+		}
+	}
+	if v.config.Platform != nil && v.config.Platform.SemanticCommits != "" {
+		if !slices.Contains(common.AllSemanticCommits, v.config.Platform.SemanticCommits) {
+			v.addError("platform.semanticCommits", "invalid value '%s'", v.config.Platform.SemanticCommits)
+		}
+	}
+	if v.config.Platform != nil && v.config.Platform.HasGitHubApp() {
+		if v.config.Platform.Type != common.PLATFORM_TYPE_GITHUB {
+			v.addError("platform.githubAppId", "github apps are only supported by the github platform")
+		}
+		if v.config.Platform.GitHubAppId <= 0 {
+			v.addError("platform.githubAppId", "missing or invalid app id")
+		}
+		if v.config.Platform.GitHubAppInstallationId <= 0 {
+			v.addError("platform.githubAppInstallationId", "missing or invalid installation id")
+		}
+		if v.config.Platform.GitHubAppPrivateKeyFile == "" {
+			v.addError("platform.githubAppPrivateKeyFile", "missing private key file")
+		}
+	}
+	if v.config.Platform != nil && v.config.Platform.Autodiscover != nil {
+		v.validateAutodiscover("platform.autodiscover", v.config.Platform)
+	}
+	// Versioning presets
+	for name, versioning := range v.config.VersioningPresets {
+		v.validateRegex(fmt.Sprintf("versioningPresets.%s", name), versioning)
+	}
+	// Ignore patterns
+	for i, pattern := range v.config.IgnorePatterns {
+		v.validateFilePattern(fmt.Sprintf("ignorePatterns[%d]", i), pattern)
+	}
+	// Managers
+	for i, manager := range v.config.Managers {
+		path := fmt.Sprintf("managers[%d]", i)
+		if manager.Id == "" {
+			v.addError(path, "missing id")
+		}
+		v.validateManagerType(path+".type", manager.Type)
+		v.validateManagerConfig(path+".managerConfig", manager.ManagerConfig)
+		v.validateDependencyConfig(path+".dependencyConfig", manager.DependencyConfig)
+	}
+	// Rules
+	for i, rule := range v.config.Rules {
+		path := fmt.Sprintf("rules[%d]", i)
+		v.validateRuleMatch(path+".matches", rule.Matches)
+		v.validateUpdateSpecificRule(path, rule)
+		v.validateManagerConfig(path+".managerConfig", rule.ManagerConfig)
+		v.validateDependencyConfig(path+".dependencyConfig", rule.DependencyConfig)
+	}
+}
+
+func (v *configValidator) validateRuleMatch(path string, ruleMatch *RuleMatch) {
+	if ruleMatch == nil {
+		return
+	}
+	for i, managerId := range ruleMatch.Managers {
+		v.validateMatchString(fmt.Sprintf("%s.managers[%d]", path, i), managerId)
+	}
+	for i, managerType := range ruleMatch.ManagerTypes {
+		v.validateManagerType(fmt.Sprintf("%s.managerTypes[%d]", path, i), managerType)
+	}
+	for i, file := range ruleMatch.Files {
+		v.validateFilePattern(fmt.Sprintf("%s.files[%d]", path, i), file)
+	}
+	for i, dependencyName := range ruleMatch.DependencyNames {
+		v.validateMatchString(fmt.Sprintf("%s.dependencyNames[%d]", path, i), dependencyName)
+	}
+	for i, datasource := range ruleMatch.Datasources {
+		v.validateDatasourceType(fmt.Sprintf("%s.datasources[%d]", path, i), datasource)
+	}
+	for i, dependencyType := range ruleMatch.DependencyTypes {
+		v.validateMatchString(fmt.Sprintf("%s.dependencyTypes[%d]", path, i), dependencyType)
+	}
+	for i, constraint := range ruleMatch.CurrentVersions {
+		v.validateVersionConstraint(fmt.Sprintf("%s.currentVersions[%d]", path, i), constraint)
+	}
+	for i, updateType := range ruleMatch.UpdateTypes {
+		v.validateUpdateType(fmt.Sprintf("%s.updateTypes[%d]", path, i), updateType)
+	}
+	// Exclusions
+	for i, managerId := range ruleMatch.ExcludeManagers {
+		v.validateMatchString(fmt.Sprintf("%s.excludeManagers[%d]", path, i), managerId)
+	}
+	for i, managerType := range ruleMatch.ExcludeManagerTypes {
+		v.validateManagerType(fmt.Sprintf("%s.excludeManagerTypes[%d]", path, i), managerType)
+	}
+	for i, file := range ruleMatch.ExcludeFiles {
+		v.validateFilePattern(fmt.Sprintf("%s.excludeFiles[%d]", path, i), file)
+	}
+	for i, dependencyName := range ruleMatch.ExcludeDependencyNames {
+		v.validateMatchString(fmt.Sprintf("%s.excludeDependencyNames[%d]", path, i), dependencyName)
+	}
+	for i, datasource := range ruleMatch.ExcludeDatasources {
+		v.validateDatasourceType(fmt.Sprintf("%s.excludeDatasources[%d]", path, i), datasource)
+	}
+	for i, dependencyType := range ruleMatch.ExcludeDependencyTypes {
+		v.validateMatchString(fmt.Sprintf("%s.excludeDependencyTypes[%d]", path, i), dependencyType)
+	}
+	for i, constraint := range ruleMatch.ExcludeCurrentVersions {
+		v.validateVersionConstraint(fmt.Sprintf("%s.excludeCurrentVersions[%d]", path, i), constraint)
+	}
+	for i, updateType := range ruleMatch.ExcludeUpdateTypes {
+		v.validateUpdateType(fmt.Sprintf("%s.excludeUpdateTypes[%d]", path, i), updateType)
+	}
+}
+
+// Rules which match update types are evaluated per update, so they can only change the settings of the branch and MR/PR.
+func (v *configValidator) validateUpdateSpecificRule(path string, rule *Rule) {
+	if !rule.Matches.IsUpdateSpecific() {
+		return
+	}
+	if !isEmptyConfigValue(reflect.ValueOf(rule.ManagerConfig)) {
+		v.addError(path+".managerConfig", "cannot be used in rules that match update types")
+	}
+	if rule.DependencyConfig == nil {
+		return
+	}
+	forEachConfigField(reflect.ValueOf(rule.DependencyConfig).Elem(), func(name string, field reflect.Value) {
+		if !isEmptyConfigValue(field) && !slices.Contains(updateSpecificSettings, name) {
+			v.addError(path+".dependencyConfig."+name, "cannot be used in rules that match update types, allowed are: %s", strings.Join(updateSpecificSettings, ", "))
+		}
+	})
+}
+
+func (v *configValidator) validateManagerConfig(path string, managerConfig *ManagerConfig) {
+	if managerConfig == nil {
+		return
+	}
+	for i, pattern := range managerConfig.FilePatterns {
+		v.validateFilePattern(fmt.Sprintf("%s.filePatterns[%d]", path, i), pattern)
+	}
+	for i, matchString := range managerConfig.MatchStrings {
+		currentPath := fmt.Sprintf("%s.matchStrings[%d]", path, i)
+		resolvedMatchString, err := presets.ResolveMatchString(matchString, v.config.MatchStringPresetsToPresets())
+		if err != nil {
+			v.addError(currentPath, "%s", err.Error())
+			continue
+		}
+		v.validateRegex(currentPath, resolvedMatchString)
+	}
+	for featureName, featureDependencies := range managerConfig.DevcontainerConfig {
+		for i, featureDependency := range featureDependencies {
+			if featureDependency.Datasource != "" {
+				v.validateDatasourceType(fmt.Sprintf("%s.devcontainerConfig.%s[%d].datasource", path, featureName, i), featureDependency.Datasource)
+			}
+		}
+	}
+}
+
+func (v *configValidator) validateDependencyConfig(path string, dependencyConfig *DependencyConfig) {
+	if dependencyConfig == nil {
+		return
+	}
+	if dependencyConfig.MaxUpdateType != "" {
+		v.validateUpdateType(path+".maxUpdateType", dependencyConfig.MaxUpdateType)
+	}
+	for i, updateType := range dependencyConfig.UpdateTypes {
+		v.validateUpdateType(fmt.Sprintf("%s.updateTypes[%d]", path, i), updateType)
+	}
+	if dependencyConfig.Versioning != "" {
+		resolvedVersioning, err := presets.ResolveVersioning(dependencyConfig.Versioning, v.config.VersioningPresets)
+		if err != nil {
+			v.addError(path+".versioning", "%s", err.Error())
+		} else {
+			v.validateRegex(path+".versioning", resolvedVersioning)
+		}
+	}
+	if dependencyConfig.ExtractVersion != "" {
+		v.validateRegex(path+".extractVersion", dependencyConfig.ExtractVersion)
+	}
+	if dependencyConfig.Datasource != "" {
+		v.validateDatasourceType(path+".datasource", dependencyConfig.Datasource)
+	}
+	for i, replacement := range dependencyConfig.PostUpgradeReplacements {
+		v.validateRegex(fmt.Sprintf("%s.postUpgradeReplacements[%d]", path, i), replacement)
+	}
+	if dependencyConfig.MinimumReleaseAge != "" {
+		if _, err := common.ParseDuration(dependencyConfig.MinimumReleaseAge); err != nil {
+			v.addError(path+".minimumReleaseAge", "%s", err.Error())
+		}
+	}
+	if dependencyConfig.AllowedVersions != "" {
+		v.validateVersionConstraint(path+".allowedVersions", dependencyConfig.AllowedVersions)
+	}
+	for i, ignoredVersion := range dependencyConfig.IgnoredVersions {
+		v.validateMatchString(fmt.Sprintf("%s.ignoredVersions[%d]", path, i), ignoredVersion)
+	}
+	if dependencyConfig.AutomergeStrategy != "" {
+		v.validateAutomergeStrategy(path+".automergeStrategy", dependencyConfig.AutomergeStrategy)
+	}
+}
+
+func (v *configValidator) validateManagerType(path string, managerType common.ManagerType) {
+	if !slices.Contains(common.AllManagerTypes, managerType) {
+		v.addError(path, "invalid manager type '%s'", managerType)
+	}
+}
+
+func (v *configValidator) validateDatasourceType(path string, datasourceType common.DatasourceType) {
+	if !slices.Contains(common.AllDatasourceTypes, datasourceType) {
+		v.addError(path, "invalid datasource '%s'", datasourceType)
+	}
+}
+
+func (v *configValidator) validateUpdateType(path string, updateType common.UpdateType) {
+	if !slices.Contains(common.AllUpdateTypes, updateType) {
+		v.addError(path, "invalid update type '%s'", updateType)
+	}
+}
+
+func (v *configValidator) validateAutomergeStrategy(path string, strategy common.AutomergeStrategy) {
+	if !slices.Contains(common.AllAutomergeStrategies, strategy) {
+		v.addError(path, "invalid automerge strategy '%s'", strategy)
+	}
+}
+
+func (v *configValidator) validateAutodiscover(path string, platformConfig *PlatformConfig) {
+	if platformConfig.IsAutodiscoverEnabled() && slices.Contains(common.AllPlatformTypes, platformConfig.Type) &&
+		!slices.Contains(platforms.AutodiscoverPlatformTypes, platformConfig.Type) {
+		v.addError(path+".enabled", "not supported by the platform '%s'", platformConfig.Type)
+	}
+	for i, include := range platformConfig.Autodiscover.Includes {
+		v.validateProjectPattern(fmt.Sprintf("%s.includes[%d]", path, i), include)
+	}
+	for i, exclude := range platformConfig.Autodiscover.Excludes {
+		v.validateProjectPattern(fmt.Sprintf("%s.excludes[%d]", path, i), exclude)
+	}
+}
+
+// Validates patterns of projects which can either be a glob or a regexp when prefixed with "re:".
+func (v *configValidator) validateProjectPattern(path string, pattern string) {
+	if strings.HasPrefix(pattern, "re:") {
+		v.validateRegex(path, pattern[3:])
+	} else if !doublestar.ValidatePattern(pattern) {
+		v.addError(path, "invalid pattern '%s'", pattern)
+	}
+}
+
+// Validates strings which can either be plain or a regexp when prefixed with "re:".
+func (v *configValidator) validateMatchString(path string, matchString string) {
+	if strings.HasPrefix(matchString, "re:") {
+		v.validateRegex(path, matchString[3:])
+	}
+}
+
+func (v *configValidator) validateVersionConstraint(path string, constraint string) {
+	if _, err := common.ParseVersionConstraint(constraint, nil); err != nil {
+		v.addError(path, "%s", err.Error())
+	}
+}
+
+func (v *configValidator) validateRegex(path string, regex string) {
+	if _, err := regexp.Compile(regex); err != nil {
+		v.addError(path, "invalid regexp '%s': %s", regex, err.Error())
+	}
+}
+
+func (v *configValidator) validateFilePattern(path string, pattern string) {
+	if !doublestar.ValidatePattern(pattern) {
+		v.addError(path, "invalid file pattern '%s'", pattern)
+	}
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDefaults(t *testing.T) {
+	require := require.New(t)
+
+	cfg, err := NewConfigLoader(slog.Default()).WithStrictDecoding().Load("preset:defaults")
+	require.NoError(err)
+	require.NoError(cfg.Validate())
+}
+
+func TestValidateJavaPreset(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// The java preset used the unknown datasource "gradle" so the versioning was never applied to gradle versions
+	cfg, err := NewConfigLoader(slog.Default()).WithStrictDecoding().Load("preset:defaults")
+	require.NoError(err)
+
+	dependency := &common.Dependency{
+		Name:        "gradle",
+		Datasource:  common.DATASOURCE_TYPE_GRADLEVERSION,
+		Version:     "8.10.2",
+		ManagerInfo: &common.ManagerInfo{ManagerId: "manager"},
+	}
+	require.NoError(cfg.ApplyToDependency(dependency))
+	assert.Equal(`^(\d+)\.(\d+)(?:\.(\d+))?$`, dependency.Versioning)
+}
+
+func TestValidateInvalidConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &GonovateConfig{
+		Platform: &PlatformConfig{
+			Type:            "unknown-platform",
+			RebaseWhen:      "always",
+			SemanticCommits: "sometimes",
+			GitHubAppId:     1,
+			Autodiscover: &AutodiscoverConfig{
+				Includes: []string{"re:[a-z"},
+				Excludes: []string{"org/[a-"},
+			},
+		},
+		Managers: []*Manager{
+			{Id: "manager", Type: "unknown-manager"},
+		},
+		VersioningPresets: map[string]string{
+			"broken": "^(\\d+",
+		},
+		Rules: []*Rule{
+			{
+				Matches: &RuleMatch{
+					DependencyNames:    []string{"re:[a-z"},
+					Datasources:        []common.DatasourceType{"unknown-datasource"},
+					CurrentVersions:    []string{"<"},
+					ExcludeUpdateTypes: []common.UpdateType{"tiny"},
+				},
+				ManagerConfig: &ManagerConfig{
+					MatchStrings: []string{"(?P<version>.*", "preset:missing"},
+				},
+				DependencyConfig: &DependencyConfig{
+					Versioning:        "preset:missing",
+					ExtractVersion:    "v(.*",
+					UpdateTypes:       []common.UpdateType{"huge"},
+					MinimumReleaseAge: "3 days",
+					AllowedVersions:   "^(3",
+					IgnoredVersions:   []string{"2.4.1", "re:[0-9"},
+					AutomergeStrategy: "fast-forward",
+				},
+			},
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(err)
+	message := err.Error()
+	assert.Contains(message, "platform.type")
+	assert.Contains(message, "platform.rebaseWhen: invalid value 'always'")
+	assert.Contains(message, "platform.semanticCommits: invalid value 'sometimes'")
+	assert.Contains(message, "platform.githubAppId: github apps are only supported by the github platform")
+	assert.Contains(message, "platform.githubAppInstallationId: missing or invalid installation id")
+	assert.Contains(message, "platform.githubAppPrivateKeyFile: missing private key file")
+	assert.Contains(message, "platform.autodiscover.includes[0]: invalid regexp")
+	assert.Contains(message, "platform.autodiscover.excludes[0]: invalid pattern 'org/[a-'")
+	assert.Contains(message, "managers[0].type")
+	assert.Contains(message, "versioningPresets.broken")
+	assert.Contains(message, "rules[0].matches.dependencyNames[0]")
+	assert.Contains(message, "rules[0].matches.datasources[0]")
+	assert.Contains(message, "rules[0].managerConfig.matchStrings[0]")
+	assert.Contains(message, "matchString preset 'missing' not found")
+	assert.Contains(message, "versioning preset 'missing' not found")
+	assert.Contains(message, "rules[0].dependencyConfig.extractVersion")
+	assert.Contains(message, "rules[0].dependencyConfig.updateTypes[0]")
+	assert.Contains(message, "rules[0].dependencyConfig.minimumReleaseAge")
+	assert.Contains(message, "rules[0].dependencyConfig.allowedVersions")
+	assert.Contains(message, "rules[0].dependencyConfig.ignoredVersions[1]")
+	assert.NotContains(message, "rules[0].dependencyConfig.ignoredVersions[0]")
+	assert.Contains(message, "rules[0].dependencyConfig.automergeStrategy: invalid automerge strategy 'fast-forward'")
+	assert.Contains(message, "rules[0].matches.currentVersions[0]")
+	assert.Contains(message, "rules[0].matches.excludeUpdateTypes[0]")
+	assert.Contains(message, "rules[0].managerConfig: cannot be used in rules that match update types")
+	assert.Contains(message, "rules[0].dependencyConfig.versioning: cannot be used in rules that match update types")
+}
+
+func TestValidateAutodiscoverPlatform(t *testing.T) {
+	assert := assert.New(t)
+
+	enabled := true
+	cfg := &GonovateConfig{Platform: &PlatformConfig{Type: common.PLATFORM_TYPE_GIT, Autodiscover: &AutodiscoverConfig{Enabled: &enabled}}}
+	err := cfg.Validate()
+	assert.ErrorContains(err, "platform.autodiscover.enabled: not supported by the platform 'git'")
+
+	cfg.Platform.Type = common.PLATFORM_TYPE_GITLAB
+	assert.NoError(cfg.Validate())
+}
+
+func TestStrictDecodingRejectsUnknownKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir := t.TempDir()
+	jsonPath := filepath.Join(tempDir, "strict.json")
+	yamlPath := filepath.Join(tempDir, "strict.yaml")
+	assert.NoError(os.WriteFile(jsonPath, []byte(`{"rules":[{"matchs":{}}]}`), os.ModePerm))
+	assert.NoError(os.WriteFile(yamlPath, []byte("rules:\n  - matchs: {}\n"), os.ModePerm))
+
+	// Non-strict loading ignores the unknown key
+	_, err := NewConfigLoader(slog.Default()).Load(jsonPath)
+	assert.NoError(err)
+	_, err = NewConfigLoader(slog.Default()).Load(yamlPath)
+	assert.NoError(err)
+
+	// Strict loading fails
+	_, err = NewConfigLoader(slog.Default()).WithStrictDecoding().Load(jsonPath)
+	assert.ErrorContains(err, "matchs")
+	_, err = NewConfigLoader(slog.Default()).WithStrictDecoding().Load(yamlPath)
+	assert.ErrorContains(err, "matchs")
+}
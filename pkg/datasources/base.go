@@ -225,6 +225,20 @@ func (ds *datasourceBase) searchUpdatedVersion(dependency *common.Dependency) ([
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed parsing the 'versioning' regexp '%s': %w", dependency.Versioning, err)
 	}
+	minimumReleaseAge := time.Duration(0)
+	if dependency.MinimumReleaseAge != "" {
+		minimumReleaseAge, err = common.ParseDuration(dependency.MinimumReleaseAge)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed parsing the 'minimumReleaseAge': %w", err)
+		}
+	}
+	var allowedVersions *common.VersionConstraint
+	if dependency.AllowedVersions != "" {
+		allowedVersions, err = common.ParseVersionConstraint(dependency.AllowedVersions, versionRegex)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed parsing the 'allowedVersions': %w", err)
+		}
+	}
 	var extractVersionRegex *regexp.Regexp
 	if dependency != nil && len(dependency.ExtractVersion) > 0 {
 		extractVersionRegex, err = regexp.Compile(dependency.ExtractVersion)
@@ -287,9 +301,25 @@ func (ds *datasourceBase) searchUpdatedVersion(dependency *common.Dependency) ([
 			return nil, nil, fmt.Errorf("failed parsing the version from '%s': %w", release.VersionString, err)
 		}
 		release.Version = version
+		// Check the version restrictions
+		if allowedVersions != nil && !allowedVersions.Allows(release) {
+			ds.logger.Debug(fmt.Sprintf("Ignoring version '%s' which is not allowed by '%s'", release.VersionString, allowedVersions))
+			continue
+		}
+		if isIgnored, err := common.IsIgnoredVersion(release, dependency.IgnoredVersions); err != nil {
+			return nil, nil, err
+		} else if isIgnored {
+			ds.logger.Debug(fmt.Sprintf("Ignoring version '%s' which is in the ignored versions", release.VersionString))
+			continue
+		}
 		availableReleases = append(availableReleases, release)
 	}
 
+	// Remove the releases that are too young
+	if minimumReleaseAge > 0 {
+		availableReleases = ds.filterYoungReleases(availableReleases, minimumReleaseAge, time.Now())
+	}
+
 	if len(availableReleases) == 0 {
 		ds.logger.Warn("No releases found to check for versions")
 		return nil, nil, nil
@@ -347,6 +377,17 @@ func (ds *datasourceBase) searchUpdatedVersion(dependency *common.Dependency) ([
 	return updates, currentVersion, nil
 }
 
+// Removes the releases that were released less than the minimum age ago. Releases without a release date are kept.
+func (ds *datasourceBase) filterYoungReleases(releases []*common.ReleaseInfo, minimumReleaseAge time.Duration, now time.Time) []*common.ReleaseInfo {
+	return slices.DeleteFunc(releases, func(release *common.ReleaseInfo) bool {
+		if release.ReleaseDate.IsZero() || now.Sub(release.ReleaseDate) >= minimumReleaseAge {
+			return false
+		}
+		ds.logger.Debug(fmt.Sprintf("Ignoring release '%s' which is younger than %s", release.VersionString, minimumReleaseAge))
+		return true
+	})
+}
+
 func (ds *datasourceBase) getRegistryUrl(baseUrl string, customRegistryUrls []string) string {
 	if len(customRegistryUrls) > 0 {
 		baseUrl = customRegistryUrls[0]
@@ -0,0 +1,30 @@
+package datasources
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterYoungReleases(t *testing.T) {
+	assert := assert.New(t)
+
+	ds := newDatasourceBase(common.DATASOURCE_TYPE_NPM, &common.DatasourceSettings{Logger: slog.New(slog.DiscardHandler)})
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	releases := []*common.ReleaseInfo{
+		{VersionString: "1.0.0", ReleaseDate: now.AddDate(0, 0, -10)},
+		{VersionString: "1.1.0", ReleaseDate: now.AddDate(0, 0, -3)},
+		{VersionString: "1.2.0", ReleaseDate: now.Add(-2 * time.Hour)},
+		{VersionString: "1.3.0"},
+	}
+
+	filtered := ds.filterYoungReleases(releases, 72*time.Hour, now)
+	versions := []string{}
+	for _, release := range filtered {
+		versions = append(versions, release.VersionString)
+	}
+	assert.Equal([]string{"1.0.0", "1.1.0", "1.3.0"}, versions)
+}
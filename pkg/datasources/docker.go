@@ -15,11 +15,14 @@ import (
 
 type DockerDatasource struct {
 	*datasourceBase
+	// The source urls by image and version, so the manifests are only downloaded once.
+	sourceUrls map[string]string
 }
 
 func NewDockerDatasource(settings *common.DatasourceSettings) common.IDatasource {
 	newDatasource := &DockerDatasource{
 		datasourceBase: newDatasourceBase(common.DATASOURCE_TYPE_DOCKER, settings),
+		sourceUrls:     map[string]string{},
 	}
 	newDatasource.impl = newDatasource
 	return newDatasource
@@ -29,25 +32,7 @@ var dockerIoRegex = regexp.MustCompile(`^(https?://)?([a-zA-Z-_0-9\.]*docker\.io
 var httpSchemeRegex = regexp.MustCompile(`^https?://(.*)`)
 
 func (ds *DockerDatasource) GetReleases(dependency *common.Dependency) ([]*common.ReleaseInfo, error) {
-	customRegistryUrl := ds.getRegistryUrl("", dependency.RegistryUrls)
-	registryUrl, imagePath, err := getDockerRegistry(dependency.Name, customRegistryUrl)
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse the registry url
-	baseUrl, err := url.Parse(registryUrl)
-	if err != nil {
-		return nil, err
-	}
-	// Add the v2 endpoint
-	baseUrl = baseUrl.JoinPath("v2")
-
-	// Get a host rule if any was defined
-	relevantHostRule := ds.getHostRuleForHost(baseUrl.Host)
-
-	// Get an authentication token
-	authToken, err := ds.getAuthToken(baseUrl, imagePath, relevantHostRule)
+	baseUrl, imagePath, authToken, err := ds.getRegistryAccess(dependency)
 	if err != nil {
 		return nil, err
 	}
@@ -75,16 +60,110 @@ func (ds *DockerDatasource) GetReleases(dependency *common.Dependency) ([]*commo
 }
 
 func (ds *DockerDatasource) GetDigest(dependency *common.Dependency, releaseVersion string) (string, error) {
+	baseUrl, imagePath, authToken, err := ds.getRegistryAccess(dependency)
+	if err != nil {
+		return "", err
+	}
+
+	// Get the digest
+	digest, err := ds.getDigestWithToken(baseUrl, imagePath, releaseVersion, authToken)
+	if err != nil {
+		return "", err
+	}
+
+	return digest, nil
+}
+
+// The label (or annotation) of OCI images which contains the url to the source repository.
+const ociSourceLabel = "org.opencontainers.image.source"
+
+// Gets the source repository from the OCI source annotation or label of the image.
+func (ds *DockerDatasource) GetSourceUrl(dependency *common.Dependency, release *common.ReleaseInfo) (string, error) {
+	cacheKey := fmt.Sprintf("%s|%s:%s", strings.Join(dependency.RegistryUrls, ","), dependency.Name, release.VersionString)
+	if sourceUrl, ok := ds.sourceUrls[cacheKey]; ok {
+		return sourceUrl, nil
+	}
+	sourceUrl, err := ds.getSourceUrl(dependency, release)
+	if err != nil {
+		return "", err
+	}
+	ds.sourceUrls[cacheKey] = sourceUrl
+	return sourceUrl, nil
+}
+
+// Gets the source url from the manifests or the config of the image.
+func (ds *DockerDatasource) getSourceUrl(dependency *common.Dependency, release *common.ReleaseInfo) (string, error) {
+	baseUrl, imagePath, authToken, err := ds.getRegistryAccess(dependency)
+	if err != nil {
+		return "", err
+	}
+
+	manifest, err := ds.getManifestWithToken(baseUrl, imagePath, release.VersionString, authToken)
+	if err != nil {
+		return "", err
+	}
+	if source := manifest.Annotations[ociSourceLabel]; source != "" {
+		return source, nil
+	}
+	// For a list of manifests, use the manifest of the first real platform (attestations have an unknown platform)
+	if len(manifest.Manifests) > 0 {
+		digest := manifest.Manifests[0].Digest
+		for _, entry := range manifest.Manifests {
+			if entry.Platform.Os != "unknown" {
+				digest = entry.Digest
+				break
+			}
+		}
+		manifest, err = ds.getManifestWithToken(baseUrl, imagePath, digest, authToken)
+		if err != nil {
+			return "", err
+		}
+		if source := manifest.Annotations[ociSourceLabel]; source != "" {
+			return source, nil
+		}
+	}
+	if manifest.Config.Digest == "" {
+		return "", nil
+	}
+
+	// Get the labels from the config of the image
+	configUrl := baseUrl.JoinPath(imagePath, "blobs", manifest.Config.Digest)
+	req, err := http.NewRequest(http.MethodGet, configUrl.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	common.HttpUtil.AddBearerToRequest(req, authToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("failed getting Docker image config: statuscode %d", resp.StatusCode)
+	}
+	var imageConfig struct {
+		Config struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"config"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&imageConfig); err != nil {
+		return "", fmt.Errorf("failed parsing Docker image config from response: %w", err)
+	}
+	return imageConfig.Config.Labels[ociSourceLabel], nil
+}
+
+// Gets the v2 url of the registry, the path of the image and the token to access the registry.
+func (ds *DockerDatasource) getRegistryAccess(dependency *common.Dependency) (*url.URL, string, string, error) {
 	customRegistryUrl := ds.getRegistryUrl("", dependency.RegistryUrls)
 	registryUrl, imagePath, err := getDockerRegistry(dependency.Name, customRegistryUrl)
 	if err != nil {
-		return "", err
+		return nil, "", "", err
 	}
 
 	// Parse the registry url
 	baseUrl, err := url.Parse(registryUrl)
 	if err != nil {
-		return "", err
+		return nil, "", "", err
 	}
 	// Add the v2 endpoint
 	baseUrl = baseUrl.JoinPath("v2")
@@ -95,16 +174,9 @@ func (ds *DockerDatasource) GetDigest(dependency *common.Dependency, releaseVers
 	// Get an authentication token
 	authToken, err := ds.getAuthToken(baseUrl, imagePath, relevantHostRule)
 	if err != nil {
-		return "", err
-	}
-
-	// Get the digest
-	digest, err := ds.getDigestWithToken(baseUrl, imagePath, releaseVersion, authToken)
-	if err != nil {
-		return "", err
+		return nil, "", "", err
 	}
-
-	return digest, nil
+	return baseUrl, imagePath, authToken, nil
 }
 
 // Processes the package name and registry url and returns the concrete host and image path
@@ -374,6 +446,29 @@ func (ds *DockerDatasource) getDigestWithToken(baseUrl *url.URL, imageName strin
 	return "", fmt.Errorf("failed to find Docker manifest for %s", imageName)
 }
 
+// Gets and parses the manifest of the image for the given tag or digest.
+func (ds *DockerDatasource) getManifestWithToken(baseUrl *url.URL, imageName string, reference string, bearerToken string) (*dockerManifest, error) {
+	manifestUrl := baseUrl.JoinPath(imageName, "manifests", reference)
+	ds.logger.Debug(fmt.Sprintf("Fetching Docker manifest from url: %s", manifestUrl))
+	req, err := ds.getManifestRequest(manifestUrl, bearerToken, http.MethodGet)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed getting Docker manifest (GET): statuscode %d", resp.StatusCode)
+	}
+	var manifest dockerManifest
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed parsing Docker manifest from response: %w", err)
+	}
+	return &manifest, nil
+}
+
 func (ds *DockerDatasource) getManifestRequest(manifestUrl *url.URL, bearerToken string, method string) (*http.Request, error) {
 	req, err := http.NewRequest(method, manifestUrl.String(), nil)
 	if err != nil {
@@ -394,9 +489,13 @@ func (ds *DockerDatasource) getManifestRequest(manifestUrl *url.URL, bearerToken
 }
 
 type dockerManifest struct {
-	SchemaVersion int    `json:"schemaVersion"`
-	MediaType     string `json:"mediaType"`
-	Manifests     []struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	Annotations   map[string]string `json:"annotations"`
+	Config        struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+	Manifests []struct {
 		MediaType string `json:"mediaType"`
 		Digest    string `json:"digest"`
 		Size      int    `json:"size"`
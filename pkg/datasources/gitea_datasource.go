@@ -64,6 +64,14 @@ func (ds *GiteaReleasesDatasource) GetReleases(dependency *common.Dependency) ([
 	return releases, nil
 }
 
+func (ds *GiteaReleasesDatasource) GetSourceUrl(dependency *common.Dependency, release *common.ReleaseInfo) (string, error) {
+	endpoint, err := normalizeGiteaEndpoint(ds.getRegistryUrl("https://gitea.com", dependency.RegistryUrls))
+	if err != nil {
+		return "", err
+	}
+	return endpoint + "/" + dependency.Name, nil
+}
+
 func (ds *GiteaReleasesDatasource) createClient(registryUrls []string) (*gitea.Client, error) {
 	endpoint, err := normalizeGiteaEndpoint(ds.getRegistryUrl("https://gitea.com", registryUrls))
 	if err != nil {
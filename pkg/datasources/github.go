@@ -1,20 +1,39 @@
 package datasources
 
-import "github.com/google/go-github/v84/github"
+import (
+	"github.com/google/go-github/v84/github"
+	"github.com/roemer/gonovate/pkg/common"
+)
 
-func getGitHubClient(ds *datasourceBase) *github.Client {
-	// Create a blank client
-	client := github.NewClient(nil)
+// Creates a client for the GitHub API. The first registry url can point to a GitHub Enterprise Server.
+func getGitHubClient(ds *datasourceBase, registryUrls []string) (*github.Client, error) {
+	endpoint := getGitHubEndpoint(ds, registryUrls)
+	apiHost := common.GetGitHubApiHost(endpoint)
 	// Get a host rule if any was defined
-	relevantHostRule := ds.getHostRuleForHost("api.github.com")
-	// Add the token to the client
-	if relevantHostRule != nil {
-		token := relevantHostRule.TokenExpanded()
-		// But only if the token is set
-		if len(token) > 0 {
-			client = client.WithAuthToken(token)
+	token := ""
+	if relevantHostRule := ds.getHostRuleForHost(apiHost); relevantHostRule != nil {
+		token = relevantHostRule.TokenExpanded()
+	}
+	// Otherwise use the GitHub App if it belongs to the same server
+	if token == "" && ds.settings != nil && ds.settings.GitHubApp != nil && ds.settings.GitHubApp.ApiHost() == apiHost {
+		appToken, err := ds.settings.GitHubApp.Token()
+		if err != nil {
+			return nil, err
 		}
+		token = appToken
+	}
+	return common.NewGitHubClient(endpoint, token)
+}
+
+// Gets the url of the GitHub repository of a dependency in the format "owner/repository".
+func getGitHubSourceUrl(ds *datasourceBase, dependency *common.Dependency) string {
+	return common.GetGitHubServerUrl(getGitHubEndpoint(ds, dependency.RegistryUrls)) + "/" + dependency.Name
+}
+
+// Gets the endpoint from the registry urls or an empty string for github.com.
+func getGitHubEndpoint(ds *datasourceBase, registryUrls []string) string {
+	if len(registryUrls) == 0 {
+		return ""
 	}
-	// Return the client
-	return client
+	return ds.getRegistryUrl("", registryUrls)
 }
@@ -21,7 +21,10 @@ func NewGitHubReleasesDatasource(settings *common.DatasourceSettings) common.IDa
 }
 
 func (ds *GitHubReleasesDatasource) GetReleases(dependency *common.Dependency) ([]*common.ReleaseInfo, error) {
-	client := getGitHubClient(ds.datasourceBase)
+	client, err := getGitHubClient(ds.datasourceBase, dependency.RegistryUrls)
+	if err != nil {
+		return nil, err
+	}
 
 	parts := strings.SplitN(dependency.Name, "/", 2)
 	owner := parts[0]
@@ -52,3 +55,7 @@ func (ds *GitHubReleasesDatasource) GetReleases(dependency *common.Dependency) (
 	}
 	return releases, nil
 }
+
+func (ds *GitHubReleasesDatasource) GetSourceUrl(dependency *common.Dependency, release *common.ReleaseInfo) (string, error) {
+	return getGitHubSourceUrl(ds.datasourceBase, dependency), nil
+}
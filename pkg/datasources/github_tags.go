@@ -21,7 +21,10 @@ func NewGitHubTagsDatasource(settings *common.DatasourceSettings) common.IDataso
 }
 
 func (ds *GitHubTagsDatasource) GetReleases(dependency *common.Dependency) ([]*common.ReleaseInfo, error) {
-	client := getGitHubClient(ds.datasourceBase)
+	client, err := getGitHubClient(ds.datasourceBase, dependency.RegistryUrls)
+	if err != nil {
+		return nil, err
+	}
 
 	parts := strings.SplitN(dependency.Name, "/", 2)
 	owner := parts[0]
@@ -55,3 +58,7 @@ func (ds *GitHubTagsDatasource) GetReleases(dependency *common.Dependency) ([]*c
 	}
 	return releases, nil
 }
+
+func (ds *GitHubTagsDatasource) GetSourceUrl(dependency *common.Dependency, release *common.ReleaseInfo) (string, error) {
+	return getGitHubSourceUrl(ds.datasourceBase, dependency), nil
+}
@@ -48,6 +48,12 @@ func (ds *GitLabPackagesDatasource) GetReleases(dependency *common.Dependency) (
 	return releases, nil
 }
 
+func (ds *GitLabPackagesDatasource) GetSourceUrl(dependency *common.Dependency, release *common.ReleaseInfo) (string, error) {
+	projectPath, _, _ := strings.Cut(dependency.Name, ":")
+	registryUrl := ds.getRegistryUrl("https://gitlab.com/api/v4", dependency.RegistryUrls)
+	return strings.TrimSuffix(strings.TrimSuffix(registryUrl, "/"), "/api/v4") + "/" + projectPath, nil
+}
+
 ////////////////////////////////////////////////////////////
 // Internal
 ////////////////////////////////////////////////////////////
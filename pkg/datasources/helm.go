@@ -12,17 +12,61 @@ import (
 
 type HelmDatasource struct {
 	*datasourceBase
+	// The entries of the parsed index files by their url, so they are only downloaded once.
+	indexes map[string]map[string][]*helmIndexEntry
 }
 
 func NewHelmDatasource(settings *common.DatasourceSettings) common.IDatasource {
 	newDatasource := &HelmDatasource{
 		datasourceBase: newDatasourceBase(common.DATASOURCE_TYPE_HELM, settings),
+		indexes:        map[string]map[string][]*helmIndexEntry{},
 	}
 	newDatasource.impl = newDatasource
 	return newDatasource
 }
 
 func (ds *HelmDatasource) GetReleases(dependency *common.Dependency) ([]*common.ReleaseInfo, error) {
+	dependencyEntries, err := ds.getIndexEntries(dependency)
+	if err != nil {
+		return nil, err
+	}
+
+	releases := []*common.ReleaseInfo{}
+	for _, entry := range dependencyEntries {
+		newRelease := &common.ReleaseInfo{
+			VersionString: entry.Version,
+			ReleaseDate:   entry.Created,
+			Digest:        entry.Digest,
+		}
+		releases = append(releases, newRelease)
+	}
+	return releases, nil
+}
+
+// Gets the first of the sources of the chart version.
+func (ds *HelmDatasource) GetSourceUrl(dependency *common.Dependency, release *common.ReleaseInfo) (string, error) {
+	dependencyEntries, err := ds.getIndexEntries(dependency)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range dependencyEntries {
+		if entry.Version == release.VersionString && len(entry.Sources) > 0 {
+			return entry.Sources[0], nil
+		}
+	}
+	return "", nil
+}
+
+type helmIndexEntry struct {
+	Name    string    `yaml:"name"`
+	Version string    `yaml:"version"`
+	Created time.Time `yaml:"created"`
+	Digest  string    `yaml:"digest"`
+	Sources []string  `yaml:"sources"`
+}
+
+// Gets the entries of the dependency from the index.yaml file of the repository.
+func (ds *HelmDatasource) getIndexEntries(dependency *common.Dependency) ([]*helmIndexEntry, error) {
 	// Get index.yaml file
 	helmRepository := dependency.RegistryUrls[0]
 
@@ -34,45 +78,34 @@ func (ds *HelmDatasource) GetReleases(dependency *common.Dependency) ([]*common.
 		return nil, nil
 	}
 
-	// Todo: Cache
 	indexUrl, err := url.JoinPath(helmRepository, "index.yaml")
 	if err != nil {
 		return nil, err
 	}
-	ds.logger.Debug(fmt.Sprintf("Fetching index from %s", indexUrl))
-	indexBytes, err := common.HttpUtil.DownloadToMemory(indexUrl)
-	if err != nil {
-		return nil, err
-	}
+	entries, ok := ds.indexes[indexUrl]
+	if !ok {
+		ds.logger.Debug(fmt.Sprintf("Fetching index from %s", indexUrl))
+		indexBytes, err := common.HttpUtil.DownloadToMemory(indexUrl)
+		if err != nil {
+			return nil, err
+		}
 
-	indexEntries := struct {
-		Entries map[string][]struct {
-			Name    string    `yaml:"name"`
-			Version string    `yaml:"version"`
-			Created time.Time `yaml:"created"`
-			Digest  string    `yaml:"digest"`
-		} `yaml:"entries"`
-	}{}
+		indexEntries := struct {
+			Entries map[string][]*helmIndexEntry `yaml:"entries"`
+		}{}
 
-	if err := yaml.Unmarshal([]byte(indexBytes), &indexEntries); err != nil {
-		return nil, fmt.Errorf("failed unmarshalling index.yaml")
+		if err := yaml.Unmarshal([]byte(indexBytes), &indexEntries); err != nil {
+			return nil, fmt.Errorf("failed unmarshalling index.yaml")
+		}
+		entries = indexEntries.Entries
+		ds.indexes[indexUrl] = entries
 	}
 
-	releases := []*common.ReleaseInfo{}
-	dependencyEntries, ok := indexEntries.Entries[dependency.Name]
-	if !ok {
-		return releases, nil
-	}
-	for _, entry := range dependencyEntries {
-		if entry.Name != dependency.Name {
-			continue
-		}
-		newRelease := &common.ReleaseInfo{
-			VersionString: entry.Version,
-			ReleaseDate:   entry.Created,
-			Digest:        entry.Digest,
+	dependencyEntries := []*helmIndexEntry{}
+	for _, entry := range entries[dependency.Name] {
+		if entry.Name == dependency.Name {
+			dependencyEntries = append(dependencyEntries, entry)
 		}
-		releases = append(releases, newRelease)
 	}
-	return releases, nil
+	return dependencyEntries, nil
 }
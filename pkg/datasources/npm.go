@@ -11,35 +11,25 @@ import (
 
 type NpmDatasource struct {
 	*datasourceBase
+	// The parsed index files by their url, so they are only downloaded once.
+	packages map[string]*npmResponse
 }
 
 func NewNpmDatasource(settings *common.DatasourceSettings) common.IDatasource {
 	newDatasource := &NpmDatasource{
 		datasourceBase: newDatasourceBase(common.DATASOURCE_TYPE_NPM, settings),
+		packages:       map[string]*npmResponse{},
 	}
 	newDatasource.impl = newDatasource
 	return newDatasource
 }
 
 func (ds *NpmDatasource) GetReleases(dependency *common.Dependency) ([]*common.ReleaseInfo, error) {
-	registryUrl := ds.getRegistryUrl("https://registry.npmjs.org", dependency.RegistryUrls)
-
-	// Download the index file
-	downloadUrl, err := url.JoinPath(registryUrl, dependency.Name)
-	if err != nil {
-		return nil, err
-	}
-	indexFileBytes, err := common.HttpUtil.DownloadToMemory(downloadUrl)
+	jsonData, err := ds.getPackage(dependency)
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse the data as json
-	var jsonData npmResponse
-	if err := json.Unmarshal(indexFileBytes, &jsonData); err != nil {
-		return nil, err
-	}
-
 	// Convert all entries to objects
 	releases := []*common.ReleaseInfo{}
 	for _, entry := range jsonData.Versions {
@@ -74,14 +64,75 @@ func (ds *NpmDatasource) GetReleases(dependency *common.Dependency) ([]*common.R
 	return releases, nil
 }
 
+// Gets the repository of the release or of the package if the release has none.
+func (ds *NpmDatasource) GetSourceUrl(dependency *common.Dependency, release *common.ReleaseInfo) (string, error) {
+	jsonData, err := ds.getPackage(dependency)
+	if err != nil {
+		return "", err
+	}
+	if version, ok := jsonData.Versions[release.VersionString]; ok && version.Repository.Url != "" {
+		return version.Repository.Url, nil
+	}
+	return jsonData.Repository.Url, nil
+}
+
+// Downloads and parses the index file of the package. The index file is only downloaded once.
+func (ds *NpmDatasource) getPackage(dependency *common.Dependency) (*npmResponse, error) {
+	registryUrl := ds.getRegistryUrl("https://registry.npmjs.org", dependency.RegistryUrls)
+
+	// Download the index file
+	downloadUrl, err := url.JoinPath(registryUrl, dependency.Name)
+	if err != nil {
+		return nil, err
+	}
+	if jsonData, ok := ds.packages[downloadUrl]; ok {
+		return jsonData, nil
+	}
+	indexFileBytes, err := common.HttpUtil.DownloadToMemory(downloadUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the data as json
+	var jsonData npmResponse
+	if err := json.Unmarshal(indexFileBytes, &jsonData); err != nil {
+		return nil, err
+	}
+	ds.packages[downloadUrl] = &jsonData
+	return &jsonData, nil
+}
+
 type npmResponse struct {
-	Versions map[string]*npmVersion `json:"versions"`
-	Time     map[string]time.Time   `json:"time"`
+	Versions   map[string]*npmVersion `json:"versions"`
+	Time       map[string]time.Time   `json:"time"`
+	Repository npmRepository          `json:"repository"`
+}
+
+// The repository of a package which is either just an url or an object with the url.
+// Other forms are ignored.
+type npmRepository struct {
+	Url string
+}
+
+func (r *npmRepository) UnmarshalJSON(data []byte) error {
+	var repositoryUrl string
+	if err := json.Unmarshal(data, &repositoryUrl); err == nil {
+		r.Url = repositoryUrl
+		return nil
+	}
+	var repository struct {
+		Url string `json:"url"`
+	}
+	if err := json.Unmarshal(data, &repository); err == nil {
+		r.Url = repository.Url
+	}
+	return nil
 }
 
 type npmVersion struct {
-	Version string `json:"version"`
-	Dist    *struct {
+	Version    string        `json:"version"`
+	Repository npmRepository `json:"repository"`
+	Dist       *struct {
 		Shasum    string `json:"shasum"`
 		Integrity string `json:"integrity"`
 	} `json:"dist"`
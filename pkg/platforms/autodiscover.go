@@ -0,0 +1,134 @@
+package platforms
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/roemer/gonovate/pkg/common"
+)
+
+// The types of the platforms which support autodiscover.
+var AutodiscoverPlatformTypes = []common.PlatformType{
+	common.PLATFORM_TYPE_GITEA,
+	common.PLATFORM_TYPE_GITHUB,
+	common.PLATFORM_TYPE_GITLAB,
+}
+
+// Optional capability of platforms that can list the repositories to discover projects.
+type IAutodiscoverPlatform interface {
+	// Lists the repositories of the given namespaces or all repositories that are visible to the credentials if there are none.
+	// As some platforms need additional requests for the topics, they only need to be filled for the repositories
+	// for which needsTopics returns true. It is nil if no topics are needed at all.
+	ListRepositories(namespaces []string, needsTopics func(repository *RepositoryInfo) bool) ([]*RepositoryInfo, error)
+}
+
+// Platform independent information about a repository.
+type RepositoryInfo struct {
+	Path     string
+	Topics   []string
+	Archived bool
+	Fork     bool
+}
+
+// Defines which repositories are discovered.
+type AutodiscoverSettings struct {
+	// The namespaces to search. Searches all visible repositories if empty.
+	Namespaces []string
+	// Patterns of the paths to include (all if empty) or exclude. Either globs or regexps when prefixed with "re:".
+	Includes []string
+	Excludes []string
+	// Topics of which a repository needs at least one (if any) or which exclude a repository.
+	Topics        []string
+	ExcludeTopics []string
+	// Flags to also discover archived repositories and forks.
+	IncludeArchived bool
+	IncludeForks    bool
+}
+
+// Discovers the projects of the platform which match the settings, sorted by their path.
+func DiscoverProjects(platform IAutodiscoverPlatform, settings *AutodiscoverSettings) ([]*common.Project, error) {
+	var needsTopics func(repository *RepositoryInfo) bool
+	if len(settings.Topics) > 0 || len(settings.ExcludeTopics) > 0 {
+		// Only the repositories which are not filtered out otherwise need their topics (invalid patterns are reported later)
+		needsTopics = func(repository *RepositoryInfo) bool {
+			isMatch, err := repositoryMatchesWithoutTopics(repository, settings)
+			return err != nil || isMatch
+		}
+	}
+	repositories, err := platform.ListRepositories(settings.Namespaces, needsTopics)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing the repositories: %w", err)
+	}
+	projects := []*common.Project{}
+	for _, repository := range repositories {
+		isMatch, err := repositoryMatches(repository, settings)
+		if err != nil {
+			return nil, err
+		}
+		if isMatch && !slices.ContainsFunc(projects, func(p *common.Project) bool { return p.Path == repository.Path }) {
+			projects = append(projects, &common.Project{Path: repository.Path})
+		}
+	}
+	slices.SortFunc(projects, func(a, b *common.Project) int { return cmp.Compare(a.Path, b.Path) })
+	return projects, nil
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+func repositoryMatches(repository *RepositoryInfo, settings *AutodiscoverSettings) (bool, error) {
+	if isMatch, err := repositoryMatchesWithoutTopics(repository, settings); err != nil || !isMatch {
+		return false, err
+	}
+	hasTopic := func(topic string) bool {
+		return slices.ContainsFunc(repository.Topics, func(t string) bool { return strings.EqualFold(t, topic) })
+	}
+	if len(settings.Topics) > 0 && !slices.ContainsFunc(settings.Topics, hasTopic) {
+		return false, nil
+	}
+	return !slices.ContainsFunc(settings.ExcludeTopics, hasTopic), nil
+}
+
+// Checks the flags and the path of the repository against the settings.
+func repositoryMatchesWithoutTopics(repository *RepositoryInfo, settings *AutodiscoverSettings) (bool, error) {
+	if (repository.Archived && !settings.IncludeArchived) || (repository.Fork && !settings.IncludeForks) {
+		return false, nil
+	}
+	if len(settings.Includes) > 0 {
+		isIncluded, err := pathMatchesAny(repository.Path, settings.Includes)
+		if err != nil || !isIncluded {
+			return false, err
+		}
+	}
+	isExcluded, err := pathMatchesAny(repository.Path, settings.Excludes)
+	return !isExcluded && err == nil, err
+}
+
+// Checks if the path matches any of the patterns. Globs are matched case-insensitive like the paths on the platforms.
+func pathMatchesAny(path string, patterns []string) (bool, error) {
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "re:") {
+			regex, err := regexp.Compile(pattern[3:])
+			if err != nil {
+				return false, fmt.Errorf("invalid autodiscover regexp '%s': %w", pattern[3:], err)
+			}
+			if regex.MatchString(path) {
+				return true, nil
+			}
+			continue
+		}
+		isMatch, err := doublestar.Match(strings.ToLower(pattern), strings.ToLower(path))
+		if err != nil {
+			return false, fmt.Errorf("invalid autodiscover pattern '%s': %w", pattern, err)
+		}
+		if isMatch {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,120 @@
+package platforms
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A platform which returns fixed repositories.
+type fakeAutodiscoverPlatform struct {
+	repositories []*RepositoryInfo
+	// The repositories for which the topics were requested.
+	withTopics []string
+}
+
+func (p *fakeAutodiscoverPlatform) ListRepositories(namespaces []string, needsTopics func(repository *RepositoryInfo) bool) ([]*RepositoryInfo, error) {
+	p.withTopics = []string{}
+	for _, repository := range p.repositories {
+		if needsTopics != nil && needsTopics(repository) {
+			p.withTopics = append(p.withTopics, repository.Path)
+		}
+	}
+	return p.repositories, nil
+}
+
+func TestDiscoverProjects(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	platform := &fakeAutodiscoverPlatform{repositories: []*RepositoryInfo{
+		{Path: "org/service-b", Topics: []string{"backend"}},
+		{Path: "org/service-a", Topics: []string{"Backend", "gonovate"}},
+		{Path: "org/service-a"},
+		{Path: "org/archived", Archived: true},
+		{Path: "org/fork", Fork: true},
+		{Path: "org/legacy-service", Topics: []string{"backend"}},
+		{Path: "group/sub/project"},
+	}}
+	projectPaths := func(projects []*common.Project) []string {
+		return lo.Map(projects, func(p *common.Project, _ int) string { return p.Path })
+	}
+
+	// Without filters, archived repositories and forks are excluded
+	projects, err := DiscoverProjects(platform, &AutodiscoverSettings{})
+	require.NoError(err)
+	assert.Equal([]string{"group/sub/project", "org/legacy-service", "org/service-a", "org/service-b"}, projectPaths(projects))
+	assert.Empty(platform.withTopics)
+
+	// Including archived repositories and forks
+	projects, err = DiscoverProjects(platform, &AutodiscoverSettings{IncludeArchived: true, IncludeForks: true})
+	require.NoError(err)
+	assert.Contains(projectPaths(projects), "org/archived")
+	assert.Contains(projectPaths(projects), "org/fork")
+
+	// Globs and regexps
+	projects, err = DiscoverProjects(platform, &AutodiscoverSettings{Includes: []string{"ORG/*", "group/**"}, Excludes: []string{"re:^org/legacy-"}})
+	require.NoError(err)
+	assert.Equal([]string{"group/sub/project", "org/service-a", "org/service-b"}, projectPaths(projects))
+
+	// Topics
+	projects, err = DiscoverProjects(platform, &AutodiscoverSettings{Topics: []string{"backend"}, ExcludeTopics: []string{"gonovate"}})
+	require.NoError(err)
+	assert.Equal([]string{"org/legacy-service", "org/service-b"}, projectPaths(projects))
+
+	// Only the repositories which are not filtered out otherwise need their topics
+	_, err = DiscoverProjects(platform, &AutodiscoverSettings{Includes: []string{"org/**"}, Topics: []string{"backend"}})
+	require.NoError(err)
+	assert.Equal([]string{"org/service-b", "org/service-a", "org/service-a", "org/legacy-service"}, platform.withTopics)
+
+	// Invalid patterns
+	_, err = DiscoverProjects(platform, &AutodiscoverSettings{Includes: []string{"re:[a-z"}})
+	assert.Error(err)
+}
+
+func TestAutodiscoverPlatformTypes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	for _, platformType := range common.AllPlatformTypes {
+		platform, err := GetPlatform(&common.PlatformSettings{Logger: slog.Default(), Platform: platformType})
+		require.NoError(err)
+		_, ok := platform.(IAutodiscoverPlatform)
+		assert.Equal(slices.Contains(AutodiscoverPlatformTypes, platformType), ok, platformType)
+	}
+}
+
+func TestGitHubListRepositories(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/orgs/org/repos", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"full_name":"org/repo","topics":["backend"]},{"full_name":"org/old","archived":true}]`))
+	})
+	mux.HandleFunc("GET /api/v3/orgs/user/repos", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	})
+	mux.HandleFunc("GET /api/v3/users/user/repos", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"full_name":"user/fork","fork":true}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	platform := NewGitHubPlatform(&common.PlatformSettings{Logger: slog.Default(), Platform: common.PLATFORM_TYPE_GITHUB, Token: "token", Endpoint: server.URL})
+	repositories, err := platform.ListRepositories([]string{"org", "user"}, nil)
+	require.NoError(err)
+	assert.Equal([]*RepositoryInfo{
+		{Path: "org/repo", Topics: []string{"backend"}},
+		{Path: "org/old", Archived: true},
+		{Path: "user/fork", Fork: true},
+	}, repositories)
+}
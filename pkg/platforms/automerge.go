@@ -0,0 +1,12 @@
+package platforms
+
+import (
+	"github.com/roemer/gonovate/pkg/common"
+)
+
+// Optional capability of platforms that can merge PRs/MRs themselves.
+type IAutomergePlatform interface {
+	// Merges the PR/MR of the update group if it is mergeable and all checks succeeded.
+	// Returns true if the PR/MR was merged.
+	MergeIfReady(project *common.Project, updateGroup *common.UpdateGroup) (bool, error)
+}
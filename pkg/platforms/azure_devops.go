@@ -0,0 +1,285 @@
+package platforms
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/samber/lo"
+)
+
+// Azure DevOps limits the description of PRs to this amount of characters.
+const azureDevOpsMaxDescriptionLength = 4000
+
+type AzureDevOpsPlatform struct {
+	*GitPlatform
+	// The organization of the fetched project. Needed to lookup the author.
+	organization string
+}
+
+func NewAzureDevOpsPlatform(settings *common.PlatformSettings) *AzureDevOpsPlatform {
+	platform := &AzureDevOpsPlatform{
+		GitPlatform: NewGitPlatform(settings),
+	}
+	platform.impl = platform
+	return platform
+}
+
+func (p *AzureDevOpsPlatform) Type() common.PlatformType {
+	return common.PLATFORM_TYPE_AZURE_DEVOPS
+}
+
+func (p *AzureDevOpsPlatform) FetchProject(project *common.Project) error {
+	// Prepare the data for the API
+	organization, projectName, repositoryName, err := project.SplitOrganizationPath()
+	if err != nil {
+		return err
+	}
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	// Get the repository
+	repository, err := client.getRepository(organization, projectName, repositoryName)
+	if err != nil {
+		return err
+	}
+	if repository.IsDisabled {
+		return fmt.Errorf("the repository of project '%s' is disabled", project.Path)
+	}
+	p.organization = organization
+	cloneUrl, err := url.Parse(repository.RemoteUrl)
+	if err != nil {
+		return err
+	}
+	cloneUrl.User = url.UserPassword("gonovate", p.settings.TokenExpanded())
+	_, _, err = common.Git.Run("clone", cloneUrl.String(), ClonePath)
+	return err
+}
+
+func (p *AzureDevOpsPlatform) LookupAuthor() (string, string, error) {
+	organization, err := p.getOrganization()
+	if err != nil {
+		return "", "", err
+	}
+	client, err := p.createClient()
+	if err != nil {
+		return "", "", err
+	}
+	user, err := client.getCurrentUser(organization)
+	if err != nil {
+		return "", "", err
+	}
+	return user.Name, user.Email, nil
+}
+
+func (p *AzureDevOpsPlatform) NotifyChanges(project *common.Project, updateGroup *common.UpdateGroup) error {
+	// Prepare the data for the API
+	organization, projectName, repositoryName, err := project.SplitOrganizationPath()
+	if err != nil {
+		return err
+	}
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	// Build the content of the PR
+	content := p.buildLimitedPullRequestBody(updateGroup, azureDevOpsMaxDescriptionLength)
+
+	// Resolve the ids of the reviewers
+	reviewerIds := []string{}
+	for _, reviewer := range updateGroup.Reviewers {
+		reviewerId, err := client.findIdentityId(organization, reviewer)
+		if err != nil {
+			return err
+		}
+		if reviewerId == "" {
+			p.logger.Warn(fmt.Sprintf("Could not find the reviewer '%s'", reviewer))
+			continue
+		}
+		reviewerIds = append(reviewerIds, reviewerId)
+	}
+
+	// Search for an existing PR
+	pullRequest, err := client.findActivePullRequest(organization, projectName, repositoryName, updateGroup.BranchName, p.settings.BaseBranch)
+	if err != nil {
+		return err
+	}
+	if pullRequest != nil {
+		p.logger.Info(fmt.Sprintf("PR already exists: %s", getAzureDevOpsPullRequestUrl(pullRequest)))
+
+		// Update the PR if something changed
+		if pullRequest.Title != updateGroup.Title || pullRequest.Description != content {
+			p.logger.Debug("Updating PR")
+			if _, err := client.updatePullRequest(organization, projectName, repositoryName, pullRequest.PullRequestId, &azureDevOpsPullRequest{
+				Title:       updateGroup.Title,
+				Description: content,
+			}); err != nil {
+				return err
+			}
+		}
+		existingLabels := lo.FilterMap(pullRequest.Labels, func(label *azureDevOpsLabel, _ int) (string, bool) { return label.Name, label.Active })
+		newLabels := lo.Without(updateGroup.Labels, existingLabels...)
+		if len(newLabels) > 0 {
+			p.logger.Debug("Updating PR labels")
+			for _, label := range newLabels {
+				if err := client.addLabel(organization, projectName, repositoryName, pullRequest.PullRequestId, label); err != nil {
+					return err
+				}
+			}
+		}
+		existingReviewerIds := lo.Map(pullRequest.Reviewers, func(reviewer *azureDevOpsReviewer, _ int) string { return reviewer.Id })
+		newReviewerIds := lo.Without(reviewerIds, existingReviewerIds...)
+		if len(newReviewerIds) > 0 {
+			p.logger.Debug("Updating PR reviewers")
+			for _, reviewerId := range newReviewerIds {
+				if err := client.addReviewer(organization, projectName, repositoryName, pullRequest.PullRequestId, reviewerId); err != nil {
+					return err
+				}
+			}
+		}
+	} else {
+		// Create the PR
+		pr, err := client.createPullRequest(organization, projectName, repositoryName, &azureDevOpsPullRequest{
+			Title:         updateGroup.Title,
+			Description:   content,
+			SourceRefName: "refs/heads/" + updateGroup.BranchName,
+			TargetRefName: "refs/heads/" + p.settings.BaseBranch,
+			Labels: lo.Map(updateGroup.Labels, func(label string, _ int) *azureDevOpsLabel {
+				return &azureDevOpsLabel{Name: label}
+			}),
+			Reviewers: lo.Map(reviewerIds, func(reviewerId string, _ int) *azureDevOpsReviewer {
+				return &azureDevOpsReviewer{Id: reviewerId}
+			}),
+		})
+		if err != nil {
+			return err
+		}
+		p.logger.Info(fmt.Sprintf("Created PR: %s", getAzureDevOpsPullRequestUrl(pr)))
+		pullRequest = pr
+	}
+
+	// Set the PR to complete automatically when all policies succeeded
+	if updateGroup.Automerge && updateGroup.PlatformAutomerge && pullRequest.AutoCompleteSetBy == nil {
+		p.logger.Info("Enabling auto-complete for the PR")
+		user, err := client.getCurrentUser(organization)
+		if err != nil {
+			return err
+		}
+		if _, err := client.updatePullRequest(organization, projectName, repositoryName, pullRequest.PullRequestId, &azureDevOpsPullRequest{
+			AutoCompleteSetBy: &azureDevOpsIdentity{Id: user.Id},
+			CompletionOptions: &azureDevOpsCompletionOptions{
+				MergeStrategy:      getAzureDevOpsMergeStrategy(updateGroup.GetAutomergeStrategy()),
+				DeleteSourceBranch: true,
+			},
+		}); err != nil {
+			return fmt.Errorf("failed enabling auto-complete: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *AzureDevOpsPlatform) Cleanup(cleanupSettings *PlatformCleanupSettings) error {
+	// Prepare the data for the API
+	organization, projectName, repositoryName, err := cleanupSettings.Project.SplitOrganizationPath()
+	if err != nil {
+		return err
+	}
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	return p.cleanupBranches(cleanupSettings, func(branchName string) error {
+		// Search for an existing PR
+		existingPr, err := client.findActivePullRequest(organization, projectName, repositoryName, branchName, cleanupSettings.BaseBranch)
+		if err != nil || existingPr == nil {
+			return err
+		}
+		// Abandon the PR (without the update markers as the updates are not declined by this)
+		p.logger.Info(fmt.Sprintf("Abandoning associated PR: %s", getAzureDevOpsPullRequestUrl(existingPr)))
+		_, err = client.updatePullRequest(organization, projectName, repositoryName, existingPr.PullRequestId, &azureDevOpsPullRequest{
+			Status:      "abandoned",
+			Description: removeUpdateMarkers(existingPr.Description),
+		})
+		return err
+	})
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+func (p *AzureDevOpsPlatform) createClient() (*azureDevOpsClient, error) {
+	if p.settings == nil || p.settings.Token == "" {
+		return nil, fmt.Errorf("no platform token defined")
+	}
+	endpoint := p.settings.EndpointExpanded()
+	if endpoint == "" {
+		endpoint = "https://dev.azure.com"
+	}
+	return newAzureDevOpsClient(endpoint, p.settings.TokenExpanded()), nil
+}
+
+// Gets the organization of the current project. It is remembered when the project is fetched and otherwise taken from the url of the remote.
+func (p *AzureDevOpsPlatform) getOrganization() (string, error) {
+	if p.organization != "" {
+		return p.organization, nil
+	}
+	stdout, _, err := common.Git.Run("remote", "get-url", p.getRemoteName())
+	if err != nil {
+		return "", err
+	}
+	return getAzureDevOpsOrganization(strings.TrimSpace(stdout))
+}
+
+// Gets the organization (or collection) from the url of a repository like "https://dev.azure.com/org/project/_git/repo".
+func getAzureDevOpsOrganization(remoteUrl string) (string, error) {
+	// SSH like "git@ssh.dev.azure.com:v3/org/project/repo"
+	if _, sshPath, found := strings.Cut(remoteUrl, ":v3/"); found {
+		organization, _, _ := strings.Cut(sshPath, "/")
+		return url.PathUnescape(organization)
+	}
+	parsedUrl, err := url.Parse(remoteUrl)
+	if err != nil {
+		return "", fmt.Errorf("failed parsing the remote url: %w", err)
+	}
+	// The legacy urls have the organization as subdomain
+	if organization, found := strings.CutSuffix(parsedUrl.Hostname(), ".visualstudio.com"); found {
+		return organization, nil
+	}
+	segments := strings.Split(strings.Trim(parsedUrl.Path, "/"), "/")
+	gitIndex := slices.Index(segments, "_git")
+	if gitIndex < 2 {
+		return "", fmt.Errorf("could not find the organization in the remote url")
+	}
+	return url.PathUnescape(segments[gitIndex-2])
+}
+
+func getAzureDevOpsPullRequestUrl(pullRequest *azureDevOpsPullRequest) string {
+	if pullRequest.Repository != nil && pullRequest.Repository.WebUrl != "" {
+		return fmt.Sprintf("%s/pullrequest/%d", pullRequest.Repository.WebUrl, pullRequest.PullRequestId)
+	}
+	return fmt.Sprintf("!%d", pullRequest.PullRequestId)
+}
+
+func getAzureDevOpsMergeStrategy(strategy common.AutomergeStrategy) string {
+	switch strategy {
+	case common.AUTOMERGE_STRATEGY_SQUASH:
+		return "squash"
+	case common.AUTOMERGE_STRATEGY_REBASE:
+		return "rebase"
+	default:
+		return "noFastForward"
+	}
+}
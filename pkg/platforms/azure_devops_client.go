@@ -0,0 +1,245 @@
+package platforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/roemer/gonovate/pkg/common"
+)
+
+const azureDevOpsApiVersion = "7.1"
+
+// A minimal client for the REST API of Azure DevOps (Services and Server).
+type azureDevOpsClient struct {
+	// The url of the server like "https://dev.azure.com".
+	baseUrl    string
+	token      string
+	httpClient *http.Client
+}
+
+type azureDevOpsRepository struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	RemoteUrl  string `json:"remoteUrl"`
+	WebUrl     string `json:"webUrl"`
+	IsDisabled bool   `json:"isDisabled"`
+}
+
+type azureDevOpsIdentity struct {
+	Id          string `json:"id"`
+	DisplayName string `json:"displayName,omitempty"`
+	UniqueName  string `json:"uniqueName,omitempty"`
+}
+
+type azureDevOpsReviewer struct {
+	Id          string `json:"id"`
+	DisplayName string `json:"displayName,omitempty"`
+	UniqueName  string `json:"uniqueName,omitempty"`
+	Vote        int    `json:"vote"`
+}
+
+type azureDevOpsLabel struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active,omitempty"`
+}
+
+type azureDevOpsCompletionOptions struct {
+	MergeStrategy      string `json:"mergeStrategy,omitempty"`
+	DeleteSourceBranch bool   `json:"deleteSourceBranch"`
+}
+
+type azureDevOpsPullRequest struct {
+	PullRequestId     int                           `json:"pullRequestId,omitempty"`
+	Status            string                        `json:"status,omitempty"`
+	Title             string                        `json:"title,omitempty"`
+	Description       string                        `json:"description,omitempty"`
+	SourceRefName     string                        `json:"sourceRefName,omitempty"`
+	TargetRefName     string                        `json:"targetRefName,omitempty"`
+	Reviewers         []*azureDevOpsReviewer        `json:"reviewers,omitempty"`
+	Labels            []*azureDevOpsLabel           `json:"labels,omitempty"`
+	AutoCompleteSetBy *azureDevOpsIdentity          `json:"autoCompleteSetBy,omitempty"`
+	CompletionOptions *azureDevOpsCompletionOptions `json:"completionOptions,omitempty"`
+	Repository        *azureDevOpsRepository        `json:"repository,omitempty"`
+}
+
+// The user of the token.
+type azureDevOpsUser struct {
+	Id    string
+	Name  string
+	Email string
+}
+
+// A list response of the REST API.
+type azureDevOpsList[T any] struct {
+	Count int `json:"count"`
+	Value []T `json:"value"`
+}
+
+// The error response of the REST API.
+type azureDevOpsError struct {
+	Message string `json:"message"`
+}
+
+func newAzureDevOpsClient(endpoint string, token string) *azureDevOpsClient {
+	return &azureDevOpsClient{
+		baseUrl:    strings.TrimSuffix(endpoint, "/"),
+		token:      token,
+		httpClient: &http.Client{},
+	}
+}
+
+// Gets the repository in the given organization and project.
+func (c *azureDevOpsClient) getRepository(organization string, project string, repository string) (*azureDevOpsRepository, error) {
+	result := &azureDevOpsRepository{}
+	if err := c.do(http.MethodGet, c.baseUrl+repositoryAzureDevOpsApiPath(organization, project, repository), nil, nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Gets the user of the token.
+func (c *azureDevOpsClient) getCurrentUser(organization string) (*azureDevOpsUser, error) {
+	connectionData := &struct {
+		AuthenticatedUser *struct {
+			Id                  string `json:"id"`
+			ProviderDisplayName string `json:"providerDisplayName"`
+			Properties          map[string]struct {
+				Value string `json:"$value"`
+			} `json:"properties"`
+		} `json:"authenticatedUser"`
+	}{}
+	if err := c.do(http.MethodGet, fmt.Sprintf("%s/%s/_apis/connectionData", c.baseUrl, url.PathEscape(organization)), nil, nil, connectionData); err != nil {
+		return nil, err
+	}
+	if connectionData.AuthenticatedUser == nil || connectionData.AuthenticatedUser.Id == "" {
+		return nil, fmt.Errorf("failed to lookup the current user. Is the token valid?")
+	}
+	return &azureDevOpsUser{
+		Id:    connectionData.AuthenticatedUser.Id,
+		Name:  connectionData.AuthenticatedUser.ProviderDisplayName,
+		Email: connectionData.AuthenticatedUser.Properties["Account"].Value,
+	}, nil
+}
+
+// Searches the id of the identity (user or group) with the given name or email. Returns an empty string if there is none.
+func (c *azureDevOpsClient) findIdentityId(organization string, name string) (string, error) {
+	// Azure DevOps Services has the identities on a separate host
+	identitiesUrl := c.baseUrl
+	if parsedUrl, err := url.Parse(c.baseUrl); err == nil && parsedUrl.Host == "dev.azure.com" {
+		parsedUrl.Host = "vssps.dev.azure.com"
+		identitiesUrl = parsedUrl.String()
+	}
+	query := url.Values{}
+	query.Set("searchFilter", "General")
+	query.Set("filterValue", name)
+	query.Set("queryMembership", "None")
+	identities := &azureDevOpsList[*azureDevOpsIdentity]{}
+	if err := c.do(http.MethodGet, fmt.Sprintf("%s/%s/_apis/identities", identitiesUrl, url.PathEscape(organization)), query, nil, identities); err != nil {
+		return "", err
+	}
+	if len(identities.Value) == 0 {
+		return "", nil
+	}
+	return identities.Value[0].Id, nil
+}
+
+// Searches for the active PR from the given branch into the given base branch. Returns nil if there is none.
+func (c *azureDevOpsClient) findActivePullRequest(organization string, project string, repository string, branchName string, baseBranch string) (*azureDevOpsPullRequest, error) {
+	query := url.Values{}
+	query.Set("searchCriteria.status", "active")
+	query.Set("searchCriteria.sourceRefName", "refs/heads/"+branchName)
+	query.Set("searchCriteria.targetRefName", "refs/heads/"+baseBranch)
+	pullRequests := &azureDevOpsList[*azureDevOpsPullRequest]{}
+	if err := c.do(http.MethodGet, c.baseUrl+repositoryAzureDevOpsApiPath(organization, project, repository)+"/pullrequests", query, nil, pullRequests); err != nil {
+		return nil, err
+	}
+	if len(pullRequests.Value) == 0 {
+		return nil, nil
+	}
+	return pullRequests.Value[0], nil
+}
+
+// Creates a new PR.
+func (c *azureDevOpsClient) createPullRequest(organization string, project string, repository string, pullRequest *azureDevOpsPullRequest) (*azureDevOpsPullRequest, error) {
+	result := &azureDevOpsPullRequest{}
+	if err := c.do(http.MethodPost, c.baseUrl+repositoryAzureDevOpsApiPath(organization, project, repository)+"/pullrequests", nil, pullRequest, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Updates the given fields of a PR.
+func (c *azureDevOpsClient) updatePullRequest(organization string, project string, repository string, pullRequestId int, pullRequest *azureDevOpsPullRequest) (*azureDevOpsPullRequest, error) {
+	result := &azureDevOpsPullRequest{}
+	if err := c.do(http.MethodPatch, fmt.Sprintf("%s%s/pullrequests/%d", c.baseUrl, repositoryAzureDevOpsApiPath(organization, project, repository), pullRequestId), nil, pullRequest, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Adds a label (tag) to a PR.
+func (c *azureDevOpsClient) addLabel(organization string, project string, repository string, pullRequestId int, label string) error {
+	return c.do(http.MethodPost, fmt.Sprintf("%s%s/pullRequests/%d/labels", c.baseUrl, repositoryAzureDevOpsApiPath(organization, project, repository), pullRequestId), nil, &azureDevOpsLabel{Name: label}, nil)
+}
+
+// Adds a reviewer to a PR.
+func (c *azureDevOpsClient) addReviewer(organization string, project string, repository string, pullRequestId int, reviewerId string) error {
+	return c.do(http.MethodPut, fmt.Sprintf("%s%s/pullRequests/%d/reviewers/%s", c.baseUrl, repositoryAzureDevOpsApiPath(organization, project, repository), pullRequestId, url.PathEscape(reviewerId)), nil, &azureDevOpsReviewer{Id: reviewerId}, nil)
+}
+
+// Executes a request against the REST API. The request and response bodies are JSON.
+func (c *azureDevOpsClient) do(method string, requestUrl string, query url.Values, requestBody any, responseBody any) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", azureDevOpsApiVersion)
+	var bodyReader io.Reader
+	if requestBody != nil {
+		bodyBytes, err := json.Marshal(requestBody)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(bodyBytes)
+	}
+	req, err := http.NewRequest(method, requestUrl+"?"+query.Encode(), bodyReader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", common.ContentTypeJSON)
+	if requestBody != nil {
+		req.Header.Set("Content-Type", common.ContentTypeJSON)
+	}
+	// Personal access tokens are used as password with any username
+	common.HttpUtil.AddBasicAuth(req, "gonovate", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	// Invalid tokens are redirected to the sign-in page
+	if resp.StatusCode >= 300 || strings.Contains(resp.Header.Get("Content-Type"), "text/html") {
+		apiError := &azureDevOpsError{}
+		_ = json.Unmarshal(respBody, apiError)
+		return fmt.Errorf("%s %s failed with status %d: %s", method, req.URL.Path, resp.StatusCode, apiError.Message)
+	}
+	if responseBody != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, responseBody); err != nil {
+			return fmt.Errorf("failed parsing the response of %s %s: %w", method, req.URL.Path, err)
+		}
+	}
+	return nil
+}
+
+func repositoryAzureDevOpsApiPath(organization string, project string, repository string) string {
+	return fmt.Sprintf("/%s/%s/_apis/git/repositories/%s", url.PathEscape(organization), url.PathEscape(project), url.PathEscape(repository))
+}
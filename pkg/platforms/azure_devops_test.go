@@ -0,0 +1,231 @@
+package platforms
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// An in-process stand-in of the REST API of Azure DevOps.
+type fakeAzureDevOps struct {
+	*httptest.Server
+	pullRequests []*azureDevOpsPullRequest
+}
+
+func newFakeAzureDevOps(t *testing.T) *fakeAzureDevOps {
+	assert := assert.New(t)
+	server := &fakeAzureDevOps{}
+	repoPath := "/org/My Project/_apis/git/repositories/repo"
+	writeJson := func(w http.ResponseWriter, value any) {
+		w.Header().Set("Content-Type", common.ContentTypeJSON)
+		assert.NoError(json.NewEncoder(w).Encode(value))
+	}
+	findPullRequest := func(r *http.Request) *azureDevOpsPullRequest {
+		for _, pullRequest := range server.pullRequests {
+			if r.PathValue("id") == fmt.Sprint(pullRequest.PullRequestId) {
+				return pullRequest
+			}
+		}
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/_apis/connectionData", func(w http.ResponseWriter, r *http.Request) {
+		_, password, _ := r.BasicAuth()
+		assert.Equal("token", password)
+		w.Write([]byte(`{"authenticatedUser":{"id":"bot-id","providerDisplayName":"Gonovate Bot","properties":{"Account":{"$type":"System.String","$value":"bot@gonovate.org"}}}}`))
+	})
+	mux.HandleFunc("GET /org/_apis/identities", func(w http.ResponseWriter, r *http.Request) {
+		identities := &azureDevOpsList[*azureDevOpsIdentity]{Value: []*azureDevOpsIdentity{}}
+		if name := r.URL.Query().Get("filterValue"); name != "unknown" {
+			identities.Value = append(identities.Value, &azureDevOpsIdentity{Id: name + "-id"})
+		}
+		writeJson(w, identities)
+	})
+	mux.HandleFunc("GET "+repoPath+"/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("7.1", r.URL.Query().Get("api-version"))
+		matches := &azureDevOpsList[*azureDevOpsPullRequest]{Value: []*azureDevOpsPullRequest{}}
+		for _, pullRequest := range server.pullRequests {
+			if pullRequest.Status == r.URL.Query().Get("searchCriteria.status") &&
+				pullRequest.SourceRefName == r.URL.Query().Get("searchCriteria.sourceRefName") &&
+				pullRequest.TargetRefName == r.URL.Query().Get("searchCriteria.targetRefName") {
+				matches.Value = append(matches.Value, pullRequest)
+			}
+		}
+		writeJson(w, matches)
+	})
+	mux.HandleFunc("POST "+repoPath+"/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+		pullRequest := &azureDevOpsPullRequest{}
+		assert.NoError(json.NewDecoder(r.Body).Decode(pullRequest))
+		pullRequest.PullRequestId = len(server.pullRequests) + 1
+		pullRequest.Status = "active"
+		for _, label := range pullRequest.Labels {
+			label.Active = true
+		}
+		server.pullRequests = append(server.pullRequests, pullRequest)
+		w.WriteHeader(http.StatusCreated)
+		writeJson(w, pullRequest)
+	})
+	mux.HandleFunc("PATCH "+repoPath+"/pullrequests/{id}", func(w http.ResponseWriter, r *http.Request) {
+		pullRequest := findPullRequest(r)
+		update := &azureDevOpsPullRequest{}
+		assert.NoError(json.NewDecoder(r.Body).Decode(update))
+		if update.Title != "" {
+			pullRequest.Title = update.Title
+		}
+		if update.Description != "" {
+			pullRequest.Description = update.Description
+		}
+		if update.Status != "" {
+			pullRequest.Status = update.Status
+		}
+		if update.AutoCompleteSetBy != nil {
+			pullRequest.AutoCompleteSetBy = update.AutoCompleteSetBy
+			pullRequest.CompletionOptions = update.CompletionOptions
+		}
+		writeJson(w, pullRequest)
+	})
+	mux.HandleFunc("POST "+repoPath+"/pullRequests/{id}/labels", func(w http.ResponseWriter, r *http.Request) {
+		pullRequest := findPullRequest(r)
+		label := &azureDevOpsLabel{}
+		assert.NoError(json.NewDecoder(r.Body).Decode(label))
+		label.Active = true
+		pullRequest.Labels = append(pullRequest.Labels, label)
+		writeJson(w, label)
+	})
+	mux.HandleFunc("PUT "+repoPath+"/pullRequests/{id}/reviewers/{reviewerId}", func(w http.ResponseWriter, r *http.Request) {
+		pullRequest := findPullRequest(r)
+		reviewer := &azureDevOpsReviewer{}
+		assert.NoError(json.NewDecoder(r.Body).Decode(reviewer))
+		assert.Equal(r.PathValue("reviewerId"), reviewer.Id)
+		pullRequest.Reviewers = append(pullRequest.Reviewers, reviewer)
+		writeJson(w, reviewer)
+	})
+	server.Server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAzureDevOpsNotifyChanges(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	server := newFakeAzureDevOps(t)
+	platform := NewAzureDevOpsPlatform(&common.PlatformSettings{Logger: slog.Default(), Token: "token", Endpoint: server.URL, BaseBranch: "main"})
+	project := &common.Project{Path: "org/My Project/repo"}
+	updateGroup := &common.UpdateGroup{
+		Title:             "Update golang",
+		BranchName:        "gonovate/golang",
+		Body:              "Updates golang",
+		Labels:            []string{"dependencies"},
+		Reviewers:         []string{"alice", "unknown"},
+		Automerge:         true,
+		PlatformAutomerge: true,
+		AutomergeStrategy: common.AUTOMERGE_STRATEGY_SQUASH,
+	}
+
+	// Creates the PR with auto-complete
+	require.NoError(platform.NotifyChanges(project, updateGroup))
+	require.Len(server.pullRequests, 1)
+	pullRequest := server.pullRequests[0]
+	assert.Equal("Update golang", pullRequest.Title)
+	assert.Equal("Updates golang", pullRequest.Description)
+	assert.Equal("refs/heads/gonovate/golang", pullRequest.SourceRefName)
+	assert.Equal("refs/heads/main", pullRequest.TargetRefName)
+	assert.Equal([]*azureDevOpsLabel{{Name: "dependencies", Active: true}}, pullRequest.Labels)
+	assert.Equal([]*azureDevOpsReviewer{{Id: "alice-id"}}, pullRequest.Reviewers)
+	require.NotNil(pullRequest.AutoCompleteSetBy)
+	assert.Equal("bot-id", pullRequest.AutoCompleteSetBy.Id)
+	assert.Equal(&azureDevOpsCompletionOptions{MergeStrategy: "squash", DeleteSourceBranch: true}, pullRequest.CompletionOptions)
+
+	// Updates the PR and adds the new labels and reviewers
+	updateGroup.Title = "Update golang to 1.24"
+	updateGroup.Labels = []string{"dependencies", "golang"}
+	updateGroup.Reviewers = []string{"alice", "bob"}
+	require.NoError(platform.NotifyChanges(project, updateGroup))
+	assert.Len(server.pullRequests, 1)
+	assert.Equal("Update golang to 1.24", pullRequest.Title)
+	assert.Equal([]*azureDevOpsLabel{{Name: "dependencies", Active: true}, {Name: "golang", Active: true}}, pullRequest.Labels)
+	assert.Equal([]*azureDevOpsReviewer{{Id: "alice-id"}, {Id: "bob-id"}}, pullRequest.Reviewers)
+
+	// Projects need three segments
+	err := platform.NotifyChanges(&common.Project{Path: "org/repo"}, updateGroup)
+	assert.ErrorContains(err, "expected 'organization/project/repository'")
+}
+
+func TestAzureDevOpsLookupAuthor(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	server := newFakeAzureDevOps(t)
+	platform := NewAzureDevOpsPlatform(&common.PlatformSettings{Logger: slog.Default(), Token: "token", Endpoint: server.URL})
+	platform.organization = "org"
+	name, email, err := platform.LookupAuthor()
+	require.NoError(err)
+	assert.Equal("Gonovate Bot", name)
+	assert.Equal("bot@gonovate.org", email)
+}
+
+func TestAzureDevOpsCleanup(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runGit, _ := prepareGitClone(t)
+	server := newFakeAzureDevOps(t)
+	platform := NewAzureDevOpsPlatform(&common.PlatformSettings{Logger: slog.Default(), Token: "token", Endpoint: server.URL, BaseBranch: "main", GitAuthor: "gonovate-bot <bot@gonovate.org>"})
+	project := &common.Project{Path: "org/My Project/repo"}
+
+	// Create a branch with a PR which is no longer needed and one which is still used
+	for _, branchName := range []string{"gonovate/stale", "gonovate/active"} {
+		updateGroup := &common.UpdateGroup{BranchName: branchName, Title: "Update", Body: "Body", Dependencies: []*common.DependencyWithUpdate{
+			{Dependency: &common.Dependency{Name: "golang", Version: "1.23.0", FilePath: "Dockerfile"}, NewRelease: &common.ReleaseInfo{VersionString: "1.24.1"}},
+		}}
+		runGit("checkout", "-B", branchName, "main")
+		require.NoError(os.WriteFile("file.txt", []byte("base\n"+branchName+"\n"), os.ModePerm))
+		require.NoError(platform.SubmitChanges(updateGroup))
+		require.NoError(platform.PublishChanges(updateGroup))
+		require.NoError(platform.NotifyChanges(project, updateGroup))
+	}
+	runGit("checkout", "main")
+
+	require.NoError(platform.Cleanup(&PlatformCleanupSettings{
+		Project:      project,
+		UpdateGroups: []*common.UpdateGroup{{BranchName: "gonovate/active"}},
+		BaseBranch:   "main",
+		BranchPrefix: "gonovate/",
+	}))
+
+	// The PR is abandoned without the update markers and the branch is deleted
+	assert.Equal("abandoned", server.pullRequests[0].Status)
+	assert.Equal("Body", server.pullRequests[0].Description)
+	assert.Equal("active", server.pullRequests[1].Status)
+	branches, err := platform.getRemoteGonovateBranches("origin", "gonovate/")
+	require.NoError(err)
+	assert.Equal([]string{"gonovate/active"}, branches)
+}
+
+func TestGetAzureDevOpsOrganization(t *testing.T) {
+	assert := assert.New(t)
+
+	for remoteUrl, expected := range map[string]string{
+		"https://org@dev.azure.com/org/My%20Project/_git/repo":          "org",
+		"git@ssh.dev.azure.com:v3/org/My%20Project/repo":                "org",
+		"https://org.visualstudio.com/DefaultCollection/project/_git/r": "org",
+		"https://tfs.example.com/tfs/Collection/project/_git/repo":      "Collection",
+	} {
+		organization, err := getAzureDevOpsOrganization(remoteUrl)
+		assert.NoError(err, remoteUrl)
+		assert.Equal(expected, organization, remoteUrl)
+	}
+
+	_, err := getAzureDevOpsOrganization("/tmp/remote.git")
+	assert.Error(err)
+}
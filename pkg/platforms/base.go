@@ -3,6 +3,8 @@ package platforms
 import (
 	"fmt"
 	"log/slog"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/roemer/gonovate/pkg/common"
 )
@@ -20,6 +22,8 @@ type IPlatform interface {
 	SubmitChanges(updateGroup *common.UpdateGroup) error
 	// Checks if the remote already has the same changes.
 	IsNewOrChanged(updateGroup *common.UpdateGroup) (bool, error)
+	// Checks if the remote branch contains commits from someone else than gonovate.
+	IsModified(updateGroup *common.UpdateGroup) (bool, error)
 	// Publishes the changes to the remote location.
 	PublishChanges(updateGroup *common.UpdateGroup) error
 	// Notifies the remote about the changes with eg. MRs/PRs.
@@ -54,6 +58,10 @@ func newPlatformBase(settings *common.PlatformSettings) *platformBase {
 
 func GetPlatform(settings *common.PlatformSettings) (IPlatform, error) {
 	switch settings.Platform {
+	case common.PLATFORM_TYPE_AZURE_DEVOPS:
+		return NewAzureDevOpsPlatform(settings), nil
+	case common.PLATFORM_TYPE_BITBUCKET_SERVER:
+		return NewBitbucketServerPlatform(settings), nil
 	case common.PLATFORM_TYPE_GIT:
 		return NewGitPlatform(settings), nil
 	case common.PLATFORM_TYPE_GITEA:
@@ -67,3 +75,47 @@ func GetPlatform(settings *common.PlatformSettings) (IPlatform, error) {
 	}
 	return nil, fmt.Errorf("no platform defined for '%s'", settings.Platform)
 }
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+// Builds the body of the PR/MR from the rendered body of the update group and the hidden update markers.
+func buildPullRequestBody(updateGroup *common.UpdateGroup) string {
+	sb := &strings.Builder{}
+	sb.WriteString(updateGroup.Body)
+	// Add the hidden markers to recognize the updates when the PR/MR is closed
+	sb.WriteString("\n\n")
+	for _, dep := range updateGroup.Dependencies {
+		sb.WriteString(BuildUpdateMarker(dep) + "\n")
+	}
+	// Trim spaces / newlines
+	return strings.TrimSpace(sb.String())
+}
+
+// Builds the body of the PR/MR with at most the given number of characters. The text is shortened first.
+// If the update markers alone are still too long, the markers that do not fit are dropped.
+func (p *platformBase) buildLimitedPullRequestBody(updateGroup *common.UpdateGroup, maxLength int) string {
+	content := buildPullRequestBody(updateGroup)
+	excessLength := utf8.RuneCountInString(content) - maxLength
+	if excessLength <= 0 {
+		return content
+	}
+	const ellipsis = "\n\n..."
+	bodyRunes := []rune(updateGroup.Body)
+	shortenedGroup := *updateGroup
+	shortenedGroup.Body = string(bodyRunes[:max(0, len(bodyRunes)-excessLength-len(ellipsis))]) + ellipsis
+	content = buildPullRequestBody(&shortenedGroup)
+	if utf8.RuneCountInString(content) <= maxLength {
+		return content
+	}
+
+	// Drop the markers which do not fit anymore
+	for len(shortenedGroup.Dependencies) > 0 && utf8.RuneCountInString(content) > maxLength {
+		shortenedGroup.Dependencies = shortenedGroup.Dependencies[:len(shortenedGroup.Dependencies)-1]
+		content = buildPullRequestBody(&shortenedGroup)
+	}
+	p.logger.Warn(fmt.Sprintf("The body of '%s' is too long, declining the PR/MR only declines the first %d of %d update(s)",
+		updateGroup.Title, len(shortenedGroup.Dependencies), len(updateGroup.Dependencies)))
+	return string([]rune(content)[:min(maxLength, utf8.RuneCountInString(content))])
+}
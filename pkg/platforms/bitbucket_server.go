@@ -0,0 +1,193 @@
+package platforms
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/samber/lo"
+)
+
+// The maximum length of the description of PRs.
+const bitbucketServerMaxDescriptionLength = 32000
+
+type BitbucketServerPlatform struct {
+	*GitPlatform
+}
+
+func NewBitbucketServerPlatform(settings *common.PlatformSettings) *BitbucketServerPlatform {
+	platform := &BitbucketServerPlatform{
+		GitPlatform: NewGitPlatform(settings),
+	}
+	platform.impl = platform
+	return platform
+}
+
+func (p *BitbucketServerPlatform) Type() common.PlatformType {
+	return common.PLATFORM_TYPE_BITBUCKET_SERVER
+}
+
+func (p *BitbucketServerPlatform) FetchProject(project *common.Project) error {
+	// Prepare the data for the API
+	projectKey, repositorySlug := project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	// Get the repository
+	repository, err := client.getRepository(projectKey, repositorySlug)
+	if err != nil {
+		return err
+	}
+	cloneUrl := ""
+	if repository.Links != nil {
+		cloneUrl = firstBitbucketServerLink(repository.Links.Clone, "http")
+	}
+	if cloneUrl == "" {
+		return fmt.Errorf("could not find the http clone url of project: %s", project.Path)
+	}
+	// The token is used as password of its user
+	username, err := client.getCurrentUsername()
+	if err != nil {
+		return err
+	}
+	cloneUrlWithCredentials, err := url.Parse(cloneUrl)
+	if err != nil {
+		return err
+	}
+	cloneUrlWithCredentials.User = url.UserPassword(username, p.settings.TokenExpanded())
+	_, _, err = common.Git.Run("clone", cloneUrlWithCredentials.String(), ClonePath)
+	return err
+}
+
+func (p *BitbucketServerPlatform) LookupAuthor() (string, string, error) {
+	client, err := p.createClient()
+	if err != nil {
+		return "", "", err
+	}
+	username, err := client.getCurrentUsername()
+	if err != nil {
+		return "", "", err
+	}
+	user, err := client.getUserByName(username)
+	if err != nil {
+		return "", "", err
+	}
+	return lo.CoalesceOrEmpty(user.DisplayName, user.Name), user.EmailAddress, nil
+}
+
+func (p *BitbucketServerPlatform) NotifyChanges(project *common.Project, updateGroup *common.UpdateGroup) error {
+	// Prepare the data for the API
+	projectKey, repositorySlug := project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	// Build the content of the PR
+	content := p.buildLimitedPullRequestBody(updateGroup, bitbucketServerMaxDescriptionLength)
+
+	// PRs do not have labels
+	if len(updateGroup.Labels) > 0 {
+		p.logger.Debug("Labels are not supported by Bitbucket Server and are ignored")
+	}
+
+	// Search for an existing PR
+	existingPr, err := client.findOpenPullRequest(projectKey, repositorySlug, updateGroup.BranchName, p.settings.BaseBranch)
+	if err != nil {
+		return err
+	}
+	if existingPr != nil {
+		p.logger.Info(fmt.Sprintf("PR already exists: %s", p.getPullRequestUrl(existingPr)))
+
+		// Update the PR if something changed
+		existingReviewers := lo.Map(existingPr.Reviewers, func(reviewer *bitbucketServerReviewer, _ int) string { return reviewer.User.Name })
+		newReviewers := lo.Without(updateGroup.Reviewers, existingReviewers...)
+		if existingPr.Title != updateGroup.Title || existingPr.Description != content || len(newReviewers) > 0 {
+			p.logger.Debug("Updating PR")
+			existingPr.Title = updateGroup.Title
+			existingPr.Description = content
+			existingPr.Reviewers = append(existingPr.Reviewers, convertBitbucketServerReviewers(newReviewers)...)
+			if _, err := client.updatePullRequest(projectKey, repositorySlug, existingPr); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Create the PR
+	pr, err := client.createPullRequest(projectKey, repositorySlug, &bitbucketServerPullRequest{
+		Title:       updateGroup.Title,
+		Description: content,
+		FromRef:     &bitbucketServerRef{Id: "refs/heads/" + updateGroup.BranchName},
+		ToRef:       &bitbucketServerRef{Id: "refs/heads/" + p.settings.BaseBranch},
+		Reviewers:   convertBitbucketServerReviewers(updateGroup.Reviewers),
+	})
+	if err != nil {
+		return err
+	}
+	p.logger.Info(fmt.Sprintf("Created PR: %s", p.getPullRequestUrl(pr)))
+	return nil
+}
+
+func (p *BitbucketServerPlatform) Cleanup(cleanupSettings *PlatformCleanupSettings) error {
+	// Prepare the data for the API
+	projectKey, repositorySlug := cleanupSettings.Project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	return p.cleanupBranches(cleanupSettings, func(branchName string) error {
+		// Search for an existing PR
+		existingPr, err := client.findOpenPullRequest(projectKey, repositorySlug, branchName, cleanupSettings.BaseBranch)
+		if err != nil || existingPr == nil {
+			return err
+		}
+		// Decline the PR (without the update markers as the updates are not declined by this)
+		p.logger.Info(fmt.Sprintf("Declining associated PR: %s", p.getPullRequestUrl(existingPr)))
+		existingPr.Description = removeUpdateMarkers(existingPr.Description)
+		updatedPr, err := client.updatePullRequest(projectKey, repositorySlug, existingPr)
+		if err != nil {
+			return err
+		}
+		return client.declinePullRequest(projectKey, repositorySlug, updatedPr)
+	})
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+func (p *BitbucketServerPlatform) createClient() (*bitbucketServerClient, error) {
+	if p.settings == nil || p.settings.Token == "" {
+		return nil, fmt.Errorf("no platform token defined")
+	}
+	if p.settings.Endpoint == "" {
+		return nil, fmt.Errorf("no platform endpoint defined")
+	}
+	return newBitbucketServerClient(p.settings.EndpointExpanded(), p.settings.TokenExpanded()), nil
+}
+
+// Returns the url of the PR in the browser.
+func (p *BitbucketServerPlatform) getPullRequestUrl(pullRequest *bitbucketServerPullRequest) string {
+	if pullRequest.Links != nil {
+		if selfUrl := firstBitbucketServerLink(pullRequest.Links.Self, ""); selfUrl != "" {
+			return selfUrl
+		}
+	}
+	return fmt.Sprintf("#%d", pullRequest.Id)
+}
+
+func convertBitbucketServerReviewers(reviewers []string) []*bitbucketServerReviewer {
+	return lo.Map(reviewers, func(reviewer string, _ int) *bitbucketServerReviewer {
+		return &bitbucketServerReviewer{User: &bitbucketServerUser{Name: reviewer}}
+	})
+}
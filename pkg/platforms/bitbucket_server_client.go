@@ -0,0 +1,263 @@
+package platforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/roemer/gonovate/pkg/common"
+)
+
+// A minimal client for the REST API of Bitbucket Server / Data Center.
+type bitbucketServerClient struct {
+	// The url of the server like "https://bitbucket.example.com".
+	baseUrl    string
+	token      string
+	httpClient *http.Client
+}
+
+type bitbucketServerLink struct {
+	Href string `json:"href"`
+	Name string `json:"name,omitempty"`
+}
+
+type bitbucketServerLinks struct {
+	Clone []*bitbucketServerLink `json:"clone,omitempty"`
+	Self  []*bitbucketServerLink `json:"self,omitempty"`
+}
+
+type bitbucketServerRepository struct {
+	Slug  string                `json:"slug"`
+	Links *bitbucketServerLinks `json:"links"`
+}
+
+type bitbucketServerUser struct {
+	Name         string `json:"name"`
+	Slug         string `json:"slug,omitempty"`
+	DisplayName  string `json:"displayName,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+type bitbucketServerRef struct {
+	Id        string `json:"id"`
+	DisplayId string `json:"displayId,omitempty"`
+}
+
+type bitbucketServerReviewer struct {
+	User *bitbucketServerUser `json:"user"`
+}
+
+type bitbucketServerPullRequest struct {
+	Id          int64                      `json:"id,omitempty"`
+	Version     int                        `json:"version"`
+	Title       string                     `json:"title"`
+	Description string                     `json:"description"`
+	State       string                     `json:"state,omitempty"`
+	FromRef     *bitbucketServerRef        `json:"fromRef,omitempty"`
+	ToRef       *bitbucketServerRef        `json:"toRef,omitempty"`
+	Reviewers   []*bitbucketServerReviewer `json:"reviewers"`
+	Links       *bitbucketServerLinks      `json:"links,omitempty"`
+}
+
+// A page of a paged response.
+type bitbucketServerPage[T any] struct {
+	Values        []T  `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// The error response of the REST API.
+type bitbucketServerErrors struct {
+	Errors []*struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func newBitbucketServerClient(endpoint string, token string) *bitbucketServerClient {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	endpoint = strings.TrimSuffix(endpoint, "/rest/api/1.0")
+	return &bitbucketServerClient{
+		baseUrl:    endpoint,
+		token:      token,
+		httpClient: &http.Client{},
+	}
+}
+
+// Gets the repository with the given project key and slug.
+func (c *bitbucketServerClient) getRepository(projectKey string, repositorySlug string) (*bitbucketServerRepository, error) {
+	repository := &bitbucketServerRepository{}
+	if _, err := c.do(http.MethodGet, repositoryApiPath(projectKey, repositorySlug), nil, nil, repository); err != nil {
+		return nil, err
+	}
+	return repository, nil
+}
+
+// Gets the name of the user of the token. The server returns it in a header of authenticated requests.
+func (c *bitbucketServerClient) getCurrentUsername() (string, error) {
+	resp, err := c.do(http.MethodGet, "/rest/api/1.0/application-properties", nil, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	username := resp.Header.Get("X-AUSERNAME")
+	if username == "" {
+		return "", fmt.Errorf("failed to lookup the current user. Is the token valid?")
+	}
+	return username, nil
+}
+
+// Gets the user with the given name. The name can differ from the slug which is used in the urls of users.
+func (c *bitbucketServerClient) getUserByName(username string) (*bitbucketServerUser, error) {
+	query := url.Values{}
+	query.Set("filter", username)
+	users, err := getAllBitbucketServerPages[*bitbucketServerUser](c, "/rest/api/1.0/users", query)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if strings.EqualFold(user.Name, username) {
+			return user, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to find the user '%s'", username)
+}
+
+// Searches for the open PR from the given branch into the given base branch. Returns nil if there is none.
+func (c *bitbucketServerClient) findOpenPullRequest(projectKey string, repositorySlug string, branchName string, baseBranch string) (*bitbucketServerPullRequest, error) {
+	query := url.Values{}
+	query.Set("state", "OPEN")
+	query.Set("direction", "OUTGOING")
+	query.Set("at", "refs/heads/"+branchName)
+	pullRequests, err := getAllBitbucketServerPages[*bitbucketServerPullRequest](c, repositoryApiPath(projectKey, repositorySlug)+"/pull-requests", query)
+	if err != nil {
+		return nil, err
+	}
+	for _, pullRequest := range pullRequests {
+		if pullRequest.FromRef != nil && pullRequest.FromRef.Id == "refs/heads/"+branchName &&
+			pullRequest.ToRef != nil && pullRequest.ToRef.Id == "refs/heads/"+baseBranch {
+			return pullRequest, nil
+		}
+	}
+	return nil, nil
+}
+
+// Creates a new PR.
+func (c *bitbucketServerClient) createPullRequest(projectKey string, repositorySlug string, pullRequest *bitbucketServerPullRequest) (*bitbucketServerPullRequest, error) {
+	createdPullRequest := &bitbucketServerPullRequest{}
+	if _, err := c.do(http.MethodPost, repositoryApiPath(projectKey, repositorySlug)+"/pull-requests", nil, pullRequest, createdPullRequest); err != nil {
+		return nil, err
+	}
+	return createdPullRequest, nil
+}
+
+// Updates the title, description and reviewers of a PR. The version must match the current version of the PR.
+func (c *bitbucketServerClient) updatePullRequest(projectKey string, repositorySlug string, pullRequest *bitbucketServerPullRequest) (*bitbucketServerPullRequest, error) {
+	updatedPullRequest := &bitbucketServerPullRequest{}
+	body := &bitbucketServerPullRequest{
+		Version:     pullRequest.Version,
+		Title:       pullRequest.Title,
+		Description: pullRequest.Description,
+		Reviewers:   pullRequest.Reviewers,
+	}
+	if _, err := c.do(http.MethodPut, fmt.Sprintf("%s/pull-requests/%d", repositoryApiPath(projectKey, repositorySlug), pullRequest.Id), nil, body, updatedPullRequest); err != nil {
+		return nil, err
+	}
+	return updatedPullRequest, nil
+}
+
+// Declines (closes) a PR.
+func (c *bitbucketServerClient) declinePullRequest(projectKey string, repositorySlug string, pullRequest *bitbucketServerPullRequest) error {
+	query := url.Values{}
+	query.Set("version", fmt.Sprint(pullRequest.Version))
+	_, err := c.do(http.MethodPost, fmt.Sprintf("%s/pull-requests/%d/decline", repositoryApiPath(projectKey, repositorySlug), pullRequest.Id), query, map[string]any{}, nil)
+	return err
+}
+
+// Executes a request against the REST API. The request and response bodies are JSON.
+func (c *bitbucketServerClient) do(method string, apiPath string, query url.Values, requestBody any, responseBody any) (*http.Response, error) {
+	requestUrl := c.baseUrl + apiPath
+	if len(query) > 0 {
+		requestUrl += "?" + query.Encode()
+	}
+	var bodyReader io.Reader
+	if requestBody != nil {
+		bodyBytes, err := json.Marshal(requestBody)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(bodyBytes)
+	}
+	req, err := http.NewRequest(method, requestUrl, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", common.ContentTypeJSON)
+	if requestBody != nil {
+		req.Header.Set("Content-Type", common.ContentTypeJSON)
+	}
+	common.HttpUtil.AddBearerToRequest(req, c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		apiErrors := &bitbucketServerErrors{}
+		messages := []string{}
+		if json.Unmarshal(respBody, apiErrors) == nil {
+			for _, apiError := range apiErrors.Errors {
+				messages = append(messages, apiError.Message)
+			}
+		}
+		return resp, fmt.Errorf("%s %s failed with status %d: %s", method, apiPath, resp.StatusCode, strings.Join(messages, ", "))
+	}
+	if responseBody != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, responseBody); err != nil {
+			return resp, fmt.Errorf("failed parsing the response of %s %s: %w", method, apiPath, err)
+		}
+	}
+	return resp, nil
+}
+
+// Gets all values of a paged resource.
+func getAllBitbucketServerPages[T any](c *bitbucketServerClient, apiPath string, query url.Values) ([]T, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("limit", "100")
+	values := []T{}
+	for {
+		page := &bitbucketServerPage[T]{}
+		if _, err := c.do(http.MethodGet, apiPath, query, nil, page); err != nil {
+			return nil, err
+		}
+		values = append(values, page.Values...)
+		if page.IsLastPage || len(page.Values) == 0 {
+			break
+		}
+		query.Set("start", fmt.Sprint(page.NextPageStart))
+	}
+	return values, nil
+}
+
+func repositoryApiPath(projectKey string, repositorySlug string) string {
+	return fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s", url.PathEscape(projectKey), url.PathEscape(repositorySlug))
+}
+
+// Returns the first link (with the given name if set) or an empty string if there is none.
+func firstBitbucketServerLink(links []*bitbucketServerLink, name string) string {
+	for _, link := range links {
+		if name == "" || link.Name == name {
+			return link.Href
+		}
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+package platforms
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// An in-process stand-in of the REST API of Bitbucket Server.
+type fakeBitbucketServer struct {
+	*httptest.Server
+	pullRequests []*bitbucketServerPullRequest
+	declined     []int64
+}
+
+func newFakeBitbucketServer(t *testing.T) *fakeBitbucketServer {
+	assert := assert.New(t)
+	server := &fakeBitbucketServer{}
+	repoPath := "/rest/api/1.0/projects/PRJ/repos/repo"
+	writeJson := func(w http.ResponseWriter, value any) {
+		w.Header().Set("Content-Type", common.ContentTypeJSON)
+		assert.NoError(json.NewEncoder(w).Encode(value))
+	}
+	findPullRequest := func(r *http.Request) *bitbucketServerPullRequest {
+		for _, pullRequest := range server.pullRequests {
+			if r.PathValue("id") == fmt.Sprint(pullRequest.Id) {
+				return pullRequest
+			}
+		}
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/api/1.0/application-properties", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("Bearer token", r.Header.Get("Authorization"))
+		w.Header().Set("X-AUSERNAME", "Gonovate@Example")
+		writeJson(w, map[string]string{"version": "9.4.0"})
+	})
+	mux.HandleFunc("GET /rest/api/1.0/users", func(w http.ResponseWriter, r *http.Request) {
+		// The filter also matches other users and the slug differs from the name
+		assert.Equal("Gonovate@Example", r.URL.Query().Get("filter"))
+		writeJson(w, &bitbucketServerPage[*bitbucketServerUser]{IsLastPage: true, Values: []*bitbucketServerUser{
+			{Name: "gonovate@example.org", Slug: "gonovate_example.org", DisplayName: "Someone Else"},
+			{Name: "gonovate@example", Slug: "gonovate_example", DisplayName: "Gonovate Bot", EmailAddress: "bot@gonovate.org"},
+		}})
+	})
+	mux.HandleFunc("GET "+repoPath+"/pull-requests", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("OPEN", r.URL.Query().Get("state"))
+		matches := []*bitbucketServerPullRequest{}
+		for _, pullRequest := range server.pullRequests {
+			if pullRequest.State == "OPEN" && pullRequest.FromRef.Id == r.URL.Query().Get("at") {
+				matches = append(matches, pullRequest)
+			}
+		}
+		writeJson(w, &bitbucketServerPage[*bitbucketServerPullRequest]{Values: matches, IsLastPage: true})
+	})
+	mux.HandleFunc("POST "+repoPath+"/pull-requests", func(w http.ResponseWriter, r *http.Request) {
+		pullRequest := &bitbucketServerPullRequest{}
+		assert.NoError(json.NewDecoder(r.Body).Decode(pullRequest))
+		pullRequest.Id = int64(len(server.pullRequests) + 1)
+		pullRequest.State = "OPEN"
+		server.pullRequests = append(server.pullRequests, pullRequest)
+		w.WriteHeader(http.StatusCreated)
+		writeJson(w, pullRequest)
+	})
+	mux.HandleFunc("PUT "+repoPath+"/pull-requests/{id}", func(w http.ResponseWriter, r *http.Request) {
+		pullRequest := findPullRequest(r)
+		update := &bitbucketServerPullRequest{}
+		assert.NoError(json.NewDecoder(r.Body).Decode(update))
+		if pullRequest == nil || update.Version != pullRequest.Version {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"errors":[{"message":"outdated version"}]}`))
+			return
+		}
+		pullRequest.Title = update.Title
+		pullRequest.Description = update.Description
+		pullRequest.Reviewers = update.Reviewers
+		pullRequest.Version++
+		writeJson(w, pullRequest)
+	})
+	mux.HandleFunc("POST "+repoPath+"/pull-requests/{id}/decline", func(w http.ResponseWriter, r *http.Request) {
+		pullRequest := findPullRequest(r)
+		assert.Equal(fmt.Sprint(pullRequest.Version), r.URL.Query().Get("version"))
+		pullRequest.State = "DECLINED"
+		server.declined = append(server.declined, pullRequest.Id)
+		writeJson(w, pullRequest)
+	})
+	server.Server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBitbucketServerNotifyChanges(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	server := newFakeBitbucketServer(t)
+	platform := NewBitbucketServerPlatform(&common.PlatformSettings{Logger: slog.Default(), Token: "token", Endpoint: server.URL + "/rest/api/1.0", BaseBranch: "main"})
+	project := &common.Project{Path: "PRJ/repo"}
+	updateGroup := &common.UpdateGroup{
+		Title:      "Update golang",
+		BranchName: "gonovate/golang",
+		Body:       "Updates golang",
+		Reviewers:  []string{"alice"},
+		Labels:     []string{"dependencies"},
+	}
+
+	// Creates the PR
+	require.NoError(platform.NotifyChanges(project, updateGroup))
+	require.Len(server.pullRequests, 1)
+	pullRequest := server.pullRequests[0]
+	assert.Equal("Update golang", pullRequest.Title)
+	assert.Equal("Updates golang", pullRequest.Description)
+	assert.Equal("refs/heads/gonovate/golang", pullRequest.FromRef.Id)
+	assert.Equal("refs/heads/main", pullRequest.ToRef.Id)
+	assert.Equal("alice", pullRequest.Reviewers[0].User.Name)
+
+	// Nothing changed
+	require.NoError(platform.NotifyChanges(project, updateGroup))
+	assert.Len(server.pullRequests, 1)
+	assert.Equal(0, pullRequest.Version)
+
+	// Updates the PR and keeps the existing reviewers
+	updateGroup.Title = "Update golang to 1.24"
+	updateGroup.Reviewers = []string{"bob"}
+	require.NoError(platform.NotifyChanges(project, updateGroup))
+	assert.Len(server.pullRequests, 1)
+	assert.Equal(1, pullRequest.Version)
+	assert.Equal("Update golang to 1.24", pullRequest.Title)
+	require.Len(pullRequest.Reviewers, 2)
+	assert.Equal("alice", pullRequest.Reviewers[0].User.Name)
+	assert.Equal("bob", pullRequest.Reviewers[1].User.Name)
+}
+
+func TestBitbucketServerLookupAuthor(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	server := newFakeBitbucketServer(t)
+	platform := NewBitbucketServerPlatform(&common.PlatformSettings{Logger: slog.Default(), Token: "token", Endpoint: server.URL})
+	name, email, err := platform.LookupAuthor()
+	require.NoError(err)
+	assert.Equal("Gonovate Bot", name)
+	assert.Equal("bot@gonovate.org", email)
+
+	// Without endpoint
+	platform = NewBitbucketServerPlatform(&common.PlatformSettings{Logger: slog.Default(), Token: "token"})
+	_, _, err = platform.LookupAuthor()
+	assert.ErrorContains(err, "no platform endpoint defined")
+}
+
+func TestBitbucketServerCleanup(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runGit, _ := prepareGitClone(t)
+	server := newFakeBitbucketServer(t)
+	platform := NewBitbucketServerPlatform(&common.PlatformSettings{Logger: slog.Default(), Token: "token", Endpoint: server.URL, BaseBranch: "main", GitAuthor: "gonovate-bot <bot@gonovate.org>"})
+	project := &common.Project{Path: "PRJ/repo"}
+
+	// Create a branch with a PR which is no longer needed and one which is still used
+	for _, branchName := range []string{"gonovate/stale", "gonovate/active"} {
+		updateGroup := &common.UpdateGroup{BranchName: branchName, Title: "Update", Body: "Body", Dependencies: []*common.DependencyWithUpdate{
+			{Dependency: &common.Dependency{Name: "golang", Version: "1.23.0", FilePath: "Dockerfile"}, NewRelease: &common.ReleaseInfo{VersionString: "1.24.1"}},
+		}}
+		runGit("checkout", "-B", branchName, "main")
+		require.NoError(os.WriteFile("file.txt", []byte("base\n"+branchName+"\n"), os.ModePerm))
+		require.NoError(platform.SubmitChanges(updateGroup))
+		require.NoError(platform.PublishChanges(updateGroup))
+		require.NoError(platform.NotifyChanges(project, updateGroup))
+	}
+	runGit("checkout", "main")
+
+	require.NoError(platform.Cleanup(&PlatformCleanupSettings{
+		Project:      project,
+		UpdateGroups: []*common.UpdateGroup{{BranchName: "gonovate/active"}},
+		BaseBranch:   "main",
+		BranchPrefix: "gonovate/",
+	}))
+
+	// The PR is declined without the update markers and the branch is deleted
+	assert.Equal([]int64{1}, server.declined)
+	assert.Equal("Body", server.pullRequests[0].Description)
+	assert.Equal("OPEN", server.pullRequests[1].State)
+	branches, err := platform.getRemoteGonovateBranches("origin", "gonovate/")
+	require.NoError(err)
+	assert.Equal([]string{"gonovate/active"}, branches)
+}
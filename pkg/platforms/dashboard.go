@@ -0,0 +1,208 @@
+package platforms
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/roemer/gonovate/pkg/common"
+)
+
+// Optional capability of platforms that can list PRs/MRs.
+type IPullRequestPlatform interface {
+	// Lists the PRs/MRs of the project whose source branch starts with the given prefix.
+	// If includeClosed is false, only open PRs/MRs are returned.
+	ListPullRequests(project *common.Project, branchPrefix string, includeClosed bool) ([]*PullRequestInfo, error)
+	// Adds a comment to the PR/MR with the given number unless it already has a comment containing the given marker.
+	AddPullRequestComment(project *common.Project, number int64, marker string, body string) error
+}
+
+// Optional capability of platforms that can maintain a dependency dashboard issue.
+type IDashboardPlatform interface {
+	// Returns the body of the dashboard issue or an empty string if there is no such issue.
+	GetDashboardBody(project *common.Project) (string, error)
+	// Creates or updates the dashboard issue with the given title and body. The issue is found by its marker in the body.
+	UpsertDashboard(project *common.Project, title string, body string) error
+}
+
+// The states a PR/MR can have.
+type PullRequestState string
+
+const (
+	PULL_REQUEST_STATE_OPEN   PullRequestState = "open"
+	PULL_REQUEST_STATE_CLOSED PullRequestState = "closed"
+	PULL_REQUEST_STATE_MERGED PullRequestState = "merged"
+)
+
+// Platform independent information about a PR/MR.
+type PullRequestInfo struct {
+	Number     int64
+	Title      string
+	Url        string
+	BranchName string
+	Body       string
+	State      PullRequestState
+	CreatedAt  time.Time
+}
+
+// A hidden marker which is added to the dashboard issue.
+const DashboardMarker = "<!-- gonovate-dependency-dashboard -->"
+
+// Checks if the body belongs to the dashboard issue. The issue is recognized by the marker so it is still found after the title was changed.
+func isDashboardBody(body string) bool {
+	return strings.Contains(body, DashboardMarker)
+}
+
+// Regex to find the checkboxes of the updates that need an approval.
+var approvalRegex = regexp.MustCompile(`(?m)^\s*[-*] \[([ xX])\] <!-- approve-branch=(\S+?) -->`)
+
+// The default title of the dashboard issue.
+const DefaultDashboardTitle = "Dependency Dashboard"
+
+// Holds all information that is shown on the dashboard.
+type DashboardData struct {
+	// All dependencies that were found.
+	Dependencies []*common.Dependency
+	// The open PRs/MRs of gonovate.
+	OpenPullRequests []*PullRequestInfo
+	// Errors that occurred when searching updates for dependencies.
+	LookupErrors []*DashboardLookupError
+	// Updates that need an approval before they are created.
+	Approvals []*DashboardApproval
+}
+
+// Holds information about an update that needs an approval.
+type DashboardApproval struct {
+	Title      string
+	BranchName string
+	Approved   bool
+	// The release notes of the updates which are linked on the dashboard.
+	ReleaseNotes []*common.ReleaseNotes
+}
+
+// Holds information about a failed update lookup of a dependency.
+type DashboardLookupError struct {
+	Dependency *common.Dependency
+	Error      error
+}
+
+// Builds the markdown body of the dashboard issue.
+func BuildDashboardBody(data *DashboardData) string {
+	sb := &strings.Builder{}
+	sb.WriteString(DashboardMarker + "\n")
+	sb.WriteString("This issue lists the dependencies that are tracked by gonovate and is updated on every run.\n")
+
+	// Open PRs/MRs
+	sb.WriteString("\n## Open Updates\n\n")
+	if len(data.OpenPullRequests) == 0 {
+		sb.WriteString("There are no open updates.\n")
+	}
+	for _, pr := range data.OpenPullRequests {
+		sb.WriteString(fmt.Sprintf("- [%s](%s) (`%s`)\n", pr.Title, pr.Url, pr.BranchName))
+	}
+
+	// Updates that need an approval
+	if len(data.Approvals) > 0 {
+		sb.WriteString("\n## Awaiting Approval\n\n")
+		sb.WriteString("Tick the checkbox of an update to create its branch and PR/MR on the next run.\n\n")
+		for _, approval := range data.Approvals {
+			checked := " "
+			if approval.Approved {
+				checked = "x"
+			}
+			sb.WriteString(fmt.Sprintf("- [%s] <!-- approve-branch=%s --> %s%s\n", checked, approval.BranchName, approval.Title, buildReleaseNotesLinks(approval.ReleaseNotes)))
+		}
+	}
+
+	// Lookup errors
+	if len(data.LookupErrors) > 0 {
+		sb.WriteString("\n## Lookup Errors\n\n")
+		for _, lookupError := range data.LookupErrors {
+			errorText := strings.ReplaceAll(lookupError.Error.Error(), "\n", " ")
+			sb.WriteString(fmt.Sprintf("- `%s` in `%s`: %s\n", lookupError.Dependency.Name, lookupError.Dependency.FilePath, errorText))
+		}
+	}
+
+	// Skipped dependencies
+	skippedDependencies := []*common.Dependency{}
+	for _, dependency := range data.Dependencies {
+		if dependency.Skip != nil && *dependency.Skip {
+			skippedDependencies = append(skippedDependencies, dependency)
+		}
+	}
+	if len(skippedDependencies) > 0 {
+		sb.WriteString("\n## Skipped Dependencies\n\n")
+		for _, dependency := range skippedDependencies {
+			reason := dependency.SkipReason
+			if reason == "" {
+				reason = "no reason given"
+			}
+			sb.WriteString(fmt.Sprintf("- `%s` in `%s`: %s\n", dependency.Name, dependency.FilePath, reason))
+		}
+	}
+
+	// All detected dependencies, grouped by file
+	sb.WriteString("\n## Detected Dependencies\n")
+	if len(data.Dependencies) == 0 {
+		sb.WriteString("\nNo dependencies were found.\n")
+	}
+	sortedDependencies := slices.SortedStableFunc(slices.Values(data.Dependencies), func(a, b *common.Dependency) int {
+		return cmp.Compare(a.FilePath, b.FilePath)
+	})
+	currentFile := ""
+	for i, dependency := range sortedDependencies {
+		if i == 0 || dependency.FilePath != currentFile {
+			currentFile = dependency.FilePath
+			sb.WriteString(fmt.Sprintf("\n### %s\n\n", currentFile))
+		}
+		version := dependency.Version
+		if dependency.HasDigest() {
+			version = fmt.Sprintf("%s@%s", version, dependency.Digest)
+		}
+		sb.WriteString(fmt.Sprintf("- `%s` `%s` (%s)\n", dependency.Name, version, dependency.Datasource))
+	}
+	return sb.String()
+}
+
+// Returns the branch names of the updates that are approved (ticked) in the given dashboard body.
+func ParseApprovedBranches(body string) []string {
+	approvedBranches := []string{}
+	for _, match := range approvalRegex.FindAllStringSubmatch(body, -1) {
+		if strings.EqualFold(match[1], "x") {
+			approvedBranches = append(approvedBranches, match[2])
+		}
+	}
+	return approvedBranches
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+// Builds the links to the release notes (once per url as changelog entries share the url of the file).
+func buildReleaseNotesLinks(releaseNotes []*common.ReleaseNotes) string {
+	links := []string{}
+	seenUrls := map[string]bool{}
+	for _, notes := range releaseNotes {
+		if notes.Url == "" || seenUrls[notes.Url] {
+			continue
+		}
+		seenUrls[notes.Url] = true
+		links = append(links, fmt.Sprintf("[%s](%s)", notes.Version, notes.Url))
+	}
+	if len(links) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (release notes: %s)", strings.Join(links, ", "))
+}
+
+// Returns true if the PR/MR matches the prefix and state filter.
+func pullRequestMatches(info *PullRequestInfo, branchPrefix string, includeClosed bool) bool {
+	if !strings.HasPrefix(info.BranchName, branchPrefix) {
+		return false
+	}
+	return includeClosed || info.State == PULL_REQUEST_STATE_OPEN
+}
@@ -0,0 +1,141 @@
+package platforms
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDashboardBody(t *testing.T) {
+	assert := assert.New(t)
+
+	dependencyA := &common.Dependency{Name: "golang", Version: "1.23.0", Datasource: common.DATASOURCE_TYPE_DOCKER, FilePath: "Dockerfile"}
+	dependencyB := &common.Dependency{Name: "alpine", Version: "3.20", Datasource: common.DATASOURCE_TYPE_DOCKER, FilePath: "Dockerfile", Skip: common.TruePtr, SkipReason: "Pinned"}
+	dependencyC := &common.Dependency{Name: "github.com/roemer/gover", Version: "v0.1.0", Datasource: common.DATASOURCE_TYPE_GOMOD, FilePath: "go.mod"}
+	body := BuildDashboardBody(&DashboardData{
+		Dependencies: []*common.Dependency{dependencyC, dependencyA, dependencyB},
+		OpenPullRequests: []*PullRequestInfo{
+			{Title: "Update 'golang' to '1.24.0'", Url: "https://example.com/pr/1", BranchName: "gonovate/main-golang-1.24.0"},
+		},
+		LookupErrors: []*DashboardLookupError{
+			{Dependency: dependencyC, Error: fmt.Errorf("not found\nat all")},
+		},
+	})
+
+	assert.True(strings.HasPrefix(body, DashboardMarker+"\n"))
+	assert.Contains(body, "## Open Updates\n\n- [Update 'golang' to '1.24.0'](https://example.com/pr/1) (`gonovate/main-golang-1.24.0`)\n")
+	assert.Contains(body, "## Lookup Errors\n\n- `github.com/roemer/gover` in `go.mod`: not found at all\n")
+	assert.Contains(body, "## Skipped Dependencies\n\n- `alpine` in `Dockerfile`: Pinned\n")
+	assert.Contains(body, "### Dockerfile\n\n- `golang` `1.23.0` (docker)\n- `alpine` `3.20` (docker)\n")
+	assert.Contains(body, "### go.mod\n\n- `github.com/roemer/gover` `v0.1.0` (go-mod)\n")
+	assert.Less(strings.Index(body, "### Dockerfile"), strings.Index(body, "### go.mod"))
+}
+
+func TestBuildDashboardBodyEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	body := BuildDashboardBody(&DashboardData{})
+	assert.Contains(body, "There are no open updates.\n")
+	assert.Contains(body, "No dependencies were found.\n")
+	assert.NotContains(body, "## Lookup Errors")
+	assert.NotContains(body, "## Skipped Dependencies")
+}
+
+func TestDashboardApprovals(t *testing.T) {
+	assert := assert.New(t)
+
+	body := BuildDashboardBody(&DashboardData{
+		Approvals: []*DashboardApproval{
+			{Title: "Update 'node' to '24.0.0'", BranchName: "gonovate/main-node-24.0.0"},
+			{Title: "Update 'java' to '25'", BranchName: "gonovate/main-java-25", Approved: true},
+			{Title: "Update 'tool' to '1.2.0'", BranchName: "gonovate/main-tool-1.2.0", ReleaseNotes: []*common.ReleaseNotes{
+				{Version: "1.2.0", Url: "https://example.com/CHANGELOG.md"},
+				{Version: "1.1.0", Url: "https://example.com/CHANGELOG.md"},
+				{Version: "1.0.1", Url: "https://example.com/releases/1.0.1"},
+			}},
+		},
+	})
+	assert.Contains(body, "## Awaiting Approval\n\n")
+	assert.Contains(body, "- [ ] <!-- approve-branch=gonovate/main-node-24.0.0 --> Update 'node' to '24.0.0'\n")
+	assert.Contains(body, "- [x] <!-- approve-branch=gonovate/main-java-25 --> Update 'java' to '25'\n")
+	assert.Contains(body, "- [ ] <!-- approve-branch=gonovate/main-tool-1.2.0 --> Update 'tool' to '1.2.0' (release notes: [1.2.0](https://example.com/CHANGELOG.md), [1.0.1](https://example.com/releases/1.0.1))\n")
+	assert.Equal([]string{"gonovate/main-java-25"}, ParseApprovedBranches(body))
+
+	// Ticked by a user
+	body = strings.Replace(body, "- [ ] <!-- approve-branch=gonovate/main-node-24.0.0", "- [X] <!-- approve-branch=gonovate/main-node-24.0.0", 1)
+	assert.Equal([]string{"gonovate/main-node-24.0.0", "gonovate/main-java-25"}, ParseApprovedBranches(body))
+
+	assert.Empty(ParseApprovedBranches(""))
+	assert.NotContains(BuildDashboardBody(&DashboardData{}), "## Awaiting Approval")
+}
+
+func TestPullRequestMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(pullRequestMatches(&PullRequestInfo{BranchName: "gonovate/a", State: PULL_REQUEST_STATE_OPEN}, "gonovate/", false))
+	assert.False(pullRequestMatches(&PullRequestInfo{BranchName: "feature/a", State: PULL_REQUEST_STATE_OPEN}, "gonovate/", false))
+	assert.False(pullRequestMatches(&PullRequestInfo{BranchName: "gonovate/a", State: PULL_REQUEST_STATE_MERGED}, "gonovate/", false))
+	assert.True(pullRequestMatches(&PullRequestInfo{BranchName: "gonovate/a", State: PULL_REQUEST_STATE_CLOSED}, "gonovate/", true))
+}
+
+func TestDashboardCapabilities(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, platform := range []any{&GitHubPlatform{}, &GitlabPlatform{}, &GiteaPlatform{}} {
+		assert.Implements((*IDashboardPlatform)(nil), platform)
+		assert.Implements((*IPullRequestPlatform)(nil), platform)
+		assert.Implements((*IAutomergePlatform)(nil), platform)
+		assert.Implements((*IModifiedBranchPlatform)(nil), platform)
+	}
+	for _, platform := range []any{&GitPlatform{}, &NoopPlatform{}} {
+		assert.NotImplements((*IDashboardPlatform)(nil), platform)
+		assert.NotImplements((*IAutomergePlatform)(nil), platform)
+		assert.NotImplements((*IModifiedBranchPlatform)(nil), platform)
+	}
+}
+
+func TestGiteaFindDashboardIssue(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	editedTitle := ""
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":"1.22.0"}`))
+	})
+	mux.HandleFunc("GET /api/v1/repos/owner/repo/issues", func(w http.ResponseWriter, r *http.Request) {
+		// The dashboard was renamed and is only on the second page
+		if r.URL.Query().Get("page") != "2" {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v1/repos/owner/repo/issues?page=2>; rel="next"`, r.Host))
+			w.Write([]byte(`[{"number":1,"title":"Dependency Dashboard","body":"Not the dashboard"}]`))
+			return
+		}
+		w.Write([]byte(`[{"number":2,"title":"Renamed","body":"Dashboard\n<!-- gonovate-dependency-dashboard -->"}]`))
+	})
+	mux.HandleFunc("PATCH /api/v1/repos/owner/repo/issues/2", func(w http.ResponseWriter, r *http.Request) {
+		issue := map[string]any{}
+		assert.NoError(json.NewDecoder(r.Body).Decode(&issue))
+		editedTitle = issue["title"].(string)
+		w.Write([]byte(`{"number":2}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	platform := NewGiteaPlatform(&common.PlatformSettings{Logger: slog.Default(), Token: "token", Endpoint: server.URL})
+	project := &common.Project{Path: "owner/repo"}
+	body, err := platform.GetDashboardBody(project)
+	require.NoError(err)
+	assert.Equal("Dashboard\n<!-- gonovate-dependency-dashboard -->", body)
+
+	// The title is restored
+	require.NoError(platform.UpsertDashboard(project, DefaultDashboardTitle, body))
+	assert.Equal(DefaultDashboardTitle, editedTitle)
+}
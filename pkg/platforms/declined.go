@@ -0,0 +1,87 @@
+package platforms
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/roemer/gonovate/pkg/common"
+)
+
+// Regex to find the hidden markers of the updates in the body of a PR/MR.
+var updateMarkerRegex = regexp.MustCompile(`<!-- gonovate-update name="([^"]*)" version="([^"]*)" major="(\d*)" -->`)
+
+// A hidden marker which is added to the comment on closed PRs/MRs.
+const DeclinedMarker = "<!-- gonovate-declined -->"
+
+// The comment which is added to closed PRs/MRs that prevent an update.
+const DeclinedComment = DeclinedMarker + "\nAs this PR/MR was closed without merging, gonovate will not propose these updates again (for major updates, all versions of this major are ignored).\n" +
+	"To undo this, edit the description of this PR/MR and remove the hidden `gonovate-update` markers."
+
+// Holds the updates of PRs/MRs that were closed without being merged.
+type DeclinedUpdates struct {
+	updates []*declinedUpdate
+}
+
+type declinedUpdate struct {
+	dependencyName string
+	version        string
+	major          int
+	pullRequest    *PullRequestInfo
+}
+
+// Builds the hidden marker for an update which is added to the body of the PR/MR.
+// For major updates, the major is added so all further versions of this major are declined as well.
+func BuildUpdateMarker(dependencyWithUpdate *common.DependencyWithUpdate) string {
+	major := ""
+	newRelease := dependencyWithUpdate.NewRelease
+	if newRelease.UpdateType == common.UPDATE_TYPE_MAJOR && newRelease.Version != nil {
+		major = strconv.Itoa(newRelease.Version.Major())
+	}
+	return fmt.Sprintf(`<!-- gonovate-update name="%s" version="%s" major="%s" -->`, dependencyWithUpdate.Dependency.Name, newRelease.VersionString, major)
+}
+
+// Collects the updates of all PRs/MRs which were closed without being merged.
+func ParseDeclinedUpdates(pullRequests []*PullRequestInfo) *DeclinedUpdates {
+	declinedUpdates := &DeclinedUpdates{}
+	for _, pullRequest := range pullRequests {
+		if pullRequest.State != PULL_REQUEST_STATE_CLOSED {
+			continue
+		}
+		for _, match := range updateMarkerRegex.FindAllStringSubmatch(pullRequest.Body, -1) {
+			update := &declinedUpdate{dependencyName: match[1], version: match[2], major: -1, pullRequest: pullRequest}
+			if match[3] != "" {
+				update.major, _ = strconv.Atoi(match[3])
+			}
+			declinedUpdates.updates = append(declinedUpdates.updates, update)
+		}
+	}
+	return declinedUpdates
+}
+
+// Returns the closed PR/MR which declined the given update or nil if the update was not declined.
+// A declined major only declines other major updates to it, not updates within the major once it is used.
+func (d *DeclinedUpdates) Find(dependencyName string, release *common.ReleaseInfo) *PullRequestInfo {
+	for _, update := range d.updates {
+		if update.dependencyName != dependencyName {
+			continue
+		}
+		if update.version == release.VersionString {
+			return update.pullRequest
+		}
+		if update.major >= 0 && release.UpdateType == common.UPDATE_TYPE_MAJOR && release.Version != nil && release.Version.Major() == update.major {
+			return update.pullRequest
+		}
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+// Removes the hidden markers of the updates from the body of a PR/MR.
+func removeUpdateMarkers(body string) string {
+	return strings.TrimSpace(updateMarkerRegex.ReplaceAllString(body, ""))
+}
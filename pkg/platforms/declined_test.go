@@ -0,0 +1,49 @@
+package platforms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/roemer/gover"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeclinedUpdates(t *testing.T) {
+	assert := assert.New(t)
+
+	newUpdate := func(name string, version string, updateType common.UpdateType) *common.DependencyWithUpdate {
+		return &common.DependencyWithUpdate{
+			Dependency: &common.Dependency{Name: name, Version: "1.0.0"},
+			NewRelease: &common.ReleaseInfo{VersionString: version, Version: gover.ParseSimple(strings.Split(version, ".")), UpdateType: updateType},
+		}
+	}
+	body := buildPullRequestBody(&common.UpdateGroup{Body: "- golang from 1.0.0 to 1.24.1\n- node from 1.0.0 to 24.0.0", Dependencies: []*common.DependencyWithUpdate{
+		newUpdate("golang", "1.24.1", common.UPDATE_TYPE_MINOR),
+		newUpdate("node", "24.0.0", common.UPDATE_TYPE_MAJOR),
+	}})
+	assert.Equal("- golang from 1.0.0 to 1.24.1\n- node from 1.0.0 to 24.0.0\n\n"+
+		`<!-- gonovate-update name="golang" version="1.24.1" major="" -->`+"\n"+
+		`<!-- gonovate-update name="node" version="24.0.0" major="24" -->`, body)
+	assert.Equal("- golang from 1.0.0 to 1.24.1\n- node from 1.0.0 to 24.0.0", removeUpdateMarkers(body))
+
+	closedPr := &PullRequestInfo{Number: 1, Body: body, State: PULL_REQUEST_STATE_CLOSED}
+	mergedPr := &PullRequestInfo{Number: 2, Body: buildPullRequestBody(&common.UpdateGroup{Dependencies: []*common.DependencyWithUpdate{
+		newUpdate("alpine", "3.22.1", common.UPDATE_TYPE_PATCH),
+	}}), State: PULL_REQUEST_STATE_MERGED}
+	declinedUpdates := ParseDeclinedUpdates([]*PullRequestInfo{closedPr, mergedPr})
+
+	// The same version
+	assert.Same(closedPr, declinedUpdates.Find("golang", newUpdate("golang", "1.24.1", common.UPDATE_TYPE_MINOR).NewRelease))
+	// Another version of a minor update
+	assert.Nil(declinedUpdates.Find("golang", newUpdate("golang", "1.24.2", common.UPDATE_TYPE_MINOR).NewRelease))
+	// Another version of the same major
+	assert.Same(closedPr, declinedUpdates.Find("node", newUpdate("node", "24.1.0", common.UPDATE_TYPE_MAJOR).NewRelease))
+	// Updates within the declined major once it is used (e.g. after a manual update)
+	assert.Nil(declinedUpdates.Find("node", newUpdate("node", "24.2.0", common.UPDATE_TYPE_MINOR).NewRelease))
+	assert.Nil(declinedUpdates.Find("node", newUpdate("node", "24.1.1", common.UPDATE_TYPE_PATCH).NewRelease))
+	// The next major
+	assert.Nil(declinedUpdates.Find("node", newUpdate("node", "25.0.0", common.UPDATE_TYPE_MAJOR).NewRelease))
+	// Merged PRs do not decline anything
+	assert.Nil(declinedUpdates.Find("alpine", newUpdate("alpine", "3.22.1", common.UPDATE_TYPE_PATCH).NewRelease))
+}
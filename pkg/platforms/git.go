@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os/exec"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/roemer/gonovate/pkg/common"
@@ -12,9 +14,14 @@ import (
 )
 
 var authorRegex = regexp.MustCompile(`^(?P<name>[^<>]+)(?:\s+<(?P<email>.*?)>)?$`)
+var gitVersionRegex = regexp.MustCompile(`^git version (\d+)\.(\d+)`)
 
 type GitPlatform struct {
 	*platformBase
+	// The author of the commits, looked up once per run.
+	authorName   string
+	authorEmail  string
+	authorLoaded bool
 }
 
 func NewGitPlatform(settings *common.PlatformSettings) *GitPlatform {
@@ -49,52 +56,93 @@ func (p *GitPlatform) SubmitChanges(updateGroup *common.UpdateGroup) error {
 		return err
 	}
 
-	// Prepare the slice for the arguments
-	args := []string{}
-
-	// Optionally set the committer if it is set
-	if p.settings != nil && p.settings.GitAuthor != "" {
-		name, email := splitAuthor(p.settings.GitAuthor)
-		args = append(args, "-c", "user.name="+name)
-		args = append(args, "-c", "user.email="+email)
-	} else {
-		// Or look it up from the platforms default
-		name, email, err := p.impl.LookupAuthor()
-		if err != nil {
-			return err
-		}
-		args = append(args, "-c", "user.name="+name)
-		args = append(args, "-c", "user.email="+email)
+	// Set the committer
+	name, email, err := p.getAuthor()
+	if err != nil {
+		return err
 	}
+	args := []string{"-c", "user.name=" + name, "-c", "user.email=" + email}
 
 	// Build the commit arguments
 	args = append(args, "commit", "--message="+updateGroup.Title)
+	if body := buildCommitBody(updateGroup); body != "" {
+		args = append(args, "--message="+body)
+	}
 
 	// Execute the command
-	_, _, err := common.Git.Run(args...)
+	_, _, err = common.Git.Run(args...)
 	return err
 }
 
 func (p *GitPlatform) IsNewOrChanged(updateGroup *common.UpdateGroup) (bool, error) {
-	_, _, err := common.Git.Run("ls-remote", "--exit-code", "origin", updateGroup.BranchName)
+	remoteName := p.getRemoteName()
+	_, _, err := common.Git.Run("ls-remote", "--exit-code", remoteName, updateGroup.BranchName)
 	if err != nil {
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+		if gitExitCode(err) == 2 {
 			// The branch does not exist
 			return true, nil
 		}
 		// There was an error
 		return false, err
 	}
-	// The branch exists, get the diff
-	stdOut, _, err := common.Git.Run("diff", fmt.Sprintf("origin/%s", updateGroup.BranchName), updateGroup.BranchName, "--name-status")
+	// The branch exists, make sure the remote branches are up to date
+	if _, _, err := common.Git.Run("fetch", remoteName, updateGroup.BranchName, p.settings.BaseBranch); err != nil {
+		return false, err
+	}
+	remoteBranch := fmt.Sprintf("%s/%s", remoteName, updateGroup.BranchName)
+	remoteBaseBranch := fmt.Sprintf("%s/%s", remoteName, p.settings.BaseBranch)
+
+	// Compare the changes of the update itself, the branch always needs to be published if they differ
+	remoteChanges, err := p.getBranchChanges(remoteBaseBranch, remoteBranch)
 	if err != nil {
 		return false, err
 	}
-	if len(stdOut) > 0 {
+	localChanges, err := p.getBranchChanges(p.settings.BaseBranch, updateGroup.BranchName)
+	if err != nil {
+		return false, err
+	}
+	if remoteChanges != localChanges {
 		return true, nil
 	}
-	return false, err
+
+	// The changes are the same, check if the branch needs to be rebuilt on the base branch
+	switch p.settings.RebaseWhen {
+	case common.REBASE_WHEN_NEVER:
+		return false, nil
+	case common.REBASE_WHEN_CONFLICTED:
+		isConflicted, err := p.isConflicted(remoteBaseBranch, remoteBranch)
+		if err != nil {
+			return false, err
+		}
+		if isConflicted {
+			p.logger.Info("Branch on remote conflicts with the base branch, rebasing")
+		}
+		return isConflicted, nil
+	default:
+		isBehind, err := p.isBehind(remoteBaseBranch, remoteBranch)
+		if err != nil {
+			return false, err
+		}
+		if isBehind {
+			p.logger.Info("Branch on remote is behind the base branch, rebasing")
+		}
+		return isBehind, nil
+	}
+}
+
+func (p *GitPlatform) IsModified(updateGroup *common.UpdateGroup) (bool, error) {
+	_, _, err := common.Git.Run("ls-remote", "--exit-code", p.getRemoteName(), updateGroup.BranchName)
+	if err != nil {
+		if gitExitCode(err) == 2 {
+			// The branch does not exist
+			return false, nil
+		}
+		return false, err
+	}
+	if err := p.fetchBranches(p.settings.BaseBranch, []string{updateGroup.BranchName}); err != nil {
+		return false, err
+	}
+	return p.isBranchModified(updateGroup.BranchName, p.settings.BaseBranch)
 }
 
 func (p *GitPlatform) PublishChanges(updateGroup *common.UpdateGroup) error {
@@ -152,6 +200,187 @@ func (p *GitPlatform) getRemoteGonovateBranches(remoteName string, branchPrefix
 	return gonovateBranches, nil
 }
 
+// Deletes the remote branches of gonovate which were not used in this run. Branches which were modified by someone else are kept.
+// The given function closes the associated PR/MR of a branch before the branch is deleted.
+func (p *GitPlatform) cleanupBranches(cleanupSettings *PlatformCleanupSettings, closePullRequest func(branchName string) error) error {
+	remoteName := p.getRemoteName()
+
+	// Get the remote branches for gonovate
+	gonovateBranches, err := p.getRemoteGonovateBranches(remoteName, cleanupSettings.BranchPrefix)
+	if err != nil {
+		return err
+	}
+
+	// Get the branches that were used in this gonovate run
+	usedBranches := lo.FlatMap(cleanupSettings.UpdateGroups, func(x *common.UpdateGroup, _ int) []string {
+		return []string{x.BranchName}
+	})
+
+	// Fetch the unused branches once to check if they were modified
+	if err := p.fetchBranches(cleanupSettings.BaseBranch, lo.Without(gonovateBranches, usedBranches...)); err != nil {
+		return err
+	}
+
+	// Loop thru the branches and check if they are active or not
+	activeBranchCount := 0
+	obsoleteBranchCount := 0
+	for _, potentialStaleBranch := range gonovateBranches {
+		if slices.Contains(usedBranches, potentialStaleBranch) {
+			// This branch is used
+			activeBranchCount++
+			continue
+		}
+		// Branches which were modified by someone else are kept
+		if isModified, err := p.isBranchModified(potentialStaleBranch, cleanupSettings.BaseBranch); err != nil {
+			return err
+		} else if isModified {
+			p.logger.Info(fmt.Sprintf("Keeping unused branch '%s' as it was modified by someone else", potentialStaleBranch))
+			activeBranchCount++
+			continue
+		}
+		// This branch is unused, delete the branch and a possible associated PR/MR
+		p.logger.Info(fmt.Sprintf("Removing unused branch '%s'", potentialStaleBranch))
+		if err := closePullRequest(potentialStaleBranch); err != nil {
+			return err
+		}
+
+		// Delete the unused branch
+		p.logger.Debug("Deleting the branch")
+		if _, _, err := common.Git.Run("push", remoteName, "--delete", potentialStaleBranch); err != nil {
+			return fmt.Errorf("failed to delete the remote branch '%s'", potentialStaleBranch)
+		}
+		obsoleteBranchCount++
+	}
+
+	p.logger.Info(fmt.Sprintf("Finished cleaning branches. Active: %d, Deleted: %d", activeBranchCount, obsoleteBranchCount))
+
+	return nil
+}
+
+// Returns the author to use for commits. This is either the configured one or the default of the platform.
+// The default of the platform is only looked up once.
+func (p *GitPlatform) getAuthor() (string, string, error) {
+	if p.settings != nil && p.settings.GitAuthor != "" {
+		name, email := splitAuthor(p.settings.GitAuthor)
+		return name, email, nil
+	}
+	if !p.authorLoaded {
+		name, email, err := p.impl.LookupAuthor()
+		if err != nil {
+			return "", "", err
+		}
+		p.authorName, p.authorEmail, p.authorLoaded = name, email, true
+	}
+	return p.authorName, p.authorEmail, nil
+}
+
+// Fetches the given remote branches together with the base branch. Does nothing if there are no branches.
+func (p *GitPlatform) fetchBranches(baseBranch string, branches []string) error {
+	if len(branches) == 0 {
+		return nil
+	}
+	args := append([]string{"fetch", p.getRemoteName(), baseBranch}, branches...)
+	_, _, err := common.Git.Run(args...)
+	return err
+}
+
+// Checks if the remote branch contains commits on top of the base branch that were not authored by gonovate.
+// The branches need to be fetched before.
+func (p *GitPlatform) isBranchModified(branch string, baseBranch string) (bool, error) {
+	remoteName := p.getRemoteName()
+	stdout, _, err := common.Git.Run("log", "--format=%an%x00%ae", fmt.Sprintf("%s/%s..%s/%s", remoteName, baseBranch, remoteName, branch))
+	if err != nil {
+		return false, err
+	}
+	name, email, err := p.getAuthor()
+	if err != nil {
+		return false, err
+	}
+	for line := range strings.Lines(stdout) {
+		commitName, commitEmail, _ := strings.Cut(strings.TrimSpace(line), "\x00")
+		// Prefer the email to identify the author as the name is often changed by platforms
+		if email != "" && !strings.EqualFold(commitEmail, email) || email == "" && commitName != name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Returns the changes the branch introduces compared to the base branch.
+// Line numbers and context lines are removed so the changes can be compared with branches with another base.
+func (p *GitPlatform) getBranchChanges(baseBranch string, branch string) (string, error) {
+	stdout, _, err := common.Git.Run("diff", "--unified=0", "--no-color", fmt.Sprintf("%s...%s", baseBranch, branch))
+	if err != nil {
+		return "", err
+	}
+	lines := lo.Filter(strings.Split(stdout, "\n"), func(line string, _ int) bool {
+		return !strings.HasPrefix(line, "@@") && !strings.HasPrefix(line, "index ")
+	})
+	return strings.Join(lines, "\n"), nil
+}
+
+// Checks if the branch does not contain the latest commit of the base branch.
+func (p *GitPlatform) isBehind(baseBranch string, branch string) (bool, error) {
+	_, _, err := common.Git.Run("merge-base", "--is-ancestor", baseBranch, branch)
+	if err != nil {
+		if gitExitCode(err) == 1 {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, nil
+}
+
+// Checks if merging the branch into the base branch would lead to conflicts.
+func (p *GitPlatform) isConflicted(baseBranch string, branch string) (bool, error) {
+	// Checking for conflicts without a worktree needs "git merge-tree --write-tree"
+	stdout, _, err := common.Git.Run("version")
+	if err != nil {
+		return false, err
+	}
+	if !gitVersionAtLeast(stdout, 2, 38) {
+		return false, fmt.Errorf("rebaseWhen '%s' needs git 2.38 or newer, found '%s'", common.REBASE_WHEN_CONFLICTED, strings.TrimSpace(stdout))
+	}
+	_, _, err = common.Git.Run("merge-tree", "--write-tree", baseBranch, branch)
+	if err != nil {
+		if gitExitCode(err) == 1 {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, nil
+}
+
+// Checks if the output of "git version" is at least the given version.
+func gitVersionAtLeast(versionOutput string, major int, minor int) bool {
+	match := gitVersionRegex.FindStringSubmatch(strings.TrimSpace(versionOutput))
+	if match == nil {
+		return false
+	}
+	foundMajor, _ := strconv.Atoi(match[1])
+	foundMinor, _ := strconv.Atoi(match[2])
+	return foundMajor > major || (foundMajor == major && foundMinor >= minor)
+}
+
+// Returns the exit code of a failed git command or -1 if the command could not be run at all.
+func gitExitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// Builds the body of the commit which lists all updated dependencies of the group.
+func buildCommitBody(updateGroup *common.UpdateGroup) string {
+	lines := []string{}
+	for _, dependencyWithUpdate := range updateGroup.Dependencies {
+		dependency := dependencyWithUpdate.Dependency
+		lines = append(lines, fmt.Sprintf("- Update %s from %s to %s (%s)", dependency.Name, dependency.Version, dependencyWithUpdate.NewRelease.VersionString, dependency.FilePath))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func splitAuthor(author string) (string, string) {
 	matchMap := common.FindNamedMatchesWithIndex(authorRegex, author, true)
 	return matchMap["name"][0].Value, matchMap["email"][0].Value
@@ -1,9 +1,15 @@
 package platforms
 
 import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/roemer/gonovate/pkg/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseAuthor(t *testing.T) {
@@ -17,3 +23,176 @@ func TestParseAuthor(t *testing.T) {
 	assert.Equal("gonovate-bot", name)
 	assert.Equal("", email)
 }
+
+func TestGitVersionAtLeast(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(gitVersionAtLeast("git version 2.38.0\n", 2, 38))
+	assert.True(gitVersionAtLeast("git version 2.45.2.windows.1", 2, 38))
+	assert.True(gitVersionAtLeast("git version 3.0.0", 2, 38))
+	assert.False(gitVersionAtLeast("git version 2.37.7 (Apple Git-140)", 2, 38))
+	assert.False(gitVersionAtLeast("git version 1.99.0", 2, 38))
+	assert.False(gitVersionAtLeast("unknown", 2, 38))
+}
+
+func TestIsNewOrChanged(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runGit, commitFile := prepareGitClone(t)
+
+	updateGroup := &common.UpdateGroup{BranchName: "gonovate/update"}
+	platform := NewGitPlatform(&common.PlatformSettings{Logger: slog.Default(), BaseBranch: "main"})
+	buildBranch := func() {
+		runGit("checkout", "-B", updateGroup.BranchName, "main")
+		content, err := os.ReadFile("file.txt")
+		require.NoError(err)
+		commitFile(strings.Replace(string(content), "version: 1", "version: 2", 1))
+	}
+	isNewOrChanged := func(rebaseWhen common.RebaseWhen) bool {
+		platform.settings.RebaseWhen = rebaseWhen
+		result, err := platform.IsNewOrChanged(updateGroup)
+		require.NoError(err)
+		return result
+	}
+
+	// The branch does not exist yet
+	buildBranch()
+	assert.True(isNewOrChanged(common.REBASE_WHEN_BEHIND_BASE))
+	runGit("push", "--force", "origin", updateGroup.BranchName)
+
+	// The same changes on the same base
+	buildBranch()
+	assert.False(isNewOrChanged(common.REBASE_WHEN_BEHIND_BASE))
+
+	// Other changes
+	runGit("checkout", "-B", updateGroup.BranchName, "main")
+	commitFile("base\nversion: 3\n")
+	assert.True(isNewOrChanged(common.REBASE_WHEN_NEVER))
+
+	// The base branch moved with a change in the line next to the update which conflicts
+	runGit("checkout", "main")
+	commitFile("moved\nversion: 1\n")
+	runGit("push", "origin", "main")
+	buildBranch()
+	assert.True(isNewOrChanged(common.REBASE_WHEN_BEHIND_BASE))
+	assert.True(isNewOrChanged(common.REBASE_WHEN_CONFLICTED))
+	assert.False(isNewOrChanged(common.REBASE_WHEN_NEVER))
+
+	// The base branch moved with a change that does not conflict
+	runGit("push", "--force", "origin", updateGroup.BranchName)
+	runGit("checkout", "main")
+	require.NoError(os.WriteFile("other.txt", []byte("other"), os.ModePerm))
+	runGit("add", "--all")
+	runGit("-c", "user.name=test", "-c", "user.email=test@gonovate.org", "commit", "--message=other")
+	runGit("push", "origin", "main")
+	buildBranch()
+	assert.True(isNewOrChanged(common.REBASE_WHEN_BEHIND_BASE))
+	assert.False(isNewOrChanged(common.REBASE_WHEN_CONFLICTED))
+	assert.False(isNewOrChanged(common.REBASE_WHEN_NEVER))
+}
+
+func TestIsModified(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runGit, commitFile := prepareGitClone(t)
+	updateGroup := &common.UpdateGroup{BranchName: "gonovate/update", Title: "Update"}
+	platform := NewGitPlatform(&common.PlatformSettings{Logger: slog.Default(), BaseBranch: "main", GitAuthor: "gonovate-bot <bot@gonovate.org>"})
+
+	// The branch does not exist yet
+	isModified, err := platform.IsModified(updateGroup)
+	require.NoError(err)
+	assert.False(isModified)
+
+	// The branch only contains commits from gonovate
+	runGit("checkout", "-B", updateGroup.BranchName, "main")
+	require.NoError(os.WriteFile("file.txt", []byte("base\nversion: 2\n"), os.ModePerm))
+	require.NoError(platform.SubmitChanges(updateGroup))
+	require.NoError(platform.PublishChanges(updateGroup))
+	isModified, err = platform.IsModified(updateGroup)
+	require.NoError(err)
+	assert.False(isModified)
+
+	// Someone else added a commit
+	commitFile("base\nversion: 2\nfix\n")
+	runGit("push", "origin", updateGroup.BranchName)
+	isModified, err = platform.IsModified(updateGroup)
+	require.NoError(err)
+	assert.True(isModified)
+}
+
+// A git platform which counts the lookups of the author.
+type authorCountingPlatform struct {
+	*GitPlatform
+	lookups int
+}
+
+func (p *authorCountingPlatform) LookupAuthor() (string, string, error) {
+	p.lookups++
+	return "gonovate-bot", "bot@gonovate.org", nil
+}
+
+func TestGetAuthorIsCached(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	platform := &authorCountingPlatform{GitPlatform: NewGitPlatform(&common.PlatformSettings{Logger: slog.Default()})}
+	platform.impl = platform
+	for range 3 {
+		name, email, err := platform.getAuthor()
+		require.NoError(err)
+		assert.Equal("gonovate-bot", name)
+		assert.Equal("bot@gonovate.org", email)
+	}
+	assert.Equal(1, platform.lookups)
+}
+
+func TestSubmitChanges(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	prepareGitClone(t)
+	updateGroup := &common.UpdateGroup{
+		BranchName: "gonovate/update",
+		Title:      "chore(deps): update group 'all'",
+		Dependencies: []*common.DependencyWithUpdate{
+			{Dependency: &common.Dependency{Name: "golang", Version: "1.23.0", FilePath: "Dockerfile"}, NewRelease: &common.ReleaseInfo{VersionString: "1.24.1"}},
+			{Dependency: &common.Dependency{Name: "alpine", Version: "3.21.0", FilePath: "Dockerfile"}, NewRelease: &common.ReleaseInfo{VersionString: "3.22.1"}},
+		},
+	}
+	platform := NewGitPlatform(&common.PlatformSettings{Logger: slog.Default(), BaseBranch: "main", GitAuthor: "gonovate-bot <bot@gonovate.org>"})
+	require.NoError(os.WriteFile("file.txt", []byte("base\nversion: 2\n"), os.ModePerm))
+	require.NoError(platform.SubmitChanges(updateGroup))
+
+	subject, _, err := common.Git.Run("log", "-1", "--format=%s")
+	require.NoError(err)
+	assert.Equal("chore(deps): update group 'all'", strings.TrimSpace(subject))
+	body, _, err := common.Git.Run("log", "-1", "--format=%b")
+	require.NoError(err)
+	assert.Equal("- Update golang from 1.23.0 to 1.24.1 (Dockerfile)\n- Update alpine from 3.21.0 to 3.22.1 (Dockerfile)", strings.TrimSpace(body))
+}
+
+// Creates a remote and a clone of it with an initial commit on "main" and changes into the clone.
+// Returns helpers to run git and to commit a changed file as a test user.
+func prepareGitClone(t *testing.T) (func(args ...string), func(content string)) {
+	require := require.New(t)
+
+	tempDir := t.TempDir()
+	runGit := func(args ...string) {
+		_, _, err := common.Git.Run(args...)
+		require.NoError(err)
+	}
+	commitFile := func(content string) {
+		require.NoError(os.WriteFile("file.txt", []byte(content), os.ModePerm))
+		runGit("-c", "user.name=test", "-c", "user.email=test@gonovate.org", "commit", "--all", "--message=change")
+	}
+	runGit("init", "--bare", "--initial-branch=main", filepath.Join(tempDir, "remote.git"))
+	runGit("clone", filepath.Join(tempDir, "remote.git"), filepath.Join(tempDir, "clone"))
+	t.Chdir(filepath.Join(tempDir, "clone"))
+	require.NoError(os.WriteFile("file.txt", []byte("base\nversion: 1\n"), os.ModePerm))
+	runGit("add", "--all")
+	commitFile("base\nversion: 1\n")
+	runGit("push", "origin", "main")
+	return runGit, commitFile
+}
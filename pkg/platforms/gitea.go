@@ -2,6 +2,7 @@ package platforms
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"slices"
 	"strings"
@@ -78,29 +79,20 @@ func (p *GiteaPlatform) NotifyChanges(project *common.Project, updateGroup *comm
 	}
 
 	// Build the content of the PR
-	content := ""
-	for _, dep := range updateGroup.Dependencies {
-		content += fmt.Sprintf("- %s from %s to %s\n", dep.Dependency.Name, dep.Dependency.Version, dep.NewRelease.VersionString)
-	}
-	// Trim spaces / newlines
-	content = strings.TrimSpace(content)
+	content := buildPullRequestBody(updateGroup)
 
 	// Search for an existing PR
-	pullRequests, _, err := client.ListRepoPullRequests(owner, repository, gitea.ListPullRequestsOptions{
-		State: gitea.StateOpen,
-	})
+	existingPr, err := p.findOpenPullRequest(client, owner, repository, updateGroup.BranchName)
 	if err != nil {
 		return err
 	}
-	existingPr, prExists := lo.Find(pullRequests, func(pr *gitea.PullRequest) bool {
-		return pr.Head.Ref == updateGroup.BranchName && pr.Base.Ref == p.settings.BaseBranch
-	})
 	// Convert the labels
 	newLabels, err := p.convertLabels(client, owner, repository, updateGroup.Labels)
 	if err != nil {
 		return err
 	}
-	if prExists {
+	pullRequest := existingPr
+	if existingPr != nil {
 		p.logger.Info(fmt.Sprintf("PR already exists: %s", existingPr.HTMLURL))
 
 		// Update the PR if something changed
@@ -139,24 +131,20 @@ func (p *GiteaPlatform) NotifyChanges(project *common.Project, updateGroup *comm
 			return err
 		}
 		p.logger.Info(fmt.Sprintf("Created PR: %s", pr.HTMLURL))
+		pullRequest = pr
+	}
+
+	// Schedule the merge when all checks succeeded
+	if updateGroup.Automerge && updateGroup.PlatformAutomerge {
+		p.logger.Info("Scheduling the merge of the PR")
+		if err := p.mergePullRequest(client, owner, repository, pullRequest, updateGroup.GetAutomergeStrategy(), true); err != nil {
+			return fmt.Errorf("failed scheduling the merge: %w", err)
+		}
 	}
 	return nil
 }
 
 func (p *GiteaPlatform) Cleanup(cleanupSettings *PlatformCleanupSettings) error {
-	remoteName := p.getRemoteName()
-
-	// Get the remote branches for gonovate
-	gonovateBranches, err := p.getRemoteGonovateBranches(remoteName, cleanupSettings.BranchPrefix)
-	if err != nil {
-		return err
-	}
-
-	// Get the branches that were used in this gonovate run
-	usedBranches := lo.FlatMap(cleanupSettings.UpdateGroups, func(x *common.UpdateGroup, _ int) []string {
-		return []string{x.BranchName}
-	})
-
 	// Prepare the data for the API
 	owner, repository := cleanupSettings.Project.SplitPath()
 
@@ -166,18 +154,7 @@ func (p *GiteaPlatform) Cleanup(cleanupSettings *PlatformCleanupSettings) error
 		return err
 	}
 
-	// Loop thru the branches and check if they are active or not
-	activeBranchCount := 0
-	obsoleteBranchCount := 0
-	for _, potentialStaleBranch := range gonovateBranches {
-		if slices.Contains(usedBranches, potentialStaleBranch) {
-			// This branch is used
-			activeBranchCount++
-			continue
-		}
-		// This branch is unused, delete the branch and a possible associated PR
-		p.logger.Info(fmt.Sprintf("Removing unused branch '%s'", potentialStaleBranch))
-
+	return p.cleanupBranches(cleanupSettings, func(branchName string) error {
 		// Search for an existing PR
 		existingRequests, _, err := client.ListRepoPullRequests(owner, repository, gitea.ListPullRequestsOptions{
 			State: gitea.StateOpen,
@@ -186,31 +163,275 @@ func (p *GiteaPlatform) Cleanup(cleanupSettings *PlatformCleanupSettings) error
 			return err
 		}
 		existingPr, prExists := lo.Find(existingRequests, func(pr *gitea.PullRequest) bool {
-			return pr.Head.Ref == potentialStaleBranch && pr.Base.Ref == cleanupSettings.BaseBranch
+			return pr.Head.Ref == branchName && pr.Base.Ref == cleanupSettings.BaseBranch
 		})
 		if prExists {
-			// Close the PR
+			// Close the PR (without the update markers as the updates are not declined by this)
 			p.logger.Info(fmt.Sprintf("Closing associated PR: %s", existingPr.HTMLURL))
 			if _, _, err := client.EditPullRequest(owner, repository, existingPr.Index, gitea.EditPullRequestOption{
 				State: &[]gitea.StateType{gitea.StateClosed}[0],
+				Body:  gitea.OptionalString(removeUpdateMarkers(existingPr.Body)),
 			}); err != nil {
 				return err
 			}
 		}
+		return nil
+	})
+}
+
+func (p *GiteaPlatform) ListPullRequests(project *common.Project, branchPrefix string, includeClosed bool) ([]*PullRequestInfo, error) {
+	// Prepare the data for the API
+	owner, repository := project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all PRs (paginated)
+	options := gitea.ListPullRequestsOptions{
+		State:       lo.Ternary(includeClosed, gitea.StateAll, gitea.StateOpen),
+		ListOptions: gitea.ListOptions{Page: 1, PageSize: 50},
+	}
+	pullRequests := []*PullRequestInfo{}
+	for {
+		prs, resp, err := client.ListRepoPullRequests(owner, repository, options)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range prs {
+			info := &PullRequestInfo{
+				Number: pr.Index,
+				Title:  pr.Title,
+				Url:    pr.HTMLURL,
+				Body:   pr.Body,
+				State:  PULL_REQUEST_STATE_OPEN,
+			}
+			if pr.Head != nil {
+				info.BranchName = pr.Head.Ref
+			}
+			if pr.Created != nil {
+				info.CreatedAt = *pr.Created
+			}
+			if pr.State == gitea.StateClosed {
+				info.State = lo.Ternary(pr.HasMerged, PULL_REQUEST_STATE_MERGED, PULL_REQUEST_STATE_CLOSED)
+			}
+			if pullRequestMatches(info, branchPrefix, includeClosed) {
+				pullRequests = append(pullRequests, info)
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		options.Page = resp.NextPage
+	}
+	return pullRequests, nil
+}
+
+func (p *GiteaPlatform) MergeIfReady(project *common.Project, updateGroup *common.UpdateGroup) (bool, error) {
+	// Prepare the data for the API
+	owner, repository := project.SplitPath()
 
-		// Delete the unused branch
-		p.logger.Debug("Deleting the branch")
-		if _, _, err := common.Git.Run("push", remoteName, "--delete", potentialStaleBranch); err != nil {
-			return fmt.Errorf("failed to delete the remote branch '%s'", potentialStaleBranch)
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return false, err
+	}
+
+	// Search for the PR
+	pullRequest, err := p.findOpenPullRequest(client, owner, repository, updateGroup.BranchName)
+	if err != nil || pullRequest == nil {
+		return false, err
+	}
+	if !pullRequest.Mergeable {
+		p.logger.Info("PR is not ready to be merged (not mergeable)")
+		return false, nil
+	}
+	// Only merge if all checks succeeded (or there are none)
+	status, _, err := client.GetCombinedStatus(owner, repository, pullRequest.Head.Sha)
+	if err != nil {
+		return false, err
+	}
+	if status.TotalCount > 0 && status.State != gitea.StatusSuccess {
+		p.logger.Info(fmt.Sprintf("PR is not ready to be merged (checks: %s)", status.State))
+		return false, nil
+	}
+	if err := p.mergePullRequest(client, owner, repository, pullRequest, updateGroup.GetAutomergeStrategy(), false); err != nil {
+		return false, err
+	}
+	p.logger.Info(fmt.Sprintf("Merged PR: %s", pullRequest.HTMLURL))
+	return true, nil
+}
+
+func (p *GiteaPlatform) MarkModified(project *common.Project, updateGroup *common.UpdateGroup) error {
+	// Prepare the data for the API
+	owner, repository := project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	// Search for the PR
+	pullRequest, err := p.findOpenPullRequest(client, owner, repository, updateGroup.BranchName)
+	if err != nil || pullRequest == nil {
+		return err
+	}
+
+	// Add the label
+	label := p.settings.ModifiedBranchLabel
+	if label != "" && !slices.ContainsFunc(pullRequest.Labels, func(existingLabel *gitea.Label) bool { return existingLabel.Name == label }) {
+		p.logger.Debug("Adding the label for modified branches")
+		labelIds, err := p.convertLabels(client, owner, repository, []string{label})
+		if err != nil {
+			return err
 		}
-		obsoleteBranchCount++
+		if _, _, err := client.AddIssueLabels(owner, repository, pullRequest.Index, gitea.IssueLabelsOption{Labels: labelIds}); err != nil {
+			return err
+		}
+	}
+
+	// Add the comment
+	if p.settings.ModifiedBranchComment {
+		return p.addCommentOnce(client, owner, repository, pullRequest.Index, ModifiedBranchMarker, modifiedBranchComment)
+	}
+	return nil
+}
+
+func (p *GiteaPlatform) AddPullRequestComment(project *common.Project, number int64, marker string, body string) error {
+	// Prepare the data for the API
+	owner, repository := project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+	return p.addCommentOnce(client, owner, repository, number, marker, body)
+}
+
+func (p *GiteaPlatform) GetDashboardBody(project *common.Project) (string, error) {
+	// Prepare the data for the API
+	owner, repository := project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return "", err
+	}
+
+	// Search for the dashboard issue
+	existingIssue, err := p.findDashboardIssue(client, owner, repository)
+	if err != nil || existingIssue == nil {
+		return "", err
+	}
+	return existingIssue.Body, nil
+}
+
+func (p *GiteaPlatform) UpsertDashboard(project *common.Project, title string, body string) error {
+	// Prepare the data for the API
+	owner, repository := project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	// Search for an existing dashboard issue
+	existingIssue, err := p.findDashboardIssue(client, owner, repository)
+	if err != nil {
+		return err
 	}
 
-	p.logger.Info(fmt.Sprintf("Finished cleaning branches. Active: %d, Deleted: %d", activeBranchCount, obsoleteBranchCount))
+	if existingIssue != nil {
+		// Update the issue if something changed
+		if existingIssue.Title != title || existingIssue.Body != body {
+			p.logger.Debug(fmt.Sprintf("Updating dashboard issue: %s", existingIssue.HTMLURL))
+			if _, _, err := client.EditIssue(owner, repository, existingIssue.Index, gitea.EditIssueOption{
+				Title: title,
+				Body:  gitea.OptionalString(body),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
+	// Create the issue
+	issue, _, err := client.CreateIssue(owner, repository, gitea.CreateIssueOption{
+		Title: title,
+		Body:  body,
+	})
+	if err != nil {
+		return err
+	}
+	p.logger.Info(fmt.Sprintf("Created dashboard issue: %s", issue.HTMLURL))
 	return nil
 }
 
+func (p *GiteaPlatform) ListRepositories(namespaces []string, needsTopics func(repository *RepositoryInfo) bool) ([]*RepositoryInfo, error) {
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the repositories (paginated)
+	giteaRepositories := []*gitea.Repository{}
+	if len(namespaces) == 0 {
+		// All repositories the user has access to
+		options := gitea.ListReposOptions{ListOptions: gitea.ListOptions{Page: 1, PageSize: 50}}
+		for {
+			repositories, resp, err := client.ListMyRepos(options)
+			if err != nil {
+				return nil, err
+			}
+			giteaRepositories = append(giteaRepositories, repositories...)
+			if resp == nil || resp.NextPage == 0 {
+				break
+			}
+			options.Page = resp.NextPage
+		}
+	}
+	for _, namespace := range namespaces {
+		options := gitea.ListOrgReposOptions{ListOptions: gitea.ListOptions{Page: 1, PageSize: 50}}
+		for {
+			repositories, resp, err := client.ListOrgRepos(namespace, options)
+			if err != nil {
+				return nil, fmt.Errorf("failed listing the repositories of the organization '%s': %w", namespace, err)
+			}
+			giteaRepositories = append(giteaRepositories, repositories...)
+			if resp == nil || resp.NextPage == 0 {
+				break
+			}
+			options.Page = resp.NextPage
+		}
+	}
+
+	repositoryInfos := []*RepositoryInfo{}
+	for _, repository := range giteaRepositories {
+		info := &RepositoryInfo{
+			Path:     repository.FullName,
+			Archived: repository.Archived,
+			Fork:     repository.Fork,
+		}
+		// The topics are not part of the repositories, so only get them if needed
+		if needsTopics != nil && needsTopics(info) {
+			owner, name, _ := strings.Cut(repository.FullName, "/")
+			topics, _, err := client.ListRepoTopics(owner, name, gitea.ListRepoTopicsOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed listing the topics of the repository '%s': %w", repository.FullName, err)
+			}
+			info.Topics = topics
+		}
+		repositoryInfos = append(repositoryInfos, info)
+	}
+	return repositoryInfos, nil
+}
+
 ////////////////////////////////////////////////////////////
 // Internal
 ////////////////////////////////////////////////////////////
@@ -280,3 +501,80 @@ func (p *GiteaPlatform) getAllLabels(client *gitea.Client, owner, repository str
 
 	return labelsMap, nil
 }
+
+// Searches for the open PR of the given branch. Returns nil if there is none.
+func (p *GiteaPlatform) findOpenPullRequest(client *gitea.Client, owner, repository, branchName string) (*gitea.PullRequest, error) {
+	pullRequests, _, err := client.ListRepoPullRequests(owner, repository, gitea.ListPullRequestsOptions{
+		State: gitea.StateOpen,
+	})
+	if err != nil {
+		return nil, err
+	}
+	existingPr, _ := lo.Find(pullRequests, func(pr *gitea.PullRequest) bool {
+		return pr.Head.Ref == branchName && pr.Base.Ref == p.settings.BaseBranch
+	})
+	return existingPr, nil
+}
+
+// Adds the comment to the issue or PR unless there is already a comment containing the marker.
+func (p *GiteaPlatform) addCommentOnce(client *gitea.Client, owner, repository string, index int64, marker string, body string) error {
+	options := gitea.ListIssueCommentOptions{ListOptions: gitea.ListOptions{Page: 1, PageSize: 50}}
+	for {
+		comments, resp, err := client.ListIssueComments(owner, repository, index, options)
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(comments, func(comment *gitea.Comment) bool { return strings.Contains(comment.Body, marker) }) {
+			return nil
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		options.Page = resp.NextPage
+	}
+	p.logger.Debug(fmt.Sprintf("Adding comment to #%d", index))
+	_, _, err := client.CreateIssueComment(owner, repository, index, gitea.CreateIssueCommentOption{
+		Body: body,
+	})
+	return err
+}
+
+// Merges the PR with the given strategy or, if whenChecksSucceed is set, schedules the merge for when all checks succeeded.
+func (p *GiteaPlatform) mergePullRequest(client *gitea.Client, owner, repository string, pullRequest *gitea.PullRequest, strategy common.AutomergeStrategy, whenChecksSucceed bool) error {
+	merged, response, err := client.MergePullRequest(owner, repository, pullRequest.Index, gitea.MergePullRequestOption{
+		Style:                  gitea.MergeStyle(strategy),
+		HeadCommitId:           pullRequest.Head.Sha,
+		DeleteBranchAfterMerge: gitea.OptionalBool(true),
+		MergeWhenChecksSucceed: whenChecksSucceed,
+	})
+	if err != nil {
+		return err
+	}
+	// Gitea responds with a conflict if the merge is already scheduled
+	if !merged && !(whenChecksSucceed && response.StatusCode == http.StatusConflict) {
+		return fmt.Errorf("merge was rejected with status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// Searches for the open dashboard issue. Returns nil if there is none.
+func (p *GiteaPlatform) findDashboardIssue(client *gitea.Client, owner, repository string) (*gitea.Issue, error) {
+	options := gitea.ListIssueOption{
+		State:       gitea.StateOpen,
+		Type:        gitea.IssueTypeIssue,
+		ListOptions: gitea.ListOptions{Page: 1, PageSize: 50},
+	}
+	for {
+		issues, resp, err := client.ListRepoIssues(owner, repository, options)
+		if err != nil {
+			return nil, err
+		}
+		if existingIssue, found := lo.Find(issues, func(issue *gitea.Issue) bool { return isDashboardBody(issue.Body) }); found {
+			return existingIssue, nil
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return nil, nil
+		}
+		options.Page = resp.NextPage
+	}
+}
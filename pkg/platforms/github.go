@@ -3,6 +3,7 @@ package platforms
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"slices"
 	"strings"
@@ -12,8 +13,13 @@ import (
 	"github.com/samber/lo"
 )
 
+// GitHub limits the body of PRs to this amount of characters.
+const gitHubMaxBodyLength = 65536
+
 type GitHubPlatform struct {
 	*GitPlatform
+	// The token in the remote of the cloned project. Installation tokens of GitHub Apps expire, so it is renewed if needed.
+	remoteToken string
 }
 
 func NewGitHubPlatform(settings *common.PlatformSettings) *GitHubPlatform {
@@ -44,17 +50,26 @@ func (p *GitHubPlatform) FetchProject(project *common.Project) error {
 	if platformRepository == nil {
 		return fmt.Errorf("could not find project: %s", project.Path)
 	}
-	cloneUrl := *platformRepository.CloneURL
-	cloneUrlWithCredentials, err := url.Parse(cloneUrl)
+	token, err := p.getToken()
 	if err != nil {
 		return err
 	}
-	cloneUrlWithCredentials.User = url.UserPassword("oauth2", p.settings.TokenExpanded())
-	_, _, err = common.Git.Run("clone", cloneUrlWithCredentials.String(), ClonePath)
-	return err
+	cloneUrlWithCredentials, err := p.buildUrlWithCredentials(platformRepository.GetCloneURL(), token)
+	if err != nil {
+		return err
+	}
+	if _, _, err := common.Git.Run("clone", cloneUrlWithCredentials, ClonePath); err != nil {
+		return err
+	}
+	p.remoteToken = token
+	return nil
 }
 
 func (p *GitHubPlatform) LookupAuthor() (string, string, error) {
+	// Commits of an app are attributed to its bot user
+	if p.settings.GitHubApp != nil {
+		return p.settings.GitHubApp.LookupBotUser()
+	}
 	client, err := p.createClient()
 	if err != nil {
 		return "", "", err
@@ -82,6 +97,27 @@ func (p *GitHubPlatform) LookupAuthor() (string, string, error) {
 	return user.GetName(), user.GetEmail(), nil
 }
 
+func (p *GitHubPlatform) IsNewOrChanged(updateGroup *common.UpdateGroup) (bool, error) {
+	if err := p.refreshRemoteCredentials(); err != nil {
+		return false, err
+	}
+	return p.GitPlatform.IsNewOrChanged(updateGroup)
+}
+
+func (p *GitHubPlatform) IsModified(updateGroup *common.UpdateGroup) (bool, error) {
+	if err := p.refreshRemoteCredentials(); err != nil {
+		return false, err
+	}
+	return p.GitPlatform.IsModified(updateGroup)
+}
+
+func (p *GitHubPlatform) PublishChanges(updateGroup *common.UpdateGroup) error {
+	if err := p.refreshRemoteCredentials(); err != nil {
+		return err
+	}
+	return p.GitPlatform.PublishChanges(updateGroup)
+}
+
 func (p *GitHubPlatform) NotifyChanges(project *common.Project, updateGroup *common.UpdateGroup) error {
 	// Prepare the data for the API
 	owner, repository := project.SplitPath()
@@ -93,26 +129,16 @@ func (p *GitHubPlatform) NotifyChanges(project *common.Project, updateGroup *com
 	}
 
 	// Build the content of the PR
-	content := ""
-	for _, dep := range updateGroup.Dependencies {
-		content += fmt.Sprintf("- %s from %s to %s\n", dep.Dependency.Name, dep.Dependency.Version, dep.NewRelease.VersionString)
-	}
-	// Trim spaces / newlines
-	content = strings.TrimSpace(content)
+	content := p.buildLimitedPullRequestBody(updateGroup, gitHubMaxBodyLength)
 
 	// Search for an existing PR
-	existingRequest, _, err := client.PullRequests.List(context.Background(), owner, repository, &github.PullRequestListOptions{
-		Head:  updateGroup.BranchName,
-		Base:  p.settings.BaseBranch,
-		State: "open",
-	})
+	existingPr, err := p.findOpenPullRequest(client, owner, repository, updateGroup.BranchName)
 	if err != nil {
 		return err
 	}
 
-	// The "Head" search parameter does not work without "user:", so just make sure that the returned list really contains the branch
-	existingPr, prExists := lo.Find(existingRequest, func(pr *github.PullRequest) bool { return pr.Head.GetRef() == updateGroup.BranchName })
-	if prExists {
+	pullRequest := existingPr
+	if existingPr != nil {
 		p.logger.Info(fmt.Sprintf("PR already exists: %s", existingPr.GetHTMLURL()))
 
 		// Update the PR if something changed
@@ -157,6 +183,7 @@ func (p *GitHubPlatform) NotifyChanges(project *common.Project, updateGroup *com
 			return err
 		}
 		p.logger.Info(fmt.Sprintf("Created PR: %s", pr.GetHTMLURL()))
+		pullRequest = pr
 		if len(updateGroup.Labels) > 0 {
 			// Labels need to be added separately
 			_, _, err := client.Issues.ReplaceLabelsForIssue(context.Background(), owner, repository, pr.GetNumber(), updateGroup.Labels)
@@ -174,23 +201,22 @@ func (p *GitHubPlatform) NotifyChanges(project *common.Project, updateGroup *com
 			}
 		}
 	}
+
+	// Enable the auto-merge of the platform
+	if updateGroup.Automerge && updateGroup.PlatformAutomerge && pullRequest.AutoMerge == nil {
+		p.logger.Info("Enabling auto-merge for the PR")
+		if err := p.enableAutomerge(client, owner, repository, pullRequest, updateGroup.GetAutomergeStrategy()); err != nil {
+			return fmt.Errorf("failed enabling auto-merge: %w", err)
+		}
+	}
 	return nil
 }
 
 func (p *GitHubPlatform) Cleanup(cleanupSettings *PlatformCleanupSettings) error {
-	remoteName := p.getRemoteName()
-
-	// Get the remote branches for gonovate
-	gonovateBranches, err := p.getRemoteGonovateBranches(remoteName, cleanupSettings.BranchPrefix)
-	if err != nil {
+	if err := p.refreshRemoteCredentials(); err != nil {
 		return err
 	}
 
-	// Get the branches that were used in this gonovate run
-	usedBranches := lo.FlatMap(cleanupSettings.UpdateGroups, func(x *common.UpdateGroup, _ int) []string {
-		return []string{x.BranchName}
-	})
-
 	// Prepare the data for the API
 	owner, repository := cleanupSettings.Project.SplitPath()
 
@@ -200,21 +226,10 @@ func (p *GitHubPlatform) Cleanup(cleanupSettings *PlatformCleanupSettings) error
 		return err
 	}
 
-	// Loop thru the branches and check if they are active or not
-	activeBranchCount := 0
-	obsoleteBranchCount := 0
-	for _, potentialStaleBranch := range gonovateBranches {
-		if slices.Contains(usedBranches, potentialStaleBranch) {
-			// This branch is used
-			activeBranchCount++
-			continue
-		}
-		// This branch is unused, delete the branch and a possible associated PR
-		p.logger.Info(fmt.Sprintf("Removing unused branch '%s'", potentialStaleBranch))
-
+	return p.cleanupBranches(cleanupSettings, func(branchName string) error {
 		// Search for an existing PR
 		existingRequest, _, err := client.PullRequests.List(context.Background(), owner, repository, &github.PullRequestListOptions{
-			Head:  potentialStaleBranch,
+			Head:  branchName,
 			Base:  cleanupSettings.BaseBranch,
 			State: "open",
 		})
@@ -222,37 +237,501 @@ func (p *GitHubPlatform) Cleanup(cleanupSettings *PlatformCleanupSettings) error
 			return err
 		}
 		// The "Head" search parameter does not work without "user:", so just make sure that the returned list really contains the branch
-		existingPr, prExists := lo.Find(existingRequest, func(pr *github.PullRequest) bool { return pr.Head.GetRef() == potentialStaleBranch })
+		existingPr, prExists := lo.Find(existingRequest, func(pr *github.PullRequest) bool { return pr.Head.GetRef() == branchName })
 		if prExists {
-			// Close the PR
+			// Close the PR (without the update markers as the updates are not declined by this)
 			p.logger.Info(fmt.Sprintf("Closing associated PR: %s", *existingPr.HTMLURL))
 			if _, _, err := client.PullRequests.Edit(context.Background(), owner, repository, existingPr.GetNumber(), &github.PullRequest{
 				State: github.Ptr("closed"),
+				Body:  github.Ptr(removeUpdateMarkers(existingPr.GetBody())),
 			}); err != nil {
 				return err
 			}
 		}
+		return nil
+	})
+}
+
+func (p *GitHubPlatform) ListPullRequests(project *common.Project, branchPrefix string, includeClosed bool) ([]*PullRequestInfo, error) {
+	// Prepare the data for the API
+	owner, repository := project.SplitPath()
 
-		// Delete the unused branch
-		p.logger.Debug("Deleting the branch")
-		if _, _, err := common.Git.Run("push", remoteName, "--delete", potentialStaleBranch); err != nil {
-			return fmt.Errorf("failed to delete the remote branch '%s'", potentialStaleBranch)
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all PRs (paginated)
+	options := &github.PullRequestListOptions{
+		State:       lo.Ternary(includeClosed, "all", "open"),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	pullRequests := []*PullRequestInfo{}
+	for {
+		prs, resp, err := client.PullRequests.List(context.Background(), owner, repository, options)
+		if err != nil {
+			return nil, err
 		}
-		obsoleteBranchCount++
+		for _, pr := range prs {
+			info := &PullRequestInfo{
+				Number:     int64(pr.GetNumber()),
+				Title:      pr.GetTitle(),
+				Url:        pr.GetHTMLURL(),
+				BranchName: pr.GetHead().GetRef(),
+				Body:       pr.GetBody(),
+				State:      PULL_REQUEST_STATE_OPEN,
+				CreatedAt:  pr.GetCreatedAt().Time,
+			}
+			if pr.GetState() == "closed" {
+				info.State = lo.Ternary(pr.MergedAt != nil, PULL_REQUEST_STATE_MERGED, PULL_REQUEST_STATE_CLOSED)
+			}
+			if pullRequestMatches(info, branchPrefix, includeClosed) {
+				pullRequests = append(pullRequests, info)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		options.Page = resp.NextPage
+	}
+	return pullRequests, nil
+}
+
+func (p *GitHubPlatform) MergeIfReady(project *common.Project, updateGroup *common.UpdateGroup) (bool, error) {
+	// Prepare the data for the API
+	owner, repository := project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return false, err
+	}
+
+	// Search for the PR
+	existingPr, err := p.findOpenPullRequest(client, owner, repository, updateGroup.BranchName)
+	if err != nil || existingPr == nil {
+		return false, err
+	}
+	// The list does not contain the mergeable state, so get the full PR
+	pullRequest, _, err := client.PullRequests.Get(context.Background(), owner, repository, existingPr.GetNumber())
+	if err != nil {
+		return false, err
+	}
+	// Only merge if the PR is mergeable and all checks succeeded
+	if pullRequest.GetMergeableState() != "clean" {
+		p.logger.Info(fmt.Sprintf("PR is not ready to be merged (state: %s)", pullRequest.GetMergeableState()))
+		return false, nil
+	}
+	if succeeded, state, err := p.checksSucceeded(client, owner, repository, pullRequest.GetHead().GetSHA()); err != nil {
+		return false, err
+	} else if !succeeded {
+		p.logger.Info(fmt.Sprintf("PR is not ready to be merged (%s)", state))
+		return false, nil
+	}
+	if err := p.mergePullRequest(client, owner, repository, pullRequest, updateGroup.GetAutomergeStrategy()); err != nil {
+		return false, err
+	}
+	p.logger.Info(fmt.Sprintf("Merged PR: %s", pullRequest.GetHTMLURL()))
+	return true, nil
+}
+
+func (p *GitHubPlatform) MarkModified(project *common.Project, updateGroup *common.UpdateGroup) error {
+	// Prepare the data for the API
+	owner, repository := project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	// Search for the PR
+	pullRequest, err := p.findOpenPullRequest(client, owner, repository, updateGroup.BranchName)
+	if err != nil || pullRequest == nil {
+		return err
+	}
+
+	// Add the label
+	label := p.settings.ModifiedBranchLabel
+	if label != "" && !slices.ContainsFunc(pullRequest.Labels, func(existingLabel *github.Label) bool { return existingLabel.GetName() == label }) {
+		p.logger.Debug("Adding the label for modified branches")
+		if _, _, err := client.Issues.AddLabelsToIssue(context.Background(), owner, repository, pullRequest.GetNumber(), []string{label}); err != nil {
+			return err
+		}
+	}
+
+	// Add the comment
+	if p.settings.ModifiedBranchComment {
+		return p.addCommentOnce(client, owner, repository, pullRequest.GetNumber(), ModifiedBranchMarker, modifiedBranchComment)
+	}
+	return nil
+}
+
+func (p *GitHubPlatform) AddPullRequestComment(project *common.Project, number int64, marker string, body string) error {
+	// Prepare the data for the API
+	owner, repository := project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+	return p.addCommentOnce(client, owner, repository, int(number), marker, body)
+}
+
+func (p *GitHubPlatform) GetDashboardBody(project *common.Project) (string, error) {
+	// Prepare the data for the API
+	owner, repository := project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return "", err
+	}
+
+	// Search for the dashboard issue
+	existingIssue, err := p.findDashboardIssue(client, owner, repository)
+	if err != nil || existingIssue == nil {
+		return "", err
+	}
+	return existingIssue.GetBody(), nil
+}
+
+func (p *GitHubPlatform) UpsertDashboard(project *common.Project, title string, body string) error {
+	// Prepare the data for the API
+	owner, repository := project.SplitPath()
+
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	// Search for an existing dashboard issue
+	existingIssue, err := p.findDashboardIssue(client, owner, repository)
+	if err != nil {
+		return err
 	}
 
-	p.logger.Info(fmt.Sprintf("Finished cleaning branches. Active: %d, Deleted: %d", activeBranchCount, obsoleteBranchCount))
+	if existingIssue != nil {
+		// Update the issue if something changed
+		if existingIssue.GetTitle() != title || existingIssue.GetBody() != body {
+			p.logger.Debug(fmt.Sprintf("Updating dashboard issue: %s", existingIssue.GetHTMLURL()))
+			if _, _, err := client.Issues.Edit(context.Background(), owner, repository, existingIssue.GetNumber(), &github.IssueRequest{
+				Title: github.Ptr(title),
+				Body:  github.Ptr(body),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
+	// Create the issue
+	issue, _, err := client.Issues.Create(context.Background(), owner, repository, &github.IssueRequest{
+		Title: github.Ptr(title),
+		Body:  github.Ptr(body),
+	})
+	if err != nil {
+		return err
+	}
+	p.logger.Info(fmt.Sprintf("Created dashboard issue: %s", issue.GetHTMLURL()))
 	return nil
 }
 
+func (p *GitHubPlatform) ListRepositories(namespaces []string, needsTopics func(repository *RepositoryInfo) bool) ([]*RepositoryInfo, error) {
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the repositories (paginated)
+	gitHubRepositories := []*github.Repository{}
+	if len(namespaces) == 0 {
+		// All repositories of the installation of the app or the ones the user has access to
+		listOptions := github.ListOptions{PerPage: 100}
+		for {
+			var repositories []*github.Repository
+			var resp *github.Response
+			if p.settings.GitHubApp != nil {
+				var installationRepositories *github.ListRepositories
+				installationRepositories, resp, err = client.Apps.ListRepos(context.Background(), &listOptions)
+				if installationRepositories != nil {
+					repositories = installationRepositories.Repositories
+				}
+			} else {
+				repositories, resp, err = client.Repositories.ListByAuthenticatedUser(context.Background(), &github.RepositoryListByAuthenticatedUserOptions{ListOptions: listOptions})
+			}
+			if err != nil {
+				return nil, err
+			}
+			gitHubRepositories = append(gitHubRepositories, repositories...)
+			if resp.NextPage == 0 {
+				break
+			}
+			listOptions.Page = resp.NextPage
+		}
+	}
+	for _, namespace := range namespaces {
+		repositories, err := p.listNamespaceRepositories(client, namespace)
+		if err != nil {
+			return nil, err
+		}
+		gitHubRepositories = append(gitHubRepositories, repositories...)
+	}
+
+	// The topics are always part of the repositories
+	return lo.Map(gitHubRepositories, func(repository *github.Repository, _ int) *RepositoryInfo {
+		return &RepositoryInfo{
+			Path:     repository.GetFullName(),
+			Topics:   repository.Topics,
+			Archived: repository.GetArchived(),
+			Fork:     repository.GetFork(),
+		}
+	}), nil
+}
+
 ////////////////////////////////////////////////////////////
 // Internal
 ////////////////////////////////////////////////////////////
 
+// Lists the repositories of an organization or, if there is no such organization, of a user.
+func (p *GitHubPlatform) listNamespaceRepositories(client *github.Client, namespace string) ([]*github.Repository, error) {
+	allRepositories := []*github.Repository{}
+	listOptions := github.ListOptions{PerPage: 100}
+	isUser := false
+	for {
+		var repositories []*github.Repository
+		var resp *github.Response
+		var err error
+		if isUser {
+			repositories, resp, err = client.Repositories.ListByUser(context.Background(), namespace, &github.RepositoryListByUserOptions{ListOptions: listOptions})
+		} else {
+			repositories, resp, err = client.Repositories.ListByOrg(context.Background(), namespace, &github.RepositoryListByOrgOptions{ListOptions: listOptions})
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				isUser = true
+				continue
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		allRepositories = append(allRepositories, repositories...)
+		if resp.NextPage == 0 {
+			break
+		}
+		listOptions.Page = resp.NextPage
+	}
+	return allRepositories, nil
+}
+
 func (p *GitHubPlatform) createClient() (*github.Client, error) {
+	token, err := p.getToken()
+	if err != nil {
+		return nil, err
+	}
+	return common.NewGitHubClient(p.settings.EndpointExpanded(), token)
+}
+
+// Returns the installation token of the GitHub App or the configured token.
+func (p *GitHubPlatform) getToken() (string, error) {
+	if p.settings != nil && p.settings.GitHubApp != nil {
+		return p.settings.GitHubApp.Token()
+	}
 	if p.settings == nil || p.settings.Token == "" {
-		return nil, fmt.Errorf("no platform token defined")
+		return "", fmt.Errorf("no platform token defined")
+	}
+	return p.settings.TokenExpanded(), nil
+}
+
+// Adds the token to the given url. Installation tokens of GitHub Apps need a specific user.
+func (p *GitHubPlatform) buildUrlWithCredentials(rawUrl string, token string) (string, error) {
+	urlWithCredentials, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+	user := lo.Ternary(p.settings.GitHubApp != nil, "x-access-token", "oauth2")
+	urlWithCredentials.User = url.UserPassword(user, token)
+	return urlWithCredentials.String(), nil
+}
+
+// Updates the credentials of the remote of the cloned project if the token was renewed in the meantime.
+func (p *GitHubPlatform) refreshRemoteCredentials() error {
+	if p.settings.GitHubApp == nil || p.remoteToken == "" {
+		return nil
+	}
+	token, err := p.settings.GitHubApp.Token()
+	if err != nil {
+		return err
+	}
+	if token == p.remoteToken {
+		return nil
+	}
+	p.logger.Debug("Renewing the credentials of the remote")
+	remoteName := p.getRemoteName()
+	remoteUrl, _, err := common.Git.Run("remote", "get-url", remoteName)
+	if err != nil {
+		return err
+	}
+	remoteUrlWithCredentials, err := p.buildUrlWithCredentials(strings.TrimSpace(remoteUrl), token)
+	if err != nil {
+		return err
+	}
+	if _, _, err := common.Git.Run("remote", "set-url", remoteName, remoteUrlWithCredentials); err != nil {
+		return err
+	}
+	p.remoteToken = token
+	return nil
+}
+
+// Searches for the open PR of the given branch. Returns nil if there is none.
+func (p *GitHubPlatform) findOpenPullRequest(client *github.Client, owner, repository, branchName string) (*github.PullRequest, error) {
+	pullRequests, _, err := client.PullRequests.List(context.Background(), owner, repository, &github.PullRequestListOptions{
+		Head:  branchName,
+		Base:  p.settings.BaseBranch,
+		State: "open",
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The "Head" search parameter does not work without "user:", so just make sure that the returned list really contains the branch
+	existingPr, _ := lo.Find(pullRequests, func(pr *github.PullRequest) bool { return pr.Head.GetRef() == branchName })
+	return existingPr, nil
+}
+
+// Enables the auto-merge of the PR. This is only available thru the GraphQL API.
+// Its path is relative to the REST API as it is "/graphql" on github.com and "/api/graphql" on GitHub Enterprise Server.
+// GitHub refuses to enable auto-merge if the PR can already be merged. Such PRs are merged with MergeIfReady on a later run
+// once all checks succeeded, as the checks might not even be registered yet.
+func (p *GitHubPlatform) enableAutomerge(client *github.Client, owner, repository string, pullRequest *github.PullRequest, strategy common.AutomergeStrategy) error {
+	request, err := client.NewRequest("POST", "../graphql", map[string]any{
+		"query": "mutation($id: ID!, $method: PullRequestMergeMethod!) { enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) { clientMutationId } }",
+		"variables": map[string]any{
+			"id":     pullRequest.GetNodeID(),
+			"method": strings.ToUpper(string(strategy)),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	response := &struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}{}
+	if _, err := client.Do(context.Background(), request, response); err != nil {
+		return err
+	}
+	if len(response.Errors) > 0 {
+		// The mergeable state is only returned when getting a single PR
+		currentPullRequest, _, err := client.PullRequests.Get(context.Background(), owner, repository, pullRequest.GetNumber())
+		if err != nil {
+			return err
+		}
+		if currentPullRequest.GetMergeableState() == "clean" {
+			p.logger.Info("PR can already be merged, it is merged on a later run once all checks succeeded")
+			return nil
+		}
+		return fmt.Errorf("%s", response.Errors[0].Message)
+	}
+	return nil
+}
+
+// Checks if all commit statuses and check runs of the commit succeeded (or there are none).
+// Returns a description of the state if they did not.
+func (p *GitHubPlatform) checksSucceeded(client *github.Client, owner, repository string, sha string) (bool, string, error) {
+	status, _, err := client.Repositories.GetCombinedStatus(context.Background(), owner, repository, sha, nil)
+	if err != nil {
+		return false, "", err
+	}
+	if status.GetTotalCount() > 0 && status.GetState() != "success" {
+		return false, "statuses: " + status.GetState(), nil
+	}
+	options := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		checkRuns, resp, err := client.Checks.ListCheckRunsForRef(context.Background(), owner, repository, sha, options)
+		if err != nil {
+			return false, "", err
+		}
+		for _, checkRun := range checkRuns.CheckRuns {
+			if checkRun.GetStatus() != "completed" {
+				return false, fmt.Sprintf("check '%s': %s", checkRun.GetName(), checkRun.GetStatus()), nil
+			}
+			if !slices.Contains([]string{"success", "neutral", "skipped"}, checkRun.GetConclusion()) {
+				return false, fmt.Sprintf("check '%s': %s", checkRun.GetName(), checkRun.GetConclusion()), nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		options.ListOptions.Page = resp.NextPage
+	}
+	return true, "", nil
+}
+
+// Merges the PR with the given strategy and deletes the branch afterwards.
+func (p *GitHubPlatform) mergePullRequest(client *github.Client, owner, repository string, pullRequest *github.PullRequest, strategy common.AutomergeStrategy) error {
+	if _, _, err := client.PullRequests.Merge(context.Background(), owner, repository, pullRequest.GetNumber(), "", &github.PullRequestOptions{
+		SHA:         pullRequest.GetHead().GetSHA(),
+		MergeMethod: string(strategy),
+	}); err != nil {
+		return err
+	}
+	// The branch might already be deleted automatically by GitHub
+	if resp, err := client.Git.DeleteRef(context.Background(), owner, repository, "heads/"+pullRequest.GetHead().GetRef()); err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity) {
+			p.logger.Debug(fmt.Sprintf("Branch was already deleted after the merge: %s", err.Error()))
+			return nil
+		}
+		return fmt.Errorf("failed deleting branch after merge: %w", err)
+	}
+	return nil
+}
+
+// Adds the comment to the issue or PR unless there is already a comment containing the marker.
+func (p *GitHubPlatform) addCommentOnce(client *github.Client, owner, repository string, number int, marker string, body string) error {
+	options := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := client.Issues.ListComments(context.Background(), owner, repository, number, options)
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(comments, func(comment *github.IssueComment) bool { return strings.Contains(comment.GetBody(), marker) }) {
+			return nil
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		options.ListOptions.Page = resp.NextPage
+	}
+	p.logger.Debug(fmt.Sprintf("Adding comment to #%d", number))
+	_, _, err := client.Issues.CreateComment(context.Background(), owner, repository, number, &github.IssueComment{
+		Body: github.Ptr(body),
+	})
+	return err
+}
+
+// Searches for the open dashboard issue. Returns nil if there is none.
+func (p *GitHubPlatform) findDashboardIssue(client *github.Client, owner, repository string) (*github.Issue, error) {
+	options := &github.IssueListByRepoOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		issues, resp, err := client.Issues.ListByRepo(context.Background(), owner, repository, options)
+		if err != nil {
+			return nil, err
+		}
+		if existingIssue, found := lo.Find(issues, func(issue *github.Issue) bool {
+			return !issue.IsPullRequest() && isDashboardBody(issue.GetBody())
+		}); found {
+			return existingIssue, nil
+		}
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		options.ListOptions.Page = resp.NextPage
 	}
-	return github.NewClient(nil).WithAuthToken(p.settings.TokenExpanded()), nil
 }
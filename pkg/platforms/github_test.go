@@ -0,0 +1,110 @@
+package platforms
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v84/github"
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// An in-process stand-in of the API of GitHub Enterprise Server for a single PR. Returns the url and the recorded write requests.
+func newFakeGitHub(t *testing.T, mergeableState *string, checkRuns *string, graphqlResponse *string) (string, *[]string) {
+	requests := []string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/owner/repo/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"number":1,"node_id":"PR_1","mergeable_state":"` + *mergeableState + `","head":{"ref":"gonovate/golang","sha":"abc"}}`))
+	})
+	mux.HandleFunc("GET /api/v3/repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"number":1,"head":{"ref":"gonovate/golang","sha":"abc"}}]`))
+	})
+	mux.HandleFunc("GET /api/v3/repos/owner/repo/commits/abc/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state":"pending","total_count":0}`))
+	})
+	mux.HandleFunc("GET /api/v3/repos/owner/repo/commits/abc/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total_count":1,"check_runs":[` + *checkRuns + `]}`))
+	})
+	mux.HandleFunc("POST /api/graphql", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Write([]byte(*graphqlResponse))
+	})
+	mux.HandleFunc("DELETE /api/v3/repos/owner/repo/git/refs/heads/gonovate/golang", func(w http.ResponseWriter, r *http.Request) {
+		// The branch was already deleted automatically
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message":"Reference does not exist"}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL, &requests
+}
+
+func TestGitHubEnableAutomerge(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	mergeableState := "blocked"
+	checkRuns := ""
+	graphqlResponse := `{"data":{}}`
+	serverUrl, requests := newFakeGitHub(t, &mergeableState, &checkRuns, &graphqlResponse)
+	platform := NewGitHubPlatform(&common.PlatformSettings{Logger: slog.Default(), Token: "token", Endpoint: serverUrl})
+	client, err := platform.createClient()
+	require.NoError(err)
+	pullRequest := &github.PullRequest{Number: github.Ptr(1)}
+
+	// The auto-merge is enabled
+	require.NoError(platform.enableAutomerge(client, "owner", "repo", pullRequest, common.AUTOMERGE_STRATEGY_SQUASH))
+	assert.Equal([]string{"POST /api/graphql"}, *requests)
+
+	// PRs which can already be merged are not merged directly as the checks might not be registered yet
+	mergeableState = "clean"
+	graphqlResponse = `{"errors":[{"message":"Pull request is in clean status"}]}`
+	*requests = []string{}
+	require.NoError(platform.enableAutomerge(client, "owner", "repo", pullRequest, common.AUTOMERGE_STRATEGY_SQUASH))
+	assert.Equal([]string{"POST /api/graphql"}, *requests)
+
+	// Other errors are returned
+	mergeableState = "blocked"
+	graphqlResponse = `{"errors":[{"message":"Auto merge is not allowed"}]}`
+	assert.ErrorContains(platform.enableAutomerge(client, "owner", "repo", pullRequest, common.AUTOMERGE_STRATEGY_SQUASH), "Auto merge is not allowed")
+}
+
+func TestGitHubMergeIfReady(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	mergeableState := "clean"
+	checkRuns := `{"name":"build","status":"in_progress"}`
+	graphqlResponse := `{"data":{}}`
+	serverUrl, requests := newFakeGitHub(t, &mergeableState, &checkRuns, &graphqlResponse)
+	platform := NewGitHubPlatform(&common.PlatformSettings{Logger: slog.Default(), Platform: common.PLATFORM_TYPE_GITHUB, Token: "token", Endpoint: serverUrl, BaseBranch: "main"})
+	project := &common.Project{Path: "owner/repo"}
+	updateGroup := &common.UpdateGroup{BranchName: "gonovate/golang", AutomergeStrategy: common.AUTOMERGE_STRATEGY_SQUASH}
+
+	// Running checks
+	merged, err := platform.MergeIfReady(project, updateGroup)
+	require.NoError(err)
+	assert.False(merged)
+
+	// Failed checks
+	checkRuns = `{"name":"build","status":"completed","conclusion":"failure"}`
+	merged, err = platform.MergeIfReady(project, updateGroup)
+	require.NoError(err)
+	assert.False(merged)
+	assert.Empty(*requests)
+
+	// Succeeded checks, the branch was already deleted by GitHub
+	checkRuns = `{"name":"build","status":"completed","conclusion":"success"}`
+	merged, err = platform.MergeIfReady(project, updateGroup)
+	require.NoError(err)
+	assert.True(merged)
+	assert.Equal([]string{"PUT /api/v3/repos/owner/repo/pulls/1/merge", "DELETE /api/v3/repos/owner/repo/git/refs/heads/gonovate/golang"}, *requests)
+}
@@ -12,6 +12,9 @@ import (
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 )
 
+// GitLab limits the description of MRs to this amount of characters.
+const gitlabMaxDescriptionLength = 1048576
+
 type GitlabPlatform struct {
 	*GitPlatform
 }
@@ -70,12 +73,7 @@ func (p *GitlabPlatform) NotifyChanges(project *common.Project, updateGroup *com
 	}
 
 	// Build the content of the MR
-	content := ""
-	for _, dep := range updateGroup.Dependencies {
-		content += fmt.Sprintf("- %s from %s to %s\n", dep.Dependency.Name, dep.Dependency.Version, dep.NewRelease.VersionString)
-	}
-	// Trim spaces / newlines
-	content = strings.TrimSpace(content)
+	content := p.buildLimitedPullRequestBody(updateGroup, gitlabMaxDescriptionLength)
 
 	// Search for an existing MR
 	mergeRequests, _, err := client.MergeRequests.ListProjectMergeRequests(project.Path, &gitlab.ListProjectMergeRequestsOptions{
@@ -87,7 +85,9 @@ func (p *GitlabPlatform) NotifyChanges(project *common.Project, updateGroup *com
 		return err
 	}
 
+	var mergeRequest *gitlab.BasicMergeRequest
 	if len(mergeRequests) > 0 {
+		mergeRequest = mergeRequests[0]
 		p.logger.Info(fmt.Sprintf("MR already exists: %s", mergeRequests[0].WebURL))
 
 		// Calculate the new reviewer list
@@ -152,75 +152,281 @@ func (p *GitlabPlatform) NotifyChanges(project *common.Project, updateGroup *com
 			return err
 		}
 		p.logger.Info(fmt.Sprintf("Created MR: %s", mr.WebURL))
+		mergeRequest = &mr.BasicMergeRequest
 	}
 
+	// Enable "merge when pipeline succeeds"
+	if updateGroup.Automerge && updateGroup.PlatformAutomerge && !mergeRequest.MergeWhenPipelineSucceeds {
+		p.logger.Info("Enabling auto-merge for the MR")
+		if err := p.acceptMergeRequest(client, project, mergeRequest, updateGroup.GetAutomergeStrategy(), true); err != nil {
+			return fmt.Errorf("failed enabling auto-merge: %w", err)
+		}
+	}
 	return nil
 }
 
 func (p *GitlabPlatform) Cleanup(cleanupSettings *PlatformCleanupSettings) error {
-	remoteName := p.getRemoteName()
-
-	// Get the remote branches for gonovate
-	gonovateBranches, err := p.getRemoteGonovateBranches(remoteName, cleanupSettings.BranchPrefix)
-	if err != nil {
-		return err
-	}
-
-	// Get the branches that were used in this gonovate run
-	usedBranches := lo.FlatMap(cleanupSettings.UpdateGroups, func(x *common.UpdateGroup, _ int) []string {
-		return []string{x.BranchName}
-	})
-
 	// Create the client
 	client, err := p.createClient()
 	if err != nil {
 		return err
 	}
 
-	// Loop thru the branches and check if they are active or not
-	activeBranchCount := 0
-	obsoleteBranchCount := 0
-	for _, potentialStaleBranch := range gonovateBranches {
-		if slices.Contains(usedBranches, potentialStaleBranch) {
-			// This branch is used
-			activeBranchCount++
-			continue
-		}
-		// This branch is unused, delete the branch and a possible associated MR
-		p.logger.Info(fmt.Sprintf("Removing unused branch '%s'", potentialStaleBranch))
-
+	return p.cleanupBranches(cleanupSettings, func(branchName string) error {
 		// Search for an existing MR
 		mergeRequests, _, err := client.MergeRequests.ListProjectMergeRequests(cleanupSettings.Project.Path, &gitlab.ListProjectMergeRequestsOptions{
-			SourceBranch: gitlab.Ptr(potentialStaleBranch),
+			SourceBranch: gitlab.Ptr(branchName),
 			TargetBranch: gitlab.Ptr(cleanupSettings.BaseBranch),
 			State:        gitlab.Ptr("opened"),
 		})
 		if err != nil {
 			return err
 		}
-		// Close all MRs
+		// Close all MRs (without the update markers as the updates are not declined by this)
 		for _, mr := range mergeRequests {
 			p.logger.Info(fmt.Sprintf("Closing associated MR: %s", mr.WebURL))
 			if _, _, err := client.MergeRequests.UpdateMergeRequest(cleanupSettings.Project.Path, mr.IID, &gitlab.UpdateMergeRequestOptions{
-				StateEvent: gitlab.Ptr("close"),
+				StateEvent:  gitlab.Ptr("close"),
+				Description: gitlab.Ptr(removeUpdateMarkers(mr.Description)),
 			}); err != nil {
 				return err
 			}
 		}
+		return nil
+	})
+}
+
+func (p *GitlabPlatform) ListPullRequests(project *common.Project, branchPrefix string, includeClosed bool) ([]*PullRequestInfo, error) {
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all MRs (paginated)
+	options := &gitlab.ListProjectMergeRequestsOptions{
+		State:       gitlab.Ptr(lo.Ternary(includeClosed, "all", "opened")),
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}
+	mergeRequests, err := gitlab.ScanAndCollect(func(pagination gitlab.PaginationOptionFunc) ([]*gitlab.BasicMergeRequest, *gitlab.Response, error) {
+		return client.MergeRequests.ListProjectMergeRequests(project.Path, options, pagination)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequests := []*PullRequestInfo{}
+	for _, mr := range mergeRequests {
+		info := &PullRequestInfo{
+			Number:     mr.IID,
+			Title:      mr.Title,
+			Url:        mr.WebURL,
+			BranchName: mr.SourceBranch,
+			Body:       mr.Description,
+			State:      PULL_REQUEST_STATE_OPEN,
+		}
+		if mr.CreatedAt != nil {
+			info.CreatedAt = *mr.CreatedAt
+		}
+		switch mr.State {
+		case "merged":
+			info.State = PULL_REQUEST_STATE_MERGED
+		case "closed", "locked":
+			info.State = PULL_REQUEST_STATE_CLOSED
+		}
+		if pullRequestMatches(info, branchPrefix, includeClosed) {
+			pullRequests = append(pullRequests, info)
+		}
+	}
+	return pullRequests, nil
+}
+
+func (p *GitlabPlatform) MergeIfReady(project *common.Project, updateGroup *common.UpdateGroup) (bool, error) {
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return false, err
+	}
+
+	// Search for the MR
+	mergeRequests, _, err := client.MergeRequests.ListProjectMergeRequests(project.Path, &gitlab.ListProjectMergeRequestsOptions{
+		SourceBranch: gitlab.Ptr(updateGroup.BranchName),
+		TargetBranch: gitlab.Ptr(p.settings.BaseBranch),
+		State:        gitlab.Ptr("opened"),
+	})
+	if err != nil || len(mergeRequests) == 0 {
+		return false, err
+	}
+	// The list does not contain the detailed status, so get the full MR
+	mergeRequest, _, err := client.MergeRequests.GetMergeRequest(project.Path, mergeRequests[0].IID, nil)
+	if err != nil {
+		return false, err
+	}
+	// Only merge if the MR is mergeable and the pipeline succeeded
+	if mergeRequest.DetailedMergeStatus != "mergeable" {
+		p.logger.Info(fmt.Sprintf("MR is not ready to be merged (status: %s)", mergeRequest.DetailedMergeStatus))
+		return false, nil
+	}
+	// Projects which do not require a successful pipeline report failed or running pipelines as mergeable
+	if mergeRequest.HeadPipeline != nil && mergeRequest.HeadPipeline.Status != "success" {
+		p.logger.Info(fmt.Sprintf("MR is not ready to be merged (pipeline: %s)", mergeRequest.HeadPipeline.Status))
+		return false, nil
+	}
+	if err := p.acceptMergeRequest(client, project, &mergeRequest.BasicMergeRequest, updateGroup.GetAutomergeStrategy(), false); err != nil {
+		return false, err
+	}
+	p.logger.Info(fmt.Sprintf("Merged MR: %s", mergeRequest.WebURL))
+	return true, nil
+}
+
+func (p *GitlabPlatform) MarkModified(project *common.Project, updateGroup *common.UpdateGroup) error {
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	// Search for the MR
+	mergeRequests, _, err := client.MergeRequests.ListProjectMergeRequests(project.Path, &gitlab.ListProjectMergeRequestsOptions{
+		SourceBranch: gitlab.Ptr(updateGroup.BranchName),
+		TargetBranch: gitlab.Ptr(p.settings.BaseBranch),
+		State:        gitlab.Ptr("opened"),
+	})
+	if err != nil || len(mergeRequests) == 0 {
+		return err
+	}
+	mergeRequest := mergeRequests[0]
 
-		// Delete the unused branch
-		p.logger.Debug("Deleting the branch")
-		if _, _, err := common.Git.Run("push", remoteName, "--delete", potentialStaleBranch); err != nil {
-			return fmt.Errorf("failed to delete the remote branch '%s'", potentialStaleBranch)
+	// Add the label
+	label := p.settings.ModifiedBranchLabel
+	if label != "" && !slices.Contains(mergeRequest.Labels, label) {
+		p.logger.Debug("Adding the label for modified branches")
+		if _, _, err := client.MergeRequests.UpdateMergeRequest(project.Path, mergeRequest.IID, &gitlab.UpdateMergeRequestOptions{
+			AddLabels: p.convertLabels([]string{label}),
+		}); err != nil {
+			return err
 		}
-		obsoleteBranchCount++
 	}
 
-	p.logger.Info(fmt.Sprintf("Finished cleaning branches. Active: %d, Deleted: %d", activeBranchCount, obsoleteBranchCount))
+	// Add the comment
+	if p.settings.ModifiedBranchComment {
+		return p.addCommentOnce(client, project, mergeRequest.IID, ModifiedBranchMarker, modifiedBranchComment)
+	}
+	return nil
+}
+
+func (p *GitlabPlatform) AddPullRequestComment(project *common.Project, number int64, marker string, body string) error {
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+	return p.addCommentOnce(client, project, number, marker, body)
+}
+
+func (p *GitlabPlatform) GetDashboardBody(project *common.Project) (string, error) {
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return "", err
+	}
+
+	// Search for the dashboard issue
+	existingIssue, err := p.findDashboardIssue(client, project)
+	if err != nil || existingIssue == nil {
+		return "", err
+	}
+	return existingIssue.Description, nil
+}
+
+func (p *GitlabPlatform) UpsertDashboard(project *common.Project, title string, body string) error {
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return err
+	}
+
+	// Search for an existing dashboard issue
+	existingIssue, err := p.findDashboardIssue(client, project)
+	if err != nil {
+		return err
+	}
+
+	if existingIssue != nil {
+		// Update the issue if something changed
+		if existingIssue.Title != title || existingIssue.Description != body {
+			p.logger.Debug(fmt.Sprintf("Updating dashboard issue: %s", existingIssue.WebURL))
+			if _, _, err := client.Issues.UpdateIssue(project.Path, existingIssue.IID, &gitlab.UpdateIssueOptions{
+				Title:       gitlab.Ptr(title),
+				Description: gitlab.Ptr(body),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
+	// Create the issue
+	issue, _, err := client.Issues.CreateIssue(project.Path, &gitlab.CreateIssueOptions{
+		Title:       gitlab.Ptr(title),
+		Description: gitlab.Ptr(body),
+	})
+	if err != nil {
+		return err
+	}
+	p.logger.Info(fmt.Sprintf("Created dashboard issue: %s", issue.WebURL))
 	return nil
 }
 
+func (p *GitlabPlatform) ListRepositories(namespaces []string, needsTopics func(repository *RepositoryInfo) bool) ([]*RepositoryInfo, error) {
+	// Create the client
+	client, err := p.createClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the projects (paginated)
+	gitLabProjects := []*gitlab.Project{}
+	if len(namespaces) == 0 {
+		// All projects the user is a member of
+		options := &gitlab.ListProjectsOptions{
+			Membership:  gitlab.Ptr(true),
+			ListOptions: gitlab.ListOptions{PerPage: 100},
+		}
+		projects, err := gitlab.ScanAndCollect(func(pagination gitlab.PaginationOptionFunc) ([]*gitlab.Project, *gitlab.Response, error) {
+			return client.Projects.ListProjects(options, pagination)
+		})
+		if err != nil {
+			return nil, err
+		}
+		gitLabProjects = append(gitLabProjects, projects...)
+	}
+	for _, namespace := range namespaces {
+		// The projects of the group including its subgroups
+		options := &gitlab.ListGroupProjectsOptions{
+			IncludeSubGroups: gitlab.Ptr(true),
+			ListOptions:      gitlab.ListOptions{PerPage: 100},
+		}
+		projects, err := gitlab.ScanAndCollect(func(pagination gitlab.PaginationOptionFunc) ([]*gitlab.Project, *gitlab.Response, error) {
+			return client.Groups.ListGroupProjects(namespace, options, pagination)
+		})
+		if err != nil {
+			return nil, err
+		}
+		gitLabProjects = append(gitLabProjects, projects...)
+	}
+
+	// The topics are always part of the projects
+	return lo.Map(gitLabProjects, func(project *gitlab.Project, _ int) *RepositoryInfo {
+		return &RepositoryInfo{
+			Path:     project.PathWithNamespace,
+			Topics:   project.Topics,
+			Archived: project.Archived,
+			Fork:     project.ForkedFromProject != nil,
+		}
+	}), nil
+}
+
 ////////////////////////////////////////////////////////////
 // Internal
 ////////////////////////////////////////////////////////////
@@ -292,3 +498,49 @@ func (p *GitlabPlatform) getUserIds(client *gitlab.Client, usernames []string) (
 	}
 	return userIds, nil
 }
+
+// Adds the comment to the MR unless there is already a comment containing the marker.
+func (p *GitlabPlatform) addCommentOnce(client *gitlab.Client, project *common.Project, mergeRequestIID int64, marker string, body string) error {
+	options := &gitlab.ListMergeRequestNotesOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+	notes, err := gitlab.ScanAndCollect(func(pagination gitlab.PaginationOptionFunc) ([]*gitlab.Note, *gitlab.Response, error) {
+		return client.Notes.ListMergeRequestNotes(project.Path, mergeRequestIID, options, pagination)
+	})
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(notes, func(note *gitlab.Note) bool { return strings.Contains(note.Body, marker) }) {
+		return nil
+	}
+	p.logger.Debug(fmt.Sprintf("Adding comment to !%d", mergeRequestIID))
+	_, _, err = client.Notes.CreateMergeRequestNote(project.Path, mergeRequestIID, &gitlab.CreateMergeRequestNoteOptions{
+		Body: gitlab.Ptr(body),
+	})
+	return err
+}
+
+// Merges the MR directly or, if autoMerge is set, as soon as the pipeline succeeded.
+func (p *GitlabPlatform) acceptMergeRequest(client *gitlab.Client, project *common.Project, mergeRequest *gitlab.BasicMergeRequest, strategy common.AutomergeStrategy, autoMerge bool) error {
+	// The merge method (merge commit, rebase) is a setting of the project, only squashing can be chosen per MR
+	_, _, err := client.MergeRequests.AcceptMergeRequest(project.Path, mergeRequest.IID, &gitlab.AcceptMergeRequestOptions{
+		AutoMerge:                gitlab.Ptr(autoMerge),
+		Squash:                   gitlab.Ptr(strategy == common.AUTOMERGE_STRATEGY_SQUASH),
+		ShouldRemoveSourceBranch: gitlab.Ptr(true),
+		SHA:                      gitlab.Ptr(mergeRequest.SHA),
+	})
+	return err
+}
+
+// Searches for the open dashboard issue. Returns nil if there is none.
+func (p *GitlabPlatform) findDashboardIssue(client *gitlab.Client, project *common.Project) (*gitlab.Issue, error) {
+	issues, err := gitlab.ScanAndCollect(func(pagination gitlab.PaginationOptionFunc) ([]*gitlab.Issue, *gitlab.Response, error) {
+		return client.Issues.ListProjectIssues(project.Path, &gitlab.ListProjectIssuesOptions{
+			State:       gitlab.Ptr("opened"),
+			ListOptions: gitlab.ListOptions{PerPage: 100},
+		}, pagination)
+	})
+	if err != nil {
+		return nil, err
+	}
+	existingIssue, _ := lo.Find(issues, func(issue *gitlab.Issue) bool { return isDashboardBody(issue.Description) })
+	return existingIssue, nil
+}
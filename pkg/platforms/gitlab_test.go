@@ -0,0 +1,62 @@
+package platforms
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitlabMergeIfReady(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	headPipeline := `{"id":1,"status":"running"}`
+	merged := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/group%2Fproject/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"iid":1}]`))
+	})
+	mux.HandleFunc("GET /api/v4/projects/group%2Fproject/merge_requests/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"iid":1,"sha":"abc","detailed_merge_status":"mergeable","head_pipeline":` + headPipeline + `}`))
+	})
+	mux.HandleFunc("PUT /api/v4/projects/group%2Fproject/merge_requests/1/merge", func(w http.ResponseWriter, r *http.Request) {
+		merged = true
+		w.Write([]byte(`{"iid":1}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	platform := NewGitlabPlatform(&common.PlatformSettings{Logger: slog.Default(), Token: "token", Endpoint: server.URL + "/api/v4", BaseBranch: "main"})
+	project := &common.Project{Path: "group/project"}
+	updateGroup := &common.UpdateGroup{BranchName: "gonovate/golang"}
+
+	// Mergeable, but the pipeline is still running (the project does not require a successful pipeline)
+	isMerged, err := platform.MergeIfReady(project, updateGroup)
+	require.NoError(err)
+	assert.False(isMerged)
+
+	// Failed pipeline
+	headPipeline = `{"id":1,"status":"failed"}`
+	isMerged, err = platform.MergeIfReady(project, updateGroup)
+	require.NoError(err)
+	assert.False(isMerged)
+	assert.False(merged)
+
+	// Succeeded pipeline
+	headPipeline = `{"id":1,"status":"success"}`
+	isMerged, err = platform.MergeIfReady(project, updateGroup)
+	require.NoError(err)
+	assert.True(isMerged)
+	assert.True(merged)
+
+	// Without a pipeline
+	headPipeline = `null`
+	isMerged, err = platform.MergeIfReady(project, updateGroup)
+	require.NoError(err)
+	assert.True(isMerged)
+}
@@ -0,0 +1,17 @@
+package platforms
+
+import (
+	"github.com/roemer/gonovate/pkg/common"
+)
+
+// Optional capability of platforms that can mark PRs/MRs of branches which were modified by someone else.
+type IModifiedBranchPlatform interface {
+	// Adds the configured label and/or comment to the PR/MR of the update group.
+	MarkModified(project *common.Project, updateGroup *common.UpdateGroup) error
+}
+
+// A hidden marker which is added to the comment on PRs/MRs of modified branches.
+const ModifiedBranchMarker = "<!-- gonovate-modified-branch -->"
+
+// The comment which is added to PRs/MRs of modified branches.
+const modifiedBranchComment = ModifiedBranchMarker + "\nThis branch was modified by someone else, so gonovate stopped updating it. Delete the branch to let gonovate recreate it."
@@ -40,6 +40,10 @@ func (p *NoopPlatform) IsNewOrChanged(updateGroup *common.UpdateGroup) (bool, er
 	return true, nil
 }
 
+func (p *NoopPlatform) IsModified(updateGroup *common.UpdateGroup) (bool, error) {
+	return false, nil
+}
+
 func (p *NoopPlatform) PublishChanges(updateGroup *common.UpdateGroup) error {
 	return nil
 }
@@ -1,8 +1,11 @@
 package platforms
 
 import (
+	"fmt"
 	"log/slog"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/roemer/gonovate/pkg/common"
 	"github.com/stretchr/testify/assert"
@@ -11,7 +14,19 @@ import (
 func TestGetCorrectPlatform(t *testing.T) {
 	assert := assert.New(t)
 
-	platform, err := GetPlatform(&common.PlatformSettings{Logger: slog.Default(), Platform: common.PLATFORM_TYPE_GIT})
+	platform, err := GetPlatform(&common.PlatformSettings{Logger: slog.Default(), Platform: common.PLATFORM_TYPE_AZURE_DEVOPS})
+	assert.NoError(err)
+	assert.NotNil(platform)
+	assert.Equal(common.PLATFORM_TYPE_AZURE_DEVOPS, platform.Type())
+	assert.IsType(&AzureDevOpsPlatform{}, platform)
+
+	platform, err = GetPlatform(&common.PlatformSettings{Logger: slog.Default(), Platform: common.PLATFORM_TYPE_BITBUCKET_SERVER})
+	assert.NoError(err)
+	assert.NotNil(platform)
+	assert.Equal(common.PLATFORM_TYPE_BITBUCKET_SERVER, platform.Type())
+	assert.IsType(&BitbucketServerPlatform{}, platform)
+
+	platform, err = GetPlatform(&common.PlatformSettings{Logger: slog.Default(), Platform: common.PLATFORM_TYPE_GIT})
 	assert.NoError(err)
 	assert.NotNil(platform)
 	assert.Equal(common.PLATFORM_TYPE_GIT, platform.Type())
@@ -41,3 +56,39 @@ func TestGetCorrectPlatform(t *testing.T) {
 	assert.Equal(common.PLATFORM_TYPE_NOOP, platform.Type())
 	assert.IsType(&NoopPlatform{}, platform)
 }
+
+func TestBuildLimitedPullRequestBody(t *testing.T) {
+	assert := assert.New(t)
+
+	updateGroup := &common.UpdateGroup{
+		Body: strings.Repeat("ä", 5000),
+		Dependencies: []*common.DependencyWithUpdate{
+			{Dependency: &common.Dependency{Name: "golang", Version: "1.23.0", FilePath: "Dockerfile"}, NewRelease: &common.ReleaseInfo{VersionString: "1.24.1"}},
+		},
+	}
+	platform := newPlatformBase(&common.PlatformSettings{Logger: slog.Default()})
+	content := platform.buildLimitedPullRequestBody(updateGroup, 4000)
+	assert.Equal(4000, utf8.RuneCountInString(content))
+	assert.True(strings.HasSuffix(content, BuildUpdateMarker(updateGroup.Dependencies[0])))
+
+	updateGroup.Body = "Short"
+	assert.Equal(buildPullRequestBody(updateGroup), platform.buildLimitedPullRequestBody(updateGroup, 4000))
+
+	// The markers which do not fit are dropped
+	for i := range 100 {
+		updateGroup.Dependencies = append(updateGroup.Dependencies, &common.DependencyWithUpdate{
+			Dependency: &common.Dependency{Name: fmt.Sprintf("dependency-%d", i), Version: "1.0.0", FilePath: "Dockerfile"},
+			NewRelease: &common.ReleaseInfo{VersionString: "2.0.0"},
+		})
+	}
+	content = platform.buildLimitedPullRequestBody(updateGroup, 4000)
+	assert.LessOrEqual(utf8.RuneCountInString(content), 4000)
+	assert.Contains(content, BuildUpdateMarker(updateGroup.Dependencies[0]))
+	assert.NotContains(content, BuildUpdateMarker(updateGroup.Dependencies[100]))
+
+	// The markers are added within the limit of the platform
+	updateGroup.Body = strings.Repeat("a", 70000)
+	content = platform.buildLimitedPullRequestBody(updateGroup, gitHubMaxBodyLength)
+	assert.LessOrEqual(utf8.RuneCountInString(content), gitHubMaxBodyLength)
+	assert.Contains(content, BuildUpdateMarker(updateGroup.Dependencies[100]))
+}
@@ -0,0 +1,59 @@
+package platforms
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Limits the number of PRs/MRs that are created, based on the already existing PRs/MRs.
+type PullRequestLimiter struct {
+	concurrentLimit int
+	hourlyLimit     int
+	openBranches    []string
+	openCount       int
+	lastHourCount   int
+}
+
+// Creates a limiter from the existing PRs/MRs (including closed ones for the hourly limit). A limit of 0 means no limit.
+func NewPullRequestLimiter(pullRequests []*PullRequestInfo, concurrentLimit int, hourlyLimit int, now time.Time) *PullRequestLimiter {
+	limiter := &PullRequestLimiter{
+		concurrentLimit: concurrentLimit,
+		hourlyLimit:     hourlyLimit,
+	}
+	for _, pullRequest := range pullRequests {
+		if pullRequest.State == PULL_REQUEST_STATE_OPEN {
+			limiter.openBranches = append(limiter.openBranches, pullRequest.BranchName)
+			limiter.openCount++
+		}
+		if !pullRequest.CreatedAt.IsZero() && now.Sub(pullRequest.CreatedAt) < time.Hour {
+			limiter.lastHourCount++
+		}
+	}
+	return limiter
+}
+
+// Checks if changes for the given branch can be published. Branches with an open PR/MR can always be updated.
+// Returns the reason if the branch cannot be published.
+func (l *PullRequestLimiter) CanPublish(branchName string) (bool, string) {
+	if slices.Contains(l.openBranches, branchName) {
+		return true, ""
+	}
+	if l.concurrentLimit > 0 && l.openCount >= l.concurrentLimit {
+		return false, fmt.Sprintf("concurrent limit of %d open PRs/MRs reached", l.concurrentLimit)
+	}
+	if l.hourlyLimit > 0 && l.lastHourCount >= l.hourlyLimit {
+		return false, fmt.Sprintf("hourly limit of %d created PRs/MRs reached", l.hourlyLimit)
+	}
+	return true, ""
+}
+
+// Registers that changes for the given branch were published. Counts a new PR/MR if there was none open for the branch.
+func (l *PullRequestLimiter) Published(branchName string) {
+	if slices.Contains(l.openBranches, branchName) {
+		return
+	}
+	l.openBranches = append(l.openBranches, branchName)
+	l.openCount++
+	l.lastHourCount++
+}
@@ -0,0 +1,47 @@
+package platforms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullRequestLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	pullRequests := []*PullRequestInfo{
+		{BranchName: "gonovate/a", State: PULL_REQUEST_STATE_OPEN, CreatedAt: now.Add(-48 * time.Hour)},
+		{BranchName: "gonovate/b", State: PULL_REQUEST_STATE_OPEN, CreatedAt: now.Add(-10 * time.Minute)},
+		{BranchName: "gonovate/c", State: PULL_REQUEST_STATE_MERGED, CreatedAt: now.Add(-20 * time.Minute)},
+	}
+
+	// Concurrent limit
+	limiter := NewPullRequestLimiter(pullRequests, 3, 0, now)
+	canPublish, _ := limiter.CanPublish("gonovate/d")
+	assert.True(canPublish)
+	limiter.Published("gonovate/d")
+	canPublish, reason := limiter.CanPublish("gonovate/e")
+	assert.False(canPublish)
+	assert.Contains(reason, "concurrent limit of 3")
+	// Existing ones can still be updated
+	canPublish, _ = limiter.CanPublish("gonovate/a")
+	assert.True(canPublish)
+	canPublish, _ = limiter.CanPublish("gonovate/d")
+	assert.True(canPublish)
+
+	// Hourly limit (counts the closed one as well)
+	limiter = NewPullRequestLimiter(pullRequests, 0, 2, now)
+	canPublish, reason = limiter.CanPublish("gonovate/d")
+	assert.False(canPublish)
+	assert.Contains(reason, "hourly limit of 2")
+
+	// No limits
+	limiter = NewPullRequestLimiter(pullRequests, 0, 0, now)
+	for range 10 {
+		limiter.Published("gonovate/new")
+	}
+	canPublish, _ = limiter.CanPublish("gonovate/other")
+	assert.True(canPublish)
+}
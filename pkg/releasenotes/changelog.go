@@ -0,0 +1,35 @@
+package releasenotes
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Regex for the headings of changelogs which contain a version like "## [1.2.0] - 2024-01-01", "# v1.2.0" or "## Version 1.2.0".
+var changelogHeadingRegex = regexp.MustCompile(`(?m)^#{1,4}[^\S\n]+((?:[A-Za-z-]+[^\S\n]+)?\[?v?(\d+(?:\.\d+)+[0-9A-Za-z.+-]*)\]?.*)$`)
+
+// A section of a changelog file which belongs to a version.
+type changelogSection struct {
+	Version string
+	Title   string
+	Body    string
+}
+
+// Splits the content of a changelog file into the sections of the versions.
+func parseChangelog(content string) []*changelogSection {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	sections := []*changelogSection{}
+	matches := changelogHeadingRegex.FindAllStringSubmatchIndex(content, -1)
+	for i, match := range matches {
+		end := len(content)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		sections = append(sections, &changelogSection{
+			Version: content[match[4]:match[5]],
+			Title:   strings.TrimSpace(content[match[2]:match[3]]),
+			Body:    strings.TrimSpace(content[match[1]:end]),
+		})
+	}
+	return sections
+}
@@ -0,0 +1,76 @@
+package releasenotes
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/roemer/gonovate/pkg/common"
+)
+
+// Gets the latest releases of a Gitea repository.
+func (f *Fetcher) getGiteaReleases(repository *repository) ([]*repositoryRelease, error) {
+	client, err := f.getGiteaClient(repository)
+	if err != nil {
+		return nil, err
+	}
+	owner, name, _ := strings.Cut(repository.path, "/")
+	giteaReleases, _, err := client.ListReleases(owner, name, gitea.ListReleasesOptions{
+		ListOptions: gitea.ListOptions{Page: 1, PageSize: 50},
+		IsDraft:     common.FalsePtr,
+	})
+	if err != nil {
+		return nil, err
+	}
+	releases := []*repositoryRelease{}
+	for _, giteaRelease := range giteaReleases {
+		releases = append(releases, &repositoryRelease{
+			TagName: giteaRelease.TagName,
+			Name:    giteaRelease.Title,
+			Body:    giteaRelease.Note,
+			Url:     giteaRelease.HTMLURL,
+		})
+	}
+	return releases, nil
+}
+
+// Gets the changelog file from the default branch of a Gitea repository.
+func (f *Fetcher) getGiteaChangelog(repository *repository) (string, string, error) {
+	client, err := f.getGiteaClient(repository)
+	if err != nil {
+		return "", "", err
+	}
+	owner, name, _ := strings.Cut(repository.path, "/")
+	fileContent, resp, err := client.GetContents(owner, name, "", changelogFileName)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if fileContent.Content == nil {
+		return "", "", nil
+	}
+	content, err := base64.StdEncoding.DecodeString(*fileContent.Content)
+	if err != nil {
+		return "", "", err
+	}
+	htmlUrl := ""
+	if fileContent.HTMLURL != nil {
+		htmlUrl = *fileContent.HTMLURL
+	}
+	return string(content), htmlUrl, nil
+}
+
+func (f *Fetcher) getGiteaClient(repository *repository) (*gitea.Client, error) {
+	// Skip the version check of the client, it is not needed for reading
+	options := []gitea.ClientOption{gitea.SetGiteaVersion("")}
+	if parsedUrl, err := url.Parse(repository.baseUrl); err == nil {
+		if token := f.getTokenForHost(parsedUrl.Host); token != "" {
+			options = append(options, gitea.SetToken(token))
+		}
+	}
+	return gitea.NewClient(repository.baseUrl, options...)
+}
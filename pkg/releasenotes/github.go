@@ -0,0 +1,79 @@
+package releasenotes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v84/github"
+	"github.com/roemer/gonovate/pkg/common"
+)
+
+// Gets the latest releases of a GitHub repository.
+func (f *Fetcher) getGitHubReleases(repository *repository) ([]*repositoryRelease, error) {
+	client, err := f.getGitHubClient(repository)
+	if err != nil {
+		return nil, err
+	}
+	owner, name, _ := strings.Cut(repository.path, "/")
+	gitHubReleases, _, err := client.Repositories.ListReleases(context.Background(), owner, name, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, err
+	}
+	releases := []*repositoryRelease{}
+	for _, gitHubRelease := range gitHubReleases {
+		if gitHubRelease.GetDraft() {
+			continue
+		}
+		releases = append(releases, &repositoryRelease{
+			TagName: gitHubRelease.GetTagName(),
+			Name:    gitHubRelease.GetName(),
+			Body:    gitHubRelease.GetBody(),
+			Url:     gitHubRelease.GetHTMLURL(),
+		})
+	}
+	return releases, nil
+}
+
+// Gets the changelog file from the default branch of a GitHub repository.
+func (f *Fetcher) getGitHubChangelog(repository *repository) (string, string, error) {
+	client, err := f.getGitHubClient(repository)
+	if err != nil {
+		return "", "", err
+	}
+	owner, name, _ := strings.Cut(repository.path, "/")
+	fileContent, _, resp, err := client.Repositories.GetContents(context.Background(), owner, name, changelogFileName, nil)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if fileContent == nil {
+		return "", "", nil
+	}
+	content, err := fileContent.GetContent()
+	if err != nil {
+		return "", "", err
+	}
+	return content, fileContent.GetHTMLURL(), nil
+}
+
+// Creates the client for github.com or the GitHub Enterprise Server which hosts the repository.
+func (f *Fetcher) getGitHubClient(repository *repository) (*github.Client, error) {
+	if !common.IsPublicGitHub(repository.baseUrl) {
+		return common.NewGitHubClient(repository.baseUrl, f.getTokenForHost(common.GetGitHubApiHost(repository.baseUrl)))
+	}
+	baseUrl, err := url.Parse(f.gitHubApiUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing github api url '%s': %w", f.gitHubApiUrl, err)
+	}
+	client := github.NewClient(nil)
+	client.BaseURL = baseUrl
+	if token := f.getTokenForHost(baseUrl.Host); token != "" {
+		client = client.WithAuthToken(token)
+	}
+	return client, nil
+}
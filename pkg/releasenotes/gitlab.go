@@ -0,0 +1,56 @@
+package releasenotes
+
+import (
+	"net/http"
+	"net/url"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// Gets the latest releases of a GitLab project.
+func (f *Fetcher) getGitLabReleases(repository *repository) ([]*repositoryRelease, error) {
+	client, err := f.getGitLabClient(repository)
+	if err != nil {
+		return nil, err
+	}
+	gitLabReleases, _, err := client.Releases.ListReleases(repository.path, &gitlab.ListReleasesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, err
+	}
+	releases := []*repositoryRelease{}
+	for _, gitLabRelease := range gitLabReleases {
+		releases = append(releases, &repositoryRelease{
+			TagName: gitLabRelease.TagName,
+			Name:    gitLabRelease.Name,
+			Body:    gitLabRelease.Description,
+			Url:     gitLabRelease.Links.Self,
+		})
+	}
+	return releases, nil
+}
+
+// Gets the changelog file from the default branch of a GitLab project.
+func (f *Fetcher) getGitLabChangelog(repository *repository) (string, string, error) {
+	client, err := f.getGitLabClient(repository)
+	if err != nil {
+		return "", "", err
+	}
+	content, resp, err := client.RepositoryFiles.GetRawFile(repository.path, changelogFileName, &gitlab.GetRawFileOptions{Ref: gitlab.Ptr("HEAD")})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return string(content), repository.webUrl() + "/-/blob/HEAD/" + changelogFileName, nil
+}
+
+func (f *Fetcher) getGitLabClient(repository *repository) (*gitlab.Client, error) {
+	token := ""
+	if parsedUrl, err := url.Parse(repository.baseUrl); err == nil {
+		token = f.getTokenForHost(parsedUrl.Host)
+	}
+	return gitlab.NewClient(token, gitlab.WithBaseURL(repository.baseUrl+"/api/v4"))
+}
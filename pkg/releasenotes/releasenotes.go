@@ -0,0 +1,287 @@
+package releasenotes
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/roemer/gonovate/pkg/datasources"
+	"github.com/roemer/gover"
+)
+
+// Fetches the release notes of dependency updates from the source repositories of the dependencies.
+type Fetcher struct {
+	logger    *slog.Logger
+	hostRules []*common.HostRule
+	// The datasources by their type, so their cached data is reused for all dependencies.
+	datasources map[common.DatasourceType]common.IDatasource
+	// The url of the GitHub api, can be changed for tests.
+	gitHubApiUrl string
+}
+
+// A release of a repository as returned by the platforms.
+type repositoryRelease struct {
+	TagName string
+	Name    string
+	Body    string
+	Url     string
+}
+
+// The name of the changelog file which is used if there are no release notes.
+const changelogFileName = "CHANGELOG.md"
+
+// The maximum number of release notes that are collected for an update.
+const maxReleaseNotes = 20
+
+// The platforms of the datasources which can also be self-hosted, so the platform cannot be detected by the host.
+var datasourcePlatforms = map[common.DatasourceType]platformType{
+	common.DATASOURCE_TYPE_GITHUB_RELEASES: platformGitHub,
+	common.DATASOURCE_TYPE_GITHUB_TAGS:     platformGitHub,
+	common.DATASOURCE_TYPE_GITEA_RELEASES:  platformGitea,
+	common.DATASOURCE_TYPE_GITLAB_PACKAGES: platformGitLab,
+}
+
+func NewFetcher(logger *slog.Logger, hostRules []*common.HostRule) *Fetcher {
+	return &Fetcher{
+		logger:       logger,
+		hostRules:    hostRules,
+		datasources:  map[common.DatasourceType]common.IDatasource{},
+		gitHubApiUrl: "https://api.github.com/",
+	}
+}
+
+// Fetches the notes of all releases after the current version of the dependency up to the new release, newest first.
+// The notes are taken from the releases of the source repository or from its changelog file if there are no release notes.
+// Returns nothing if the source repository is unknown or not hosted on a supported platform (GitHub, GitLab or Gitea).
+func (f *Fetcher) Fetch(dependency *common.Dependency, newRelease *common.ReleaseInfo) ([]*common.ReleaseNotes, error) {
+	repository, err := f.findRepository(dependency, newRelease)
+	if err != nil {
+		return nil, fmt.Errorf("failed finding the source repository of '%s': %w", dependency.Name, err)
+	}
+	if repository == nil {
+		f.logger.Debug(fmt.Sprintf("No supported source repository found for '%s'", dependency.Name))
+		return nil, nil
+	}
+	versionRange := newVersionRange(dependency, newRelease)
+
+	// Prefer the notes of the releases
+	releases, err := f.getReleases(repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching the releases of '%s': %w", repository.webUrl(), err)
+	}
+	releaseNotes := []*versionedReleaseNotes{}
+	for _, release := range releases {
+		versionString, version, ok := versionRange.find(release.TagName, release.Name)
+		if !ok || strings.TrimSpace(release.Body) == "" {
+			continue
+		}
+		releaseNotes = append(releaseNotes, &versionedReleaseNotes{version: version, notes: &common.ReleaseNotes{
+			Version: versionString,
+			Title:   cmp.Or(release.Name, release.TagName),
+			Body:    strings.TrimSpace(release.Body),
+			Url:     release.Url,
+		}})
+	}
+
+	// Fall back to the changelog file
+	if len(releaseNotes) == 0 {
+		changelog, changelogUrl, err := f.getChangelog(repository)
+		if err != nil {
+			return nil, fmt.Errorf("failed fetching the changelog of '%s': %w", repository.webUrl(), err)
+		}
+		for _, section := range parseChangelog(changelog) {
+			versionString, version, ok := versionRange.find(section.Version)
+			if !ok || section.Body == "" {
+				continue
+			}
+			releaseNotes = append(releaseNotes, &versionedReleaseNotes{version: version, notes: &common.ReleaseNotes{
+				Version: versionString,
+				Title:   section.Title,
+				Body:    section.Body,
+				Url:     changelogUrl,
+			}})
+		}
+	}
+
+	if len(releaseNotes) == 0 {
+		f.logger.Debug(fmt.Sprintf("No release notes found for '%s' up to version '%s'", dependency.Name, newRelease.VersionString))
+		return nil, nil
+	}
+	// Sort them newest first (versions that cannot be compared are the new version) and only keep the latest ones
+	slices.SortStableFunc(releaseNotes, func(a, b *versionedReleaseNotes) int {
+		if a.version == nil || b.version == nil {
+			return cmp.Compare(boolToInt(a.version != nil), boolToInt(b.version != nil))
+		}
+		return b.version.CompareTo(a.version)
+	})
+	releaseNotes = slices.CompactFunc(releaseNotes, func(a, b *versionedReleaseNotes) bool { return a.notes.Version == b.notes.Version })
+	result := []*common.ReleaseNotes{}
+	for _, entry := range releaseNotes[:min(len(releaseNotes), maxReleaseNotes)] {
+		result = append(result, entry.notes)
+	}
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////
+// Internal
+////////////////////////////////////////////////////////////
+
+// Release notes with their parsed version for sorting.
+type versionedReleaseNotes struct {
+	version *gover.Version
+	notes   *common.ReleaseNotes
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// Finds the source repository with the datasource of the dependency.
+func (f *Fetcher) findRepository(dependency *common.Dependency, newRelease *common.ReleaseInfo) (*repository, error) {
+	datasource, ok := f.datasources[dependency.Datasource]
+	if !ok {
+		var err error
+		datasource, err = datasources.GetDatasource(dependency.Datasource, &common.DatasourceSettings{
+			Logger:    f.logger,
+			HostRules: f.hostRules,
+		})
+		if err != nil {
+			return nil, err
+		}
+		f.datasources[dependency.Datasource] = datasource
+	}
+	sourceUrlDatasource, ok := datasource.(common.ISourceUrlDatasource)
+	if !ok {
+		return nil, nil
+	}
+	sourceUrl, err := sourceUrlDatasource.GetSourceUrl(dependency, newRelease)
+	if err != nil || sourceUrl == "" {
+		return nil, err
+	}
+	repository := parseRepositoryUrl(sourceUrl, datasourcePlatforms[dependency.Datasource])
+	if repository == nil || repository.platform == "" {
+		return nil, nil
+	}
+	return repository, nil
+}
+
+func (f *Fetcher) getReleases(repository *repository) ([]*repositoryRelease, error) {
+	switch repository.platform {
+	case platformGitHub:
+		return f.getGitHubReleases(repository)
+	case platformGitLab:
+		return f.getGitLabReleases(repository)
+	case platformGitea:
+		return f.getGiteaReleases(repository)
+	}
+	return nil, fmt.Errorf("unsupported platform '%s'", repository.platform)
+}
+
+// Gets the content and url of the changelog file of the repository. Both are empty if there is no such file.
+func (f *Fetcher) getChangelog(repository *repository) (string, string, error) {
+	switch repository.platform {
+	case platformGitHub:
+		return f.getGitHubChangelog(repository)
+	case platformGitLab:
+		return f.getGitLabChangelog(repository)
+	case platformGitea:
+		return f.getGiteaChangelog(repository)
+	}
+	return "", "", fmt.Errorf("unsupported platform '%s'", repository.platform)
+}
+
+// Returns the host rule which matches the given host exactly or as a subdomain. The hosts come from the metadata of
+// packages, so a partial match is not enough as it would leak the tokens to lookalike hosts.
+func (f *Fetcher) getHostRuleForHost(host string) *common.HostRule {
+	for _, hostRule := range f.hostRules {
+		if hostMatches(host, hostRule.MatchHost) {
+			return hostRule
+		}
+	}
+	return nil
+}
+
+// Checks if the host (with an optional port) is the given host or a subdomain of it.
+func hostMatches(host string, matchHost string) bool {
+	host = strings.ToLower(host)
+	matchHost = strings.ToLower(strings.TrimSuffix(matchHost, "."))
+	if matchHost == "" {
+		return false
+	}
+	if host == matchHost {
+		return true
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	return host == matchHost || strings.HasSuffix(host, "."+matchHost)
+}
+
+// Returns the token of the host rule for the given host or an empty string if there is none.
+func (f *Fetcher) getTokenForHost(host string) string {
+	if hostRule := f.getHostRuleForHost(host); hostRule != nil {
+		return hostRule.TokenExpanded()
+	}
+	return ""
+}
+
+// The versions after the current version up to the new version of a dependency.
+type versionRange struct {
+	versionRegex   *regexp.Regexp
+	currentVersion *gover.Version
+	newVersion     *gover.Version
+	newVersionRaw  string
+}
+
+func newVersionRange(dependency *common.Dependency, newRelease *common.ReleaseInfo) *versionRange {
+	versionRange := &versionRange{newVersionRaw: newRelease.VersionString}
+	if dependency.Versioning != "" {
+		versionRange.versionRegex, _ = regexp.Compile(dependency.Versioning)
+	}
+	versionRange.currentVersion = versionRange.parse(dependency.Version)
+	versionRange.newVersion = versionRange.parse(newRelease.VersionString)
+	return versionRange
+}
+
+// Parses a version with the versioning of the dependency or as a simple version (with an optional "v" prefix).
+// Returns nil if the value is no version.
+func (r *versionRange) parse(value string) *gover.Version {
+	if r.versionRegex != nil {
+		if version, err := gover.ParseVersionFromRegex(value, r.versionRegex); err == nil {
+			return version
+		}
+	}
+	if version, err := gover.ParseVersionFromRegex(strings.TrimPrefix(value, "v"), gover.RegexpSimple); err == nil {
+		return version
+	}
+	return nil
+}
+
+// Returns the first of the values (without "v" prefix) which is a version within the range and its parsed version.
+// If the versions cannot be compared, only the new version itself is within the range.
+func (r *versionRange) find(values ...string) (string, *gover.Version, bool) {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		if strings.TrimPrefix(value, "v") == strings.TrimPrefix(r.newVersionRaw, "v") {
+			return strings.TrimPrefix(value, "v"), r.newVersion, true
+		}
+		if r.currentVersion == nil || r.newVersion == nil {
+			continue
+		}
+		version := r.parse(value)
+		if version != nil && version.CompareTo(r.currentVersion) > 0 && version.CompareTo(r.newVersion) <= 0 {
+			return strings.TrimPrefix(value, "v"), version, true
+		}
+	}
+	return "", nil, false
+}
@@ -0,0 +1,193 @@
+package releasenotes
+
+import (
+	"encoding/base64"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/roemer/gonovate/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetch(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	changelog := "# Changelog\n\n## [2.1.0] - 2025-02-01\n\n### Added\n- Feature B\n\n## [2.0.0] - 2025-01-01\n\n- Breaking\n\n## [1.0.0]\n\n- Initial\n"
+	mux := http.NewServeMux()
+	// GitHub
+	mux.HandleFunc("GET /repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("Bearer token", r.Header.Get("Authorization"))
+		w.Write([]byte(`[
+			{"tag_name":"v1.3.0","name":"","body":"- Feature C","html_url":"https://github.com/owner/repo/releases/tag/v1.3.0"},
+			{"tag_name":"v1.2.0","name":"Release 1.2.0","body":"- Feature B\n","html_url":"https://github.com/owner/repo/releases/tag/v1.2.0"},
+			{"tag_name":"v1.1.0","name":"","body":"- Feature A","html_url":"https://github.com/owner/repo/releases/tag/v1.1.0"},
+			{"tag_name":"v1.0.0","name":"","body":"- Initial","html_url":"https://github.com/owner/repo/releases/tag/v1.0.0"}
+		]`))
+	})
+	mux.HandleFunc("GET /repos/owner/chart/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("GET /repos/owner/chart/contents/CHANGELOG.md", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"type":"file","encoding":"base64","content":"` + base64.StdEncoding.EncodeToString([]byte(changelog)) + `","html_url":"https://github.com/owner/chart/blob/main/CHANGELOG.md"}`))
+	})
+	// GitHub Enterprise Server
+	mux.HandleFunc("GET /api/v3/repos/owner/internal/releases", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("Bearer token", r.Header.Get("Authorization"))
+		w.Write([]byte(`[{"tag_name":"v3.0.0","name":"","body":"- Internal","html_url":"https://ghe.example/owner/internal/releases/tag/v3.0.0"}]`))
+	})
+	// npm
+	npmDownloads := 0
+	mux.HandleFunc("GET /npm/package", func(w http.ResponseWriter, r *http.Request) {
+		npmDownloads++
+		w.Write([]byte(`{"repository":{"type":"git","url":"git+https://github.com/owner/repo.git"},"versions":{}}`))
+	})
+	mux.HandleFunc("GET /npm/unknown", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"repository":["https://github.com/owner/repo"],"versions":{}}`))
+	})
+	// Docker
+	mux.HandleFunc("GET /v2/image/manifests/1.2.0", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"schemaVersion":2,"manifests":[
+			{"digest":"sha256:attestation","platform":{"os":"unknown","architecture":"unknown"}},
+			{"digest":"sha256:amd64","platform":{"os":"linux","architecture":"amd64"}}
+		]}`))
+	})
+	mux.HandleFunc("GET /v2/image/manifests/sha256:amd64", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"schemaVersion":2,"config":{"digest":"sha256:config"}}`))
+	})
+	mux.HandleFunc("GET /v2/image/blobs/sha256:config", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"config":{"Labels":{"org.opencontainers.image.source":"https://github.com/owner/repo"}}}`))
+	})
+	// Helm
+	mux.HandleFunc("GET /helm/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("entries:\n  chart:\n    - name: chart\n      version: 2.1.0\n      sources:\n        - https://github.com/owner/chart\n"))
+	})
+	// Gitea
+	mux.HandleFunc("GET /gitea/api/v1/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"tag_name":"2.0.0","name":"","body":"Gitea notes","html_url":"https://gitea.example/owner/repo/releases/tag/2.0.0"}]`))
+	})
+	// GitLab
+	mux.HandleFunc("GET /api/v4/projects/group%2Fsub%2Fproject/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("GET /api/v4/projects/group%2Fsub%2Fproject/repository/files/CHANGELOG.md/raw", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"404 File Not Found"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fetcher := NewFetcher(slog.New(slog.DiscardHandler), []*common.HostRule{{MatchHost: "127.0.0.1", Token: "token"}})
+	fetcher.gitHubApiUrl = server.URL + "/"
+
+	// GitHub, all releases after the current version, newest first
+	notes, err := fetcher.Fetch(&common.Dependency{Name: "owner/repo", Version: "1.0.0", Datasource: common.DATASOURCE_TYPE_GITHUB_TAGS}, &common.ReleaseInfo{VersionString: "v1.2.0"})
+	require.NoError(err)
+	assert.Equal([]*common.ReleaseNotes{
+		{Version: "1.2.0", Title: "Release 1.2.0", Body: "- Feature B", Url: "https://github.com/owner/repo/releases/tag/v1.2.0"},
+		{Version: "1.1.0", Title: "v1.1.0", Body: "- Feature A", Url: "https://github.com/owner/repo/releases/tag/v1.1.0"},
+	}, notes)
+
+	// GitHub Enterprise Server from the registry url
+	notes, err = fetcher.Fetch(&common.Dependency{Name: "owner/internal", Version: "2.0.0", Datasource: common.DATASOURCE_TYPE_GITHUB_RELEASES, RegistryUrls: []string{server.URL + "/api/v3"}}, &common.ReleaseInfo{VersionString: "v3.0.0"})
+	require.NoError(err)
+	require.Len(notes, 1)
+	assert.Equal("- Internal", notes[0].Body)
+
+	// npm with the repository of the package
+	notes, err = fetcher.Fetch(&common.Dependency{Name: "package", Version: "1.2.0", Datasource: common.DATASOURCE_TYPE_NPM, RegistryUrls: []string{server.URL + "/npm"}}, &common.ReleaseInfo{VersionString: "1.3.0"})
+	require.NoError(err)
+	require.Len(notes, 1)
+	assert.Equal("- Feature C", notes[0].Body)
+
+	// npm reuses the downloaded index file for other dependencies of the package
+	notes, err = fetcher.Fetch(&common.Dependency{Name: "package", Version: "1.1.0", Datasource: common.DATASOURCE_TYPE_NPM, RegistryUrls: []string{server.URL + "/npm"}}, &common.ReleaseInfo{VersionString: "1.3.0"})
+	require.NoError(err)
+	assert.Len(notes, 2)
+	assert.Equal(1, npmDownloads)
+
+	// npm with an unknown form of the repository
+	notes, err = fetcher.Fetch(&common.Dependency{Name: "unknown", Version: "1.0.0", Datasource: common.DATASOURCE_TYPE_NPM, RegistryUrls: []string{server.URL + "/npm"}}, &common.ReleaseInfo{VersionString: "2.0.0"})
+	require.NoError(err)
+	assert.Empty(notes)
+
+	// Docker with the source label of the image
+	notes, err = fetcher.Fetch(&common.Dependency{Name: "image", Version: "1.1.0", Datasource: common.DATASOURCE_TYPE_DOCKER, RegistryUrls: []string{server.URL}}, &common.ReleaseInfo{VersionString: "1.2.0"})
+	require.NoError(err)
+	require.Len(notes, 1)
+	assert.Equal("1.2.0", notes[0].Version)
+
+	// Helm with the sources of the chart, falls back to the changelog
+	notes, err = fetcher.Fetch(&common.Dependency{Name: "chart", Version: "1.0.0", Datasource: common.DATASOURCE_TYPE_HELM, RegistryUrls: []string{server.URL + "/helm"}}, &common.ReleaseInfo{VersionString: "2.1.0"})
+	require.NoError(err)
+	assert.Equal([]*common.ReleaseNotes{
+		{Version: "2.1.0", Title: "[2.1.0] - 2025-02-01", Body: "### Added\n- Feature B", Url: "https://github.com/owner/chart/blob/main/CHANGELOG.md"},
+		{Version: "2.0.0", Title: "[2.0.0] - 2025-01-01", Body: "- Breaking", Url: "https://github.com/owner/chart/blob/main/CHANGELOG.md"},
+	}, notes)
+
+	// Gitea on a self-hosted instance
+	notes, err = fetcher.Fetch(&common.Dependency{Name: "owner/repo", Version: "1.0.0", Datasource: common.DATASOURCE_TYPE_GITEA_RELEASES, RegistryUrls: []string{server.URL + "/gitea"}}, &common.ReleaseInfo{VersionString: "2.0.0"})
+	require.NoError(err)
+	require.Len(notes, 1)
+	assert.Equal(&common.ReleaseNotes{Version: "2.0.0", Title: "2.0.0", Body: "Gitea notes", Url: "https://gitea.example/owner/repo/releases/tag/2.0.0"}, notes[0])
+
+	// GitLab without releases and changelog
+	notes, err = fetcher.Fetch(&common.Dependency{Name: "group/sub/project:package", Version: "1.0.0", Datasource: common.DATASOURCE_TYPE_GITLAB_PACKAGES, RegistryUrls: []string{server.URL + "/api/v4"}}, &common.ReleaseInfo{VersionString: "2.0.0"})
+	require.NoError(err)
+	assert.Empty(notes)
+
+	// Datasource without a source repository
+	notes, err = fetcher.Fetch(&common.Dependency{Name: "go", Version: "1.24.0", Datasource: common.DATASOURCE_TYPE_GOVERSION}, &common.ReleaseInfo{VersionString: "1.24.1"})
+	require.NoError(err)
+	assert.Empty(notes)
+}
+
+func TestGetTokenForHost(t *testing.T) {
+	assert := assert.New(t)
+
+	fetcher := NewFetcher(slog.New(slog.DiscardHandler), []*common.HostRule{{MatchHost: "github.com", Token: "token"}, {MatchHost: "ghe.example:8443", Token: "ghe"}})
+	assert.Equal("token", fetcher.getTokenForHost("github.com"))
+	assert.Equal("token", fetcher.getTokenForHost("api.github.com"))
+	assert.Equal("token", fetcher.getTokenForHost("GitHub.com:443"))
+	assert.Equal("ghe", fetcher.getTokenForHost("ghe.example:8443"))
+	// Lookalike hosts from the metadata of packages do not get the token
+	assert.Empty(fetcher.getTokenForHost("github.com.attacker.example"))
+	assert.Empty(fetcher.getTokenForHost("api.github.com.attacker.example"))
+	assert.Empty(fetcher.getTokenForHost("evilgithub.com"))
+	assert.Empty(fetcher.getTokenForHost("ghe.example"))
+}
+
+func TestParseRepositoryUrl(t *testing.T) {
+	assert := assert.New(t)
+
+	for rawUrl, expected := range map[string]*repository{
+		"git+https://github.com/owner/repo.git":              {platform: platformGitHub, baseUrl: "https://github.com", path: "owner/repo"},
+		"git@github.com:owner/repo.git":                      {platform: platformGitHub, baseUrl: "https://github.com", path: "owner/repo"},
+		"git+ssh://git@github.com/owner/repo.git":            {platform: platformGitHub, baseUrl: "https://github.com", path: "owner/repo"},
+		"https://github.com/owner/repo/tree/main/packages/x": {platform: platformGitHub, baseUrl: "https://github.com", path: "owner/repo"},
+		"owner/repo":           {platform: platformGitHub, baseUrl: "https://github.com", path: "owner/repo"},
+		"gitlab:group/project": {platform: platformGitLab, baseUrl: "https://gitlab.com", path: "group/project"},
+		"https://gitlab.com/group/sub/project/-/tree/main": {platform: platformGitLab, baseUrl: "https://gitlab.com", path: "group/sub/project"},
+		"https://codeberg.org/owner/repo":                  {platform: platformGitea, baseUrl: "https://codeberg.org", path: "owner/repo"},
+		"https://github.example.com/owner/repo":            {platform: platformGitHub, baseUrl: "https://github.example.com", path: "owner/repo"},
+		"https://git.example.com/owner/repo":               {platform: "", baseUrl: "https://git.example.com", path: "owner/repo"},
+	} {
+		assert.Equal(expected, parseRepositoryUrl(rawUrl, ""), rawUrl)
+	}
+	assert.Equal(&repository{platform: platformGitLab, baseUrl: "https://git.example.com", path: "group/sub/project"}, parseRepositoryUrl("https://git.example.com/group/sub/project", platformGitLab))
+	assert.Nil(parseRepositoryUrl("https://github.com/owner", ""))
+	assert.Nil(parseRepositoryUrl("not a url", ""))
+}
+
+func TestParseChangelog(t *testing.T) {
+	assert := assert.New(t)
+
+	sections := parseChangelog("# Changelog\r\n\r\n## v1.1.0 (2025-01-01)\r\n- Fix\r\n\r\n## Version 1.0.0\r\n\r\nInitial\r\n")
+	assert.Equal([]*changelogSection{
+		{Version: "1.1.0", Title: "v1.1.0 (2025-01-01)", Body: "- Fix"},
+		{Version: "1.0.0", Title: "Version 1.0.0", Body: "Initial"},
+	}, sections)
+}
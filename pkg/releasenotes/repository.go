@@ -0,0 +1,111 @@
+package releasenotes
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// The platforms that can host source repositories.
+type platformType string
+
+const (
+	platformGitHub platformType = "github"
+	platformGitLab platformType = "gitlab"
+	platformGitea  platformType = "gitea"
+)
+
+// A source repository on a platform.
+type repository struct {
+	// The platform which hosts the repository. Is empty if the platform is not known.
+	platform platformType
+	// The url of the platform like "https://github.com".
+	baseUrl string
+	// The path of the repository like "owner/repository".
+	path string
+}
+
+// Regex for shorthands like "owner/repository" or "gitlab:group/project" (used by npm).
+var repositoryShorthandRegex = regexp.MustCompile(`^(?:(github|gitlab):)?([\w.-]+/[\w.-]+)$`)
+
+// Regex for scp-like urls of git like "git@github.com:owner/repository.git".
+var scpUrlRegex = regexp.MustCompile(`^[\w.-]+@([\w.-]+):(.+)$`)
+
+// Parses the url of a repository like "git+https://github.com/owner/repository.git" or "git@github.com:owner/repository.git".
+// The platform is detected by the host unless a known platform is given. Returns nil if it is not a valid url.
+func parseRepositoryUrl(rawUrl string, knownPlatform platformType) *repository {
+	rawUrl = strings.TrimSpace(rawUrl)
+	if match := repositoryShorthandRegex.FindStringSubmatch(rawUrl); match != nil {
+		host := "github.com"
+		if match[1] == "gitlab" {
+			host = "gitlab.com"
+		}
+		rawUrl = "https://" + host + "/" + match[2]
+	}
+	rawUrl = strings.TrimPrefix(rawUrl, "git+")
+	if match := scpUrlRegex.FindStringSubmatch(rawUrl); match != nil {
+		rawUrl = "https://" + match[1] + "/" + match[2]
+	}
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil || parsedUrl.Host == "" {
+		return nil
+	}
+	// Repositories are always accessed with https, except for explicit http urls
+	host := parsedUrl.Host
+	scheme := parsedUrl.Scheme
+	if scheme != "http" && scheme != "https" {
+		scheme = "https"
+		host = parsedUrl.Hostname()
+	}
+
+	// Remove everything that points into the repository (like "/tree/main" or "/-/blob/main")
+	path := strings.Trim(parsedUrl.Path, "/")
+	path, _, _ = strings.Cut(path, "/-/")
+	path = strings.TrimSuffix(path, ".git")
+	baseUrl := scheme + "://" + host
+	platform := knownPlatform
+	if platform == "" {
+		platform = detectPlatform(host)
+	}
+	if platform != platformGitLab {
+		parts := strings.Split(path, "/")
+		if len(parts) < 2 {
+			return nil
+		}
+		if platform == platformGitea && knownPlatform != "" {
+			// The url comes from the datasource and points exactly to the repository, so the instance can be in a sub path
+			if len(parts) > 2 {
+				baseUrl += "/" + strings.Join(parts[:len(parts)-2], "/")
+			}
+			parts = parts[len(parts)-2:]
+		}
+		path = parts[0] + "/" + strings.TrimSuffix(parts[1], ".git")
+	}
+	if path == "" {
+		return nil
+	}
+	return &repository{
+		platform: platform,
+		baseUrl:  baseUrl,
+		path:     path,
+	}
+}
+
+// Returns the url of the repository in the browser.
+func (r *repository) webUrl() string {
+	return r.baseUrl + "/" + r.path
+}
+
+// Detects the platform of well-known hosts.
+func detectPlatform(host string) platformType {
+	host = strings.ToLower(host)
+	switch {
+	case host == "github.com" || host == "www.github.com" || strings.HasPrefix(host, "github."):
+		return platformGitHub
+	case host == "gitlab.com" || strings.Contains(host, "gitlab"):
+		return platformGitLab
+	case host == "gitea.com" || host == "codeberg.org" || strings.Contains(host, "gitea") || strings.Contains(host, "forgejo"):
+		return platformGitea
+	}
+	return ""
+}